@@ -1,6 +1,6 @@
 module github.com/luverolla/lexgo
 
-go 1.21.1
+go 1.22
 
 retract v0.1.0 // accidentally published
 
@@ -0,0 +1,264 @@
+package algo
+
+import (
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// The generators are lazy: every call to Next computes the following value
+// from the previous one, so the whole output is never kept in memory.
+// Every value is a fresh list, made by cloning the given one, so it can be
+// kept or modified without affecting the others.
+
+// Returns all the permutations of the given list, generated with Heap's
+// algorithm, which swaps one pair of elements at every step
+// The first permutation is the list itself. Equal elements are not
+// recognized, so there are always n! permutations
+func Permutations[T any](list tau.List[T]) tau.Iterator[tau.List[T]] {
+	data := toSlice[T](list)
+	c := make([]int, len(data))
+	started, i := false, 0
+	return &generator[tau.List[T]]{func() (tau.List[T], bool) {
+		if !started {
+			started = true
+			return fresh(list, data), true
+		}
+		for i < len(data) {
+			if c[i] < i {
+				if i%2 == 0 {
+					data[0], data[i] = data[i], data[0]
+				} else {
+					data[c[i]], data[i] = data[i], data[c[i]]
+				}
+				c[i]++
+				i = 0
+				return fresh(list, data), true
+			}
+			c[i] = 0
+			i++
+		}
+		return nil, false
+	}}
+}
+
+// Rearranges the given collection into the next permutation in
+// lexicographic order, according to the given comparator
+// If it is the last one, the collection is sorted in ascending order and
+// false is returned. Starting from a sorted collection, it goes through all
+// the distinct permutations
+func NextPermutation[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) bool {
+	data := toSlice(coll)
+	i := len(data) - 2
+	for i >= 0 && cmp(data[i], data[i+1]) >= 0 {
+		i--
+	}
+	if i >= 0 {
+		j := len(data) - 1
+		for cmp(data[j], data[i]) <= 0 {
+			j--
+		}
+		data[i], data[j] = data[j], data[i]
+	}
+	for l, r := i+1, len(data)-1; l < r; l, r = l+1, r-1 {
+		data[l], data[r] = data[r], data[l]
+	}
+	writeBack(coll, data)
+	return i >= 0
+}
+
+// Returns all the combinations of k elements of the given list, in
+// lexicographic order of their positions
+// There are none if k is negative or greater than the size of the list
+func Combinations[T any](list tau.List[T], k int) tau.Iterator[tau.List[T]] {
+	data := toSlice[T](list)
+	n := len(data)
+	return combinations(list, data, k, k >= 0 && k <= n, func(idx []int) bool {
+		// the last position that can still be moved forward
+		i := k - 1
+		for i >= 0 && idx[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+		return true
+	}, func(idx []int) {
+		for i := range idx {
+			idx[i] = i
+		}
+	})
+}
+
+// Returns all the combinations of k elements of the given list, where every
+// element can be taken more than once, in lexicographic order of their positions
+// There are none if k is negative, or if the list is empty and k is positive
+func CombinationsWithReplacement[T any](list tau.List[T], k int) tau.Iterator[tau.List[T]] {
+	data := toSlice[T](list)
+	n := len(data)
+	return combinations(list, data, k, k == 0 || (k > 0 && n > 0), func(idx []int) bool {
+		i := k - 1
+		for i >= 0 && idx[i] == n-1 {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[i]
+		}
+		return true
+	}, func([]int) {})
+}
+
+// Returns all the subsets of the given list, by increasing size, and the
+// ones of the same size as [Combinations] does
+// The elements of every subset are in the same order as in the list
+func PowerSet[T any](list tau.List[T]) tau.Iterator[tau.List[T]] {
+	size := 0
+	current := Combinations(list, 0)
+	return &generator[tau.List[T]]{func() (tau.List[T], bool) {
+		for {
+			if next, ok := current.Next(); ok {
+				return *next, true
+			}
+			if size == list.Size() {
+				return nil, false
+			}
+			size++
+			current = Combinations(list, size)
+		}
+	}}
+}
+
+// Returns all the lists made of one element of every given list, in the
+// same order as the lists, like the digits of an odometer
+// The values are clones of the first list. There are none if a list is
+// empty or if no list is given
+func CartesianProduct[T any](lists ...tau.List[T]) tau.Iterator[tau.List[T]] {
+	data := make([][]T, len(lists))
+	done := len(lists) == 0
+	for i, list := range lists {
+		data[i] = toSlice[T](list)
+		done = done || len(data[i]) == 0
+	}
+	idx := make([]int, len(lists))
+	tuple := make([]T, len(lists))
+	started := false
+	return &generator[tau.List[T]]{func() (tau.List[T], bool) {
+		if done {
+			return nil, false
+		}
+		if started {
+			i := len(idx) - 1
+			for i >= 0 && idx[i] == len(data[i])-1 {
+				idx[i] = 0
+				i--
+			}
+			if i < 0 {
+				done = true
+				return nil, false
+			}
+			idx[i]++
+		}
+		started = true
+		for i, j := range idx {
+			tuple[i] = data[i][j]
+		}
+		return fresh(lists[0], tuple), true
+	}}
+}
+
+// Returns all the ways of writing n as a sum of positive integers, ignoring
+// their order, as lists of non-increasing parts, in reverse lexicographic order
+// For example, the partitions of 4 are [4], [3 1], [2 2], [2 1 1], [1 1 1 1]
+// The values are clones of the given list, which is usually empty. There
+// is one empty partition of 0, and none of negative numbers
+func Partitions(n int, list tau.List[int]) tau.Iterator[tau.List[int]] {
+	parts := []int{n}
+	if n == 0 {
+		parts = []int{}
+	}
+	started, done := false, n < 0
+	return &generator[tau.List[int]]{func() (tau.List[int], bool) {
+		if done {
+			return nil, false
+		}
+		if started {
+			// the last part greater than 1 is decreased, and the rest is
+			// split in parts not greater than it
+			i := len(parts) - 1
+			for i >= 0 && parts[i] == 1 {
+				i--
+			}
+			if i < 0 {
+				done = true
+				return nil, false
+			}
+			rest, part := parts[i]+len(parts)-1-i, parts[i]-1
+			parts = parts[:i]
+			for ; rest >= part; rest -= part {
+				parts = append(parts, part)
+			}
+			if rest > 0 {
+				parts = append(parts, rest)
+			}
+		}
+		started = true
+		return fresh(list, parts), true
+	}}
+}
+
+// --- Private helpers ---
+
+// iterator over the values of a function
+type generator[T any] struct {
+	next func() (T, bool)
+}
+
+func (g *generator[T]) Next() (*T, bool) {
+	value, ok := g.next()
+	if !ok {
+		return nil, false
+	}
+	return &value, true
+}
+
+func (g *generator[T]) Each(f func(T)) {
+	for next, ok := g.Next(); ok; next, ok = g.Next() {
+		f(*next)
+	}
+}
+
+// returns a clone of the given list with the given elements
+func fresh[T any](list tau.List[T], data []T) tau.List[T] {
+	copy := list.Clone().(tau.List[T])
+	writeBack[T](copy, data)
+	return copy
+}
+
+// iterates over the k positions of data, if there's any combination,
+// starting from the ones set by first and moving on with advance
+func combinations[T any](list tau.List[T], data []T, k int, exist bool, advance func([]int) bool, first func([]int)) tau.Iterator[tau.List[T]] {
+	idx := make([]int, max(k, 0))
+	first(idx)
+	chosen := make([]T, len(idx))
+	started, done := false, !exist
+	return &generator[tau.List[T]]{func() (tau.List[T], bool) {
+		if done {
+			return nil, false
+		}
+		if started && !advance(idx) {
+			done = true
+			return nil, false
+		}
+		started = true
+		for i, j := range idx {
+			chosen[i] = data[j]
+		}
+		return fresh(list, chosen), true
+	}}
+}
@@ -0,0 +1,440 @@
+package algo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// --- Patches ---
+
+// Run of consecutive elements that are kept, deleted or inserted
+// A and B are the positions of the run in the old and in the new sequence.
+// For a deletion, B is where the new sequence continues, and for an
+// insertion, A is where the old one does
+type Hunk[T any] struct {
+	// one of EditKeep, EditDelete and EditInsert
+	Op     EditOp
+	A      int
+	B      int
+	Values []T
+}
+
+// Differences between two sequences, as returned by [Diff]
+// Within every changed region, the deletion comes before the insertion
+type Patch[T any] struct {
+	Hunks []Hunk[T]
+	// Comparator the patch was made with, used by [Apply]. Nil for [tau.Eq]
+	Cmp tau.Comparator[T]
+}
+
+// Options of [DiffWith]
+// The zero value gives the defaults
+type DiffOptions[T any] struct {
+	// Comparator whose zero means equal, [tau.Eq] if nil
+	Cmp tau.Comparator[T]
+	// Whether to use the patience algorithm, which matches first the
+	// elements that occur once in both sequences, and then diffs the
+	// regions between them with the Myers algorithm. It often gives more
+	// readable patches. It needs hashable elements, see [tau.Hash]
+	Patience bool
+}
+
+// Returns the differences between the two collections, using the Myers
+// algorithm and comparing the elements with [tau.Eq]
+// The patch is minimal, and it takes O((n + m) d) time, where d is the
+// number of inserted and deleted elements
+func Diff[T any](a, b tau.IdxedColl[T]) Patch[T] {
+	return DiffWith(a, b, DiffOptions[T]{})
+}
+
+// Returns the differences between the two collections, with the given options
+func DiffWith[T any](a, b tau.IdxedColl[T], opts DiffOptions[T]) Patch[T] {
+	d := &differ[T]{x: toSlice(a), y: toSlice(b), eq: func(v, w T) bool { return tau.Eq(v, w) }}
+	if opts.Cmp != nil {
+		d.eq = func(v, w T) bool { return opts.Cmp(v, w) == 0 }
+	}
+	d.edits = make([]Edit, 0, max(len(d.x), len(d.y)))
+	if opts.Patience {
+		d.patience(0, len(d.x), 0, len(d.y))
+	} else {
+		d.myers(0, len(d.x), 0, len(d.y))
+	}
+	patch := d.patch()
+	patch.Cmp = opts.Cmp
+	return patch
+}
+
+// Applies the patch to a copy of the given list and returns it
+// Returns an error if the kept and deleted elements, compared with the
+// comparator of the patch, or [tau.Eq] if it has none, are not the ones of the list
+func Apply[T any](patch Patch[T], list tau.List[T]) (tau.List[T], error) {
+	return ApplyWith(patch, list, patch.Cmp)
+}
+
+// Same as [Apply], but the elements are compared with the given comparator,
+// or with [tau.Eq] if it's nil
+func ApplyWith[T any](patch Patch[T], list tau.List[T], cmp tau.Comparator[T]) (tau.List[T], error) {
+	eq := func(v, w T) bool { return tau.Eq(v, w) }
+	if cmp != nil {
+		eq = func(v, w T) bool { return cmp(v, w) == 0 }
+	}
+	src := toSlice[T](list)
+	result := make([]T, 0, len(src))
+	i := 0
+	for _, hunk := range patch.Hunks {
+		if hunk.A != i {
+			return nil, errs.Mismatch(fmt.Sprintf("hunk at %d", i), fmt.Sprintf("hunk at %d", hunk.A))
+		}
+		if hunk.Op == EditInsert {
+			result = append(result, hunk.Values...)
+			continue
+		}
+		if i+len(hunk.Values) > len(src) {
+			return nil, errs.Mismatch(hunk.Values, src[i:])
+		}
+		for k, value := range hunk.Values {
+			if !eq(value, src[i+k]) {
+				return nil, errs.Mismatch(value, src[i+k])
+			}
+		}
+		if hunk.Op == EditKeep {
+			result = append(result, src[i:i+len(hunk.Values)]...)
+		}
+		i += len(hunk.Values)
+	}
+	if i != len(src) {
+		return nil, errs.Mismatch(fmt.Sprintf("%d elements", i), fmt.Sprintf("%d elements", len(src)))
+	}
+	copy := list.Clone().(tau.List[T])
+	writeBack[T](copy, result)
+	return copy, nil
+}
+
+// Returns the patch that undoes the given one
+func Invert[T any](patch Patch[T]) Patch[T] {
+	hunks := make([]Hunk[T], len(patch.Hunks))
+	for i, hunk := range patch.Hunks {
+		switch hunk.Op {
+		case EditInsert:
+			hunk.Op = EditDelete
+		case EditDelete:
+			hunk.Op = EditInsert
+		}
+		hunks[i] = hunk
+	}
+	// keeps the deletions before the insertions
+	for i := 1; i < len(hunks); i++ {
+		if hunks[i-1].Op == EditInsert && hunks[i].Op == EditDelete {
+			hunks[i-1], hunks[i] = hunks[i], hunks[i-1]
+		}
+	}
+	locate(hunks)
+	return Patch[T]{hunks, patch.Cmp}
+}
+
+// Checks if the patch has no insertions and deletions
+func (p Patch[T]) Empty() bool {
+	for _, hunk := range p.Hunks {
+		if hunk.Op != EditKeep {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the patch in the unified diff format, with up to 3 elements of
+// context around the changes, one element per line
+// Returns an empty string if there are no changes
+func (p Patch[T]) String() string {
+	type line struct {
+		op    EditOp
+		value T
+	}
+	lines := make([]line, 0)
+	for _, hunk := range p.Hunks {
+		for _, value := range hunk.Values {
+			lines = append(lines, line{hunk.Op, value})
+		}
+	}
+	var sb strings.Builder
+	// positions, in a and b, of the next line
+	a, b := 0, 0
+	for lo := 0; lo < len(lines); {
+		// finds the next change and the group of changes close to it
+		first := lo
+		for first < len(lines) && lines[first].op == EditKeep {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		start := max(lo, first-diffContext)
+		end, kept := first, 0
+		for end < len(lines) && kept <= 2*diffContext {
+			if lines[end].op == EditKeep {
+				kept++
+			} else {
+				kept = 0
+			}
+			end++
+		}
+		end -= max(kept-diffContext, 0)
+		// the lines skipped before the group are all kept
+		a, b = a+start-lo, b+start-lo
+		oldLen, newLen := 0, 0
+		for _, l := range lines[start:end] {
+			if l.op != EditInsert {
+				oldLen++
+			}
+			if l.op != EditDelete {
+				newLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(a, oldLen), hunkRange(b, newLen))
+		for _, l := range lines[start:end] {
+			prefix := " "
+			switch l.op {
+			case EditDelete:
+				prefix = "-"
+			case EditInsert:
+				prefix = "+"
+			}
+			fmt.Fprintf(&sb, "%s%v\n", prefix, l.value)
+		}
+		a, b = a+oldLen, b+newLen
+		lo = end
+	}
+	return sb.String()
+}
+
+// --- Private helpers ---
+
+// number of kept elements shown around the changes
+const diffContext = 3
+
+// range of a hunk header, 1-based, where an empty range names the line before
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	} else if length == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+type differ[T any] struct {
+	x, y  []T
+	eq    func(T, T) bool
+	edits []Edit
+}
+
+// appends the edits turning x[xlo:xhi] into y[ylo:yhi], with the Myers algorithm
+func (d *differ[T]) myers(xlo, xhi, ylo, yhi int) {
+	// common prefix and suffix are kept directly
+	for xlo < xhi && ylo < yhi && d.eq(d.x[xlo], d.y[ylo]) {
+		d.edits = append(d.edits, Edit{EditKeep, xlo, ylo})
+		xlo, ylo = xlo+1, ylo+1
+	}
+	suffix := 0
+	for xlo < xhi-suffix && ylo < yhi-suffix && d.eq(d.x[xhi-suffix-1], d.y[yhi-suffix-1]) {
+		suffix++
+	}
+	xhi, yhi = xhi-suffix, yhi-suffix
+	n, m := xhi-xlo, yhi-ylo
+	offset := n + m + 1
+	// v[offset+k] is the furthest x reached on diagonal k = x - y
+	v := make([]int, 2*offset+1)
+	// trace[steps] holds v on diagonals [-steps, steps] before that step,
+	// all the walk back needs, so it takes O(d^2) space for d edits
+	trace := make([][]int, 0)
+	found := n == 0 && m == 0
+	for steps := 0; !found; steps++ {
+		trace = append(trace, append([]int(nil), v[offset-steps:offset+steps+1]...))
+		for k := -steps; k <= steps; k += 2 {
+			var x int
+			if k == -steps || (k != steps && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.eq(d.x[xlo+x], d.y[ylo+y]) {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	// walks back through the trace, collecting the edits in reverse
+	region := make([]Edit, 0, n+m)
+	x, y := n, m
+	for steps := len(trace) - 1; steps >= 0; steps-- {
+		// the first step starts from the origin
+		prevX, prevY := 0, 0
+		if steps > 0 {
+			prev := trace[steps]
+			k := x - y
+			var prevK int
+			if k == -steps || (k != steps && prev[steps+k-1] < prev[steps+k+1]) {
+				prevK = k + 1
+			} else {
+				prevK = k - 1
+			}
+			prevX = prev[steps+prevK]
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			region = append(region, Edit{EditKeep, xlo + x, ylo + y})
+		}
+		if steps > 0 {
+			if x == prevX {
+				region = append(region, Edit{EditInsert, xlo + x, ylo + prevY})
+			} else {
+				region = append(region, Edit{EditDelete, xlo + prevX, ylo + y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i := len(region) - 1; i >= 0; i-- {
+		d.edits = append(d.edits, region[i])
+	}
+	for i := 0; i < suffix; i++ {
+		d.edits = append(d.edits, Edit{EditKeep, xhi + i, yhi + i})
+	}
+}
+
+// appends the edits turning x[xlo:xhi] into y[ylo:yhi], with the patience algorithm
+func (d *differ[T]) patience(xlo, xhi, ylo, yhi int) {
+	anchors := d.uniqueMatches(xlo, xhi, ylo, yhi)
+	if len(anchors) == 0 {
+		d.myers(xlo, xhi, ylo, yhi)
+		return
+	}
+	for _, anchor := range anchors {
+		d.patience(xlo, anchor[0], ylo, anchor[1])
+		d.edits = append(d.edits, Edit{EditKeep, anchor[0], anchor[1]})
+		xlo, ylo = anchor[0]+1, anchor[1]+1
+	}
+	d.patience(xlo, xhi, ylo, yhi)
+}
+
+// returns the longest increasing sequence of pairs of equal elements that
+// occur once in both ranges
+func (d *differ[T]) uniqueMatches(xlo, xhi, ylo, yhi int) [][2]int {
+	type occurrence struct {
+		value  T
+		xCount int
+		yCount int
+		xPos   int
+		yPos   int
+	}
+	buckets := make(map[uint32][]*occurrence)
+	find := func(value T) *occurrence {
+		h := tau.Hash(value)
+		for _, occ := range buckets[h] {
+			if d.eq(occ.value, value) {
+				return occ
+			}
+		}
+		occ := &occurrence{value: value}
+		buckets[h] = append(buckets[h], occ)
+		return occ
+	}
+	for i := xlo; i < xhi; i++ {
+		occ := find(d.x[i])
+		occ.xCount, occ.xPos = occ.xCount+1, i
+	}
+	for j := ylo; j < yhi; j++ {
+		occ := find(d.y[j])
+		occ.yCount, occ.yPos = occ.yCount+1, j
+	}
+	// pairs ordered by position in x
+	pairs := make([][2]int, 0)
+	for i := xlo; i < xhi; i++ {
+		if occ := find(d.x[i]); occ.xCount == 1 && occ.yCount == 1 {
+			pairs = append(pairs, [2]int{i, occ.yPos})
+		}
+	}
+	// longest increasing subsequence by position in y, with patience sorting:
+	// tops[p] is the pair ending the best sequence of length p+1
+	tops := make([]int, 0)
+	prev := make([]int, len(pairs))
+	for i, pair := range pairs {
+		p := sliceBound(tops, func(top int) bool { return pairs[top][1] < pair[1] })
+		if p > 0 {
+			prev[i] = tops[p-1]
+		} else {
+			prev[i] = -1
+		}
+		if p == len(tops) {
+			tops = append(tops, i)
+		} else {
+			tops[p] = i
+		}
+	}
+	anchors := make([][2]int, len(tops))
+	if len(tops) == 0 {
+		return anchors
+	}
+	for p, i := len(tops)-1, tops[len(tops)-1]; p >= 0; p, i = p-1, prev[i] {
+		anchors[p] = pairs[i]
+	}
+	return anchors
+}
+
+// groups the edits in hunks, putting deletions before insertions
+func (d *differ[T]) patch() Patch[T] {
+	hunks := make([]Hunk[T], 0)
+	add := func(op EditOp, value T) {
+		if last := len(hunks) - 1; last >= 0 && hunks[last].Op == op {
+			hunks[last].Values = append(hunks[last].Values, value)
+			return
+		}
+		hunks = append(hunks, Hunk[T]{Op: op, Values: []T{value}})
+	}
+	for lo := 0; lo < len(d.edits); {
+		if e := d.edits[lo]; e.Op == EditKeep {
+			add(EditKeep, d.x[e.A])
+			lo++
+			continue
+		}
+		hi := lo
+		for hi < len(d.edits) && d.edits[hi].Op != EditKeep {
+			hi++
+		}
+		for _, e := range d.edits[lo:hi] {
+			if e.Op == EditDelete {
+				add(EditDelete, d.x[e.A])
+			}
+		}
+		for _, e := range d.edits[lo:hi] {
+			if e.Op == EditInsert {
+				add(EditInsert, d.y[e.B])
+			}
+		}
+		lo = hi
+	}
+	locate(hunks)
+	return Patch[T]{Hunks: hunks}
+}
+
+// sets the positions of the hunks, which follow one another
+func locate[T any](hunks []Hunk[T]) {
+	a, b := 0, 0
+	for i := range hunks {
+		hunks[i].A, hunks[i].B = a, b
+		if hunks[i].Op != EditInsert {
+			a += len(hunks[i].Values)
+		}
+		if hunks[i].Op != EditDelete {
+			b += len(hunks[i].Values)
+		}
+	}
+}
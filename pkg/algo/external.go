@@ -0,0 +1,406 @@
+package algo
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// --- Encoding ---
+
+// Writes values to a stream, one after the other
+type Encoder[T any] interface {
+	Encode(T) error
+}
+
+// Reads the values written by the matching [Encoder]
+// Decode returns [io.EOF] when there are no more values
+type Decoder[T any] interface {
+	Decode() (T, error)
+}
+
+// Way of storing values in files, used by [ExternalSort]
+type Codec[T any] interface {
+	NewEncoder(io.Writer) Encoder[T]
+	NewDecoder(io.Reader) Decoder[T]
+}
+
+// Returns a codec using [encoding/gob], which works with most types
+func GobCodec[T any]() Codec[T] {
+	return gobCodec[T]{}
+}
+
+// Returns a codec writing every string on its own line
+// The strings must not contain newlines
+func LineCodec[T ~string]() Codec[T] {
+	return lineCodec[T]{}
+}
+
+// --- External sort ---
+
+// Options of [ExternalSort]
+// The zero value gives the defaults
+type ExternalOptions[T any] struct {
+	// Codec of the temporary files, [GobCodec] if nil
+	Codec Codec[T]
+	// Maximum number of values kept in memory, and so length of the sorted
+	// runs written to the temporary files, 1 << 20 if not positive
+	RunSize int
+	// Maximum number of runs merged at once, and so of files open at the
+	// same time, 64 if less than 2
+	FanIn int
+	// Directory of the temporary files, [os.TempDir] if empty
+	TempDir string
+}
+
+// Sorts the values of the given iterator using the given comparator,
+// keeping only a bounded number of them in memory
+//
+// Values are read in runs of opts.RunSize, which are sorted with TimSort
+// and written to temporary files, then the runs are merged with a heap.
+// The sort is stable, so the order is the same as [MergeSort]. If all the
+// values fit in a single run, no file is written.
+//
+// The temporary files are removed when the returned iterator is exhausted
+// or closed. Returns an error, after removing the files, if a file can't
+// be written or read back
+func ExternalSort[T any](iter tau.Iterator[T], cmp tau.Comparator[T], opts ExternalOptions[T]) (*ExternalIterator[T], error) {
+	opts = opts.withDefaults()
+	es := &externalSorter[T]{opts, cmp, make([]string, 0)}
+	run := make([]T, 0, min(opts.RunSize, 1<<16))
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		run = append(run, *next)
+		if len(run) == opts.RunSize {
+			if err := es.spill(run); err != nil {
+				return nil, es.fail(err)
+			}
+			run = run[:0]
+		}
+	}
+	if len(es.runs) == 0 {
+		TimSortSlice(run, cmp)
+		return &ExternalIterator[T]{memory: run}, nil
+	}
+	if len(run) > 0 {
+		if err := es.spill(run); err != nil {
+			return nil, es.fail(err)
+		}
+	}
+	// merges groups of consecutive runs, which keeps the sort stable
+	for len(es.runs) > opts.FanIn {
+		merged := make([]string, 0, len(es.runs)/opts.FanIn+1)
+		for lo := 0; lo < len(es.runs); lo += opts.FanIn {
+			group := es.runs[lo:min(lo+opts.FanIn, len(es.runs))]
+			path, err := es.mergeToFile(group)
+			if err != nil {
+				es.runs = append(es.runs[lo:], merged...)
+				return nil, es.fail(err)
+			}
+			merged = append(merged, path)
+		}
+		es.runs = merged
+	}
+	merger, err := newRunMerger(es.runs, cmp, opts.Codec)
+	if err != nil {
+		return nil, es.fail(err)
+	}
+	return &ExternalIterator[T]{merger: merger}, nil
+}
+
+// Iterator over the values sorted by [ExternalSort]
+type ExternalIterator[T any] struct {
+	// values, if they all fit in memory
+	memory []T
+	pos    int
+	merger *runMerger[T]
+}
+
+func (iter *ExternalIterator[T]) Next() (*T, bool) {
+	if iter.merger == nil {
+		if iter.pos == len(iter.memory) {
+			return nil, false
+		}
+		iter.pos++
+		return &iter.memory[iter.pos-1], true
+	}
+	return iter.merger.next()
+}
+
+func (iter *ExternalIterator[T]) Each(f func(T)) {
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		f(*next)
+	}
+}
+
+// Returns the error that stopped the iteration early, if any
+func (iter *ExternalIterator[T]) Err() error {
+	if iter.merger == nil {
+		return nil
+	}
+	return iter.merger.err
+}
+
+// Stops the iteration and removes the temporary files
+// It's safe to call it more than once
+func (iter *ExternalIterator[T]) Close() error {
+	iter.memory, iter.pos = nil, 0
+	if iter.merger == nil {
+		return nil
+	}
+	return iter.merger.close()
+}
+
+// --- Private ---
+
+func (opts ExternalOptions[T]) withDefaults() ExternalOptions[T] {
+	if opts.Codec == nil {
+		opts.Codec = GobCodec[T]()
+	}
+	if opts.RunSize <= 0 {
+		opts.RunSize = 1 << 20
+	}
+	if opts.FanIn < 2 {
+		opts.FanIn = 64
+	}
+	if opts.TempDir == "" {
+		opts.TempDir = os.TempDir()
+	}
+	return opts
+}
+
+type externalSorter[T any] struct {
+	opts ExternalOptions[T]
+	cmp  tau.Comparator[T]
+	// temporary files of the runs, in input order
+	runs []string
+}
+
+// sorts the run and writes it to a new temporary file
+func (es *externalSorter[T]) spill(run []T) error {
+	TimSortSlice(run, es.cmp)
+	i := 0
+	return es.writeRun(func() (*T, bool) {
+		if i == len(run) {
+			return nil, false
+		}
+		i++
+		return &run[i-1], true
+	})
+}
+
+// merges the given runs into a new temporary file, removing them
+func (es *externalSorter[T]) mergeToFile(group []string) (string, error) {
+	merger, err := newRunMerger(group, es.cmp, es.opts.Codec)
+	if err != nil {
+		return "", err
+	}
+	defer merger.close()
+	if err := es.writeRun(merger.next); err != nil {
+		return "", err
+	}
+	if merger.err != nil {
+		return "", merger.err
+	}
+	path := es.runs[len(es.runs)-1]
+	es.runs = es.runs[:len(es.runs)-1]
+	return path, nil
+}
+
+// writes the given values to a new temporary file, added to the runs
+func (es *externalSorter[T]) writeRun(next func() (*T, bool)) error {
+	file, err := os.CreateTemp(es.opts.TempDir, "lexgo-sort-*.run")
+	if err != nil {
+		return err
+	}
+	es.runs = append(es.runs, file.Name())
+	writer := bufio.NewWriter(file)
+	encoder := es.opts.Codec.NewEncoder(writer)
+	for value, ok := next(); ok; value, ok = next() {
+		if err := encoder.Encode(*value); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// removes the temporary files and returns the given error
+func (es *externalSorter[T]) fail(err error) error {
+	for _, path := range es.runs {
+		os.Remove(path)
+	}
+	es.runs = nil
+	return err
+}
+
+// value read from a run, waiting in the heap
+type runHead[T any] struct {
+	value T
+	run   int
+}
+
+// k-way merge of sorted runs, stored in files
+type runMerger[T any] struct {
+	cmp      tau.Comparator[runHead[T]]
+	paths    []string
+	files    []*os.File
+	decoders []Decoder[T]
+	// min-heap of the next value of every run
+	heap   []runHead[T]
+	err    error
+	closed bool
+}
+
+func newRunMerger[T any](paths []string, cmp tau.Comparator[T], codec Codec[T]) (*runMerger[T], error) {
+	rm := &runMerger[T]{
+		// reversed, as the heap helpers build max-heaps, with ties broken
+		// by run so that the merge is stable
+		cmp: func(a, b runHead[T]) int {
+			if c := cmp(b.value, a.value); c != 0 {
+				return c
+			}
+			return b.run - a.run
+		},
+		paths: paths,
+		heap:  make([]runHead[T], 0, len(paths)),
+	}
+	for run, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			rm.close()
+			return nil, err
+		}
+		rm.files = append(rm.files, file)
+		rm.decoders = append(rm.decoders, codec.NewDecoder(bufio.NewReader(file)))
+		if err := rm.pull(run); err != nil {
+			rm.close()
+			return nil, err
+		}
+	}
+	return rm, nil
+}
+
+// reads the next value of the given run into the heap
+func (rm *runMerger[T]) pull(run int) error {
+	value, err := rm.decoders[run].Decode()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	rm.heap = append(rm.heap, runHead[T]{value, run})
+	siftUp(rm.heap, rm.cmp, len(rm.heap)-1)
+	return nil
+}
+
+func (rm *runMerger[T]) next() (*T, bool) {
+	if rm.closed || len(rm.heap) == 0 {
+		rm.close()
+		return nil, false
+	}
+	head := rm.heap[0]
+	last := len(rm.heap) - 1
+	rm.heap[0] = rm.heap[last]
+	rm.heap = rm.heap[:last]
+	siftDown(rm.heap, rm.cmp, len(rm.heap), 0)
+	if err := rm.pull(head.run); err != nil {
+		rm.err = err
+		rm.close()
+		return nil, false
+	}
+	return &head.value, true
+}
+
+// closes and removes all the files
+func (rm *runMerger[T]) close() error {
+	if rm.closed {
+		return nil
+	}
+	rm.closed = true
+	rm.heap = nil
+	var first error
+	for _, file := range rm.files {
+		if err := file.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, path := range rm.paths {
+		if err := os.Remove(path); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+type gobCodec[T any] struct{}
+
+type gobEncoder[T any] struct {
+	enc *gob.Encoder
+}
+
+type gobDecoder[T any] struct {
+	dec *gob.Decoder
+}
+
+func (gobCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return gobEncoder[T]{gob.NewEncoder(w)}
+}
+
+func (gobCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return gobDecoder[T]{gob.NewDecoder(r)}
+}
+
+func (enc gobEncoder[T]) Encode(value T) error {
+	return enc.enc.Encode(value)
+}
+
+func (dec gobDecoder[T]) Decode() (T, error) {
+	var value T
+	err := dec.dec.Decode(&value)
+	return value, err
+}
+
+type lineCodec[T ~string] struct{}
+
+type lineEncoder[T ~string] struct {
+	w io.Writer
+}
+
+type lineDecoder[T ~string] struct {
+	r *bufio.Reader
+}
+
+func (lineCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return lineEncoder[T]{w}
+}
+
+func (lineCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return lineDecoder[T]{bufio.NewReader(r)}
+}
+
+func (enc lineEncoder[T]) Encode(value T) error {
+	_, err := io.WriteString(enc.w, string(value)+"\n")
+	return err
+}
+
+func (dec lineDecoder[T]) Decode() (T, error) {
+	line, err := dec.r.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			// last line without newline
+			return T(line), nil
+		}
+		return T(""), err
+	}
+	return T(strings.TrimSuffix(line, "\n")), nil
+}
@@ -0,0 +1,175 @@
+package algo
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// The parallel algorithms run on at most the given number of goroutines,
+// or on [runtime.GOMAXPROCS] of them if the number is not positive.
+// They stop as soon as possible when the context is done, returning its
+// error and leaving the collection unchanged.
+
+// Sorts a copy of the given collection using the given comparator and a
+// parallel MergeSort
+// The sort is stable, so the result is the same as the one of [TimSort]
+func ParallelSort[T any](ctx context.Context, coll tau.IdxedColl[T], cmp tau.Comparator[T], workers int) (tau.IdxedColl[T], error) {
+	copy := coll.Clone().(tau.IdxedColl[T])
+	if err := ParallelSortInPlace(ctx, copy, cmp, workers); err != nil {
+		return nil, err
+	}
+	return copy, nil
+}
+
+// Sorts the given collection using the given comparator and a parallel MergeSort
+func ParallelSortInPlace[T any](ctx context.Context, coll tau.IdxedColl[T], cmp tau.Comparator[T], workers int) error {
+	data := toSlice(coll)
+	if err := ParallelSortSlice(ctx, data, cmp, workers); err != nil {
+		return err
+	}
+	writeBack(coll, data)
+	return nil
+}
+
+// Sorts the given slice using the given comparator and a parallel MergeSort
+// The slice is halved until the halves are short enough to be sorted with
+// [TimSortSlice], and the halves are sorted concurrently while goroutines
+// are available. If the context is done, the slice is left in an unspecified order
+func ParallelSortSlice[T any](ctx context.Context, data []T, cmp tau.Comparator[T], workers int) error {
+	ps := &parallelSorter[T]{ctx, cmp, make(chan struct{}, budget(workers)-1)}
+	ps.sort(data, make([]T, len(data)))
+	return ctx.Err()
+}
+
+// Reduces the given collection to a single value, in parallel
+//
+// The collection is split in contiguous chunks, each one reduced with
+// accumulate starting from the identity, and the chunk results are merged
+// in order with combine. So, the result is the same as the sequential
+// reduction if combine is associative and identity is its identity element
+func Reduce[T any, R any](ctx context.Context, coll tau.IdxedColl[T], identity R, accumulate func(R, T) R, combine func(R, R) R, workers int) (R, error) {
+	data := toSlice(coll)
+	bounds := chunkBounds(len(data), workers)
+	partial := make([]R, len(bounds)-1)
+	runChunks(ctx, bounds, func(chunk, lo, hi int) {
+		acc := identity
+		for i := lo; i < hi; i++ {
+			if (i-lo)%cancelCheckInterval == 0 && ctx.Err() != nil {
+				return
+			}
+			acc = accumulate(acc, data[i])
+		}
+		partial[chunk] = acc
+	})
+	if err := ctx.Err(); err != nil {
+		return identity, err
+	}
+	result := identity
+	for _, value := range partial {
+		result = combine(result, value)
+	}
+	return result, nil
+}
+
+// Replaces every element of the given collection with the result of the
+// given function on it, calling the function in parallel
+func MapInPlace[T any](ctx context.Context, coll tau.IdxedColl[T], f func(T) T, workers int) error {
+	data := toSlice(coll)
+	runChunks(ctx, chunkBounds(len(data), workers), func(_, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			if (i-lo)%cancelCheckInterval == 0 && ctx.Err() != nil {
+				return
+			}
+			data[i] = f(data[i])
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	writeBack(coll, data)
+	return nil
+}
+
+// --- Private helpers ---
+
+const (
+	// ranges shorter than this are sorted sequentially
+	parallelCutoff = 1 << 13
+	// number of elements processed between two checks of the context
+	cancelCheckInterval = 1 << 10
+)
+
+// number of goroutines to use
+func budget(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+type parallelSorter[T any] struct {
+	ctx context.Context
+	cmp tau.Comparator[T]
+	// one token for every goroutine that can be started
+	tokens chan struct{}
+}
+
+// sorts data, using buf, of the same length, for the merge
+func (ps *parallelSorter[T]) sort(data, buf []T) {
+	if ps.ctx.Err() != nil {
+		return
+	}
+	if len(data) <= parallelCutoff {
+		TimSortSlice(data, ps.cmp)
+		return
+	}
+	mid := len(data) / 2
+	var wg sync.WaitGroup
+	select {
+	case ps.tokens <- struct{}{}:
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-ps.tokens }()
+			ps.sort(data[:mid], buf[:mid])
+		}()
+	default:
+		ps.sort(data[:mid], buf[:mid])
+	}
+	ps.sort(data[mid:], buf[mid:])
+	wg.Wait()
+	if ps.ctx.Err() != nil {
+		return
+	}
+	merge(data, ps.cmp, buf, 0, mid, len(data))
+}
+
+// splits n elements in at most the given number of chunks, of at least
+// parallelCutoff elements each, returning the bounds of the chunks
+func chunkBounds(n, workers int) []int {
+	chunks := min(budget(workers), max(n/parallelCutoff, 1))
+	bounds := make([]int, chunks+1)
+	for i := range bounds {
+		bounds[i] = i * n / chunks
+	}
+	return bounds
+}
+
+// calls f on every chunk concurrently, unless the context is done
+func runChunks(ctx context.Context, bounds []int, f func(chunk, lo, hi int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < len(bounds)-1; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(chunk int) {
+			defer wg.Done()
+			f(chunk, bounds[chunk], bounds[chunk+1])
+		}(i)
+	}
+	wg.Wait()
+}
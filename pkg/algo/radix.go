@@ -0,0 +1,286 @@
+package algo
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/luverolla/lexgo/pkg/tau"
+	"golang.org/x/exp/constraints"
+)
+
+// The non-comparison sorts look at the values themselves instead of
+// comparing them, so they can beat the O(n log n) bound of the comparison
+// sorts. They sort in ascending order.
+
+// Sorts a copy of the given collection of integers using the LSD RadixSort algorithm
+//
+// The integers are sorted one byte at a time, from the least significant
+// one, skipping the bytes that are the same for all of them. It takes
+// O(w n) time, where w is the size of the integers in bytes
+func RadixSort[T constraints.Integer](coll tau.IdxedColl[T]) tau.IdxedColl[T] {
+	return rearrangeCopy(coll, radixSortInts[T])
+}
+
+// Sorts the given collection of integers using the LSD RadixSort algorithm
+func RadixSortInPlace[T constraints.Integer](coll tau.IdxedColl[T]) {
+	rearrange(coll, radixSortInts[T])
+}
+
+// Sorts a copy of the given collection by the integer key of its elements,
+// using the LSD RadixSort algorithm
+// The sort is stable, and the key function is called once per element
+func RadixSortBy[T any, K constraints.Integer](coll tau.IdxedColl[T], key func(T) K) tau.IdxedColl[T] {
+	return rearrangeCopy(coll, func(data []T) { radixSortBy(data, key) })
+}
+
+// Sorts the given collection by the integer key of its elements, using the
+// LSD RadixSort algorithm
+func RadixSortByInPlace[T any, K constraints.Integer](coll tau.IdxedColl[T], key func(T) K) {
+	rearrange(coll, func(data []T) { radixSortBy(data, key) })
+}
+
+// Sorts a copy of the given collection of strings using the MSD RadixSort algorithm
+//
+// The strings are split in groups by their first byte, then each group by
+// the second byte, and so on. Short groups are sorted with InsertionSort.
+// Strings are ordered byte-wise, like with the < operator
+func RadixSortStrings[T ~string](coll tau.IdxedColl[T]) tau.IdxedColl[T] {
+	return rearrangeCopy(coll, radixSortStrings[T])
+}
+
+// Sorts the given collection of strings using the MSD RadixSort algorithm
+func RadixSortStringsInPlace[T ~string](coll tau.IdxedColl[T]) {
+	rearrange(coll, radixSortStrings[T])
+}
+
+// Sorts a copy of the given collection of integers using the CountingSort algorithm
+//
+// It counts the occurrences of every value between the minimum and the
+// maximum, so it takes O(n + k) time and O(k) memory, where k is the
+// difference between the two. It suits many values in a small range
+// If the range is much larger than the number of values, more than
+// max(2^16, 4n), it falls back to RadixSort to bound the memory
+func CountingSort[T constraints.Integer](coll tau.IdxedColl[T]) tau.IdxedColl[T] {
+	return rearrangeCopy(coll, countingSort[T])
+}
+
+// Sorts the given collection of integers using the CountingSort algorithm
+func CountingSortInPlace[T constraints.Integer](coll tau.IdxedColl[T]) {
+	rearrange(coll, countingSort[T])
+}
+
+// Sorts a copy of the given collection of numbers using the BucketSort algorithm
+//
+// The range between the minimum and the maximum is split in n buckets of the
+// same width, and every bucket is sorted with IntroSort. It takes O(n) time
+// on average if the numbers are uniformly distributed. NaNs are put first,
+// and infinities at the ends. If the range is too wide to be split, like
+// [-MaxFloat64, MaxFloat64], it falls back to IntroSort
+func BucketSort[T tau.Number](coll tau.IdxedColl[T]) tau.IdxedColl[T] {
+	return rearrangeCopy(coll, bucketSort[T])
+}
+
+// Sorts the given collection of numbers using the BucketSort algorithm
+func BucketSortInPlace[T tau.Number](coll tau.IdxedColl[T]) {
+	rearrange(coll, bucketSort[T])
+}
+
+// --- Private helpers ---
+
+// strings groups shorter than this are sorted with InsertionSort
+const stringInsertionThreshold = 32
+
+// minimum range of values for which CountingSort falls back to RadixSort
+const countingMaxSpan = 1 << 16
+
+// maps an integer to an unsigned one with the same order
+func radixKey[K constraints.Integer](value K) uint64 {
+	var zero K
+	key := uint64(value)
+	if zero-1 < zero {
+		// signed: flips the sign bit, so that negative numbers come first
+		key ^= 1 << (8*unsafe.Sizeof(value) - 1)
+	}
+	if width := 8 * unsafe.Sizeof(value); width < 64 {
+		key &= 1<<width - 1
+	}
+	return key
+}
+
+func radixSortInts[T constraints.Integer](data []T) {
+	keys := make([]uint64, len(data))
+	for i, value := range data {
+		keys[i] = radixKey(value)
+	}
+	var zero T
+	lsdRadix(data, keys, int(unsafe.Sizeof(zero)))
+}
+
+func radixSortBy[T any, K constraints.Integer](data []T, key func(T) K) {
+	keys := make([]uint64, len(data))
+	for i, value := range data {
+		keys[i] = radixKey(key(value))
+	}
+	var zero K
+	lsdRadix(data, keys, int(unsafe.Sizeof(zero)))
+}
+
+// stably sorts data by the given keys, of the given number of bytes,
+// with a counting pass for every byte
+func lsdRadix[T any](data []T, keys []uint64, width int) {
+	n := len(data)
+	if n < 2 {
+		return
+	}
+	orig := data
+	bufData, bufKeys := make([]T, n), make([]uint64, n)
+	for shift := 0; shift < 8*width; shift += 8 {
+		var counts [257]int
+		for _, key := range keys {
+			counts[(key>>shift)&0xff+1]++
+		}
+		if counts[(keys[0]>>shift)&0xff+1] == n {
+			// all the keys have the same byte
+			continue
+		}
+		for i := 1; i < len(counts); i++ {
+			counts[i] += counts[i-1]
+		}
+		for i, key := range keys {
+			pos := &counts[(key>>shift)&0xff]
+			bufData[*pos], bufKeys[*pos] = data[i], key
+			*pos++
+		}
+		data, bufData = bufData, data
+		keys, bufKeys = bufKeys, keys
+	}
+	if &data[0] != &orig[0] {
+		// an odd number of passes left the result in the buffer
+		copy(orig, data)
+	}
+}
+
+func radixSortStrings[T ~string](data []T) {
+	msdRadix(data, make([]T, len(data)), 0)
+}
+
+// sorts data, whose strings all share the first depth bytes
+func msdRadix[T ~string](data, buf []T, depth int) {
+	if len(data) < stringInsertionThreshold {
+		insertionSortRange(data, tau.ASCmp[T], 0, len(data))
+		return
+	}
+	// bucket 0 is for the strings that end at depth, bucket b+1 for byte b
+	var counts [258]int
+	for _, s := range data {
+		counts[byteAt(s, depth)+1]++
+	}
+	for i := 1; i < len(counts); i++ {
+		counts[i] += counts[i-1]
+	}
+	starts := counts
+	for _, s := range data {
+		pos := &counts[byteAt(s, depth)]
+		buf[*pos] = s
+		*pos++
+	}
+	copy(data, buf)
+	for b := 1; b < 257; b++ {
+		if lo, hi := starts[b], starts[b+1]; hi-lo > 1 {
+			msdRadix(data[lo:hi], buf[lo:hi], depth+1)
+		}
+	}
+}
+
+// bucket of the string at the given depth: 0 if it ends before, else the byte plus 1
+func byteAt[T ~string](s T, depth int) int {
+	if depth < len(s) {
+		return int(s[depth]) + 1
+	}
+	return 0
+}
+
+func countingSort[T constraints.Integer](data []T) {
+	if len(data) == 0 {
+		return
+	}
+	lo, hi := data[0], data[0]
+	for _, value := range data {
+		lo, hi = min(lo, value), max(hi, value)
+	}
+	// computed on unsigned keys, so that it can't overflow
+	span := radixKey(hi) - radixKey(lo)
+	if span > uint64(max(countingMaxSpan, 4*len(data))) {
+		radixSortInts(data)
+		return
+	}
+	counts := make([]int, span+1)
+	for _, value := range data {
+		counts[radixKey(value)-radixKey(lo)]++
+	}
+	i := 0
+	for offset, count := range counts {
+		for ; count > 0; count-- {
+			data[i] = lo + T(offset)
+			i++
+		}
+	}
+}
+
+func bucketSort[T tau.Number](data []T) {
+	// NaNs and -Inf go first, in this order, and +Inf last
+	data = data[moveFirst(data, func(v T) bool { return v != v }):]
+	data = data[moveFirst(data, func(v T) bool { return math.IsInf(float64(v), -1) }):]
+	data = data[:moveLast(data, func(v T) bool { return math.IsInf(float64(v), 1) })]
+	if len(data) < 2 {
+		return
+	}
+	lo, hi := data[0], data[0]
+	for _, value := range data {
+		lo, hi = min(lo, value), max(hi, value)
+	}
+	width := (float64(hi) - float64(lo)) / float64(len(data))
+	if width == 0 {
+		return
+	}
+	if math.IsInf(width, 0) {
+		// the range overflows
+		IntroSortSlice(data, tau.ASCmp[T])
+		return
+	}
+	buckets := make([][]T, len(data))
+	for _, value := range data {
+		b := max(0, min(int((float64(value)-float64(lo))/width), len(data)-1))
+		buckets[b] = append(buckets[b], value)
+	}
+	i := 0
+	for _, bucket := range buckets {
+		IntroSortSlice(bucket, tau.ASCmp[T])
+		i += copy(data[i:], bucket)
+	}
+}
+
+// moves the values satisfying the predicate to the front, and returns their number
+func moveFirst[T any](data []T, pred func(T) bool) int {
+	n := 0
+	for i, value := range data {
+		if pred(value) {
+			data[i], data[n] = data[n], data[i]
+			n++
+		}
+	}
+	return n
+}
+
+// moves the values satisfying the predicate to the back, and returns the
+// number of the others
+func moveLast[T any](data []T, pred func(T) bool) int {
+	n := len(data)
+	for i := len(data) - 1; i >= 0; i-- {
+		if pred(data[i]) {
+			n--
+			data[i], data[n] = data[n], data[i]
+		}
+	}
+	return n
+}
@@ -0,0 +1,155 @@
+package algo
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// The random algorithms take the source of randomness as a [rand.Rand],
+// so that the results can be reproduced by seeding it, for example with
+// rand.New(rand.NewPCG(1, 2)). If it's nil, the global source is used.
+
+// Shuffles the given collection using the Fisher-Yates algorithm
+// Every permutation is equally likely
+func Shuffle[T any](coll tau.IdxedColl[T], r *rand.Rand) {
+	rearrange(coll, func(data []T) {
+		for i := len(data) - 1; i > 0; i-- {
+			j := RandIntN(r, i+1)
+			data[i], data[j] = data[j], data[i]
+		}
+	})
+}
+
+// Returns k distinct elements of the given collection, chosen at random
+// without replacement, in random order
+// Returns an error if k is not in [0, size]
+func Sample[T any](coll tau.IdxedColl[T], k int, r *rand.Rand) ([]T, error) {
+	if k < 0 || k > coll.Size() {
+		return nil, errs.IndexOutOfRange("algo.Sample", nil, k, coll.Size()+1)
+	}
+	data := toSlice(coll)
+	// the first k steps of Fisher-Yates
+	for i := 0; i < k; i++ {
+		j := i + RandIntN(r, len(data)-i)
+		data[i], data[j] = data[j], data[i]
+	}
+	return append(make([]T, 0, k), data[:k]...), nil
+}
+
+// Returns k values of the given iterator, chosen at random with reservoir
+// sampling, so that every subset of k values is equally likely
+// It keeps only k values at a time, so it suits streams of unknown length
+// If there are less than k values, all of them are returned
+func Reservoir[T any](iter tau.Iterator[T], k int, r *rand.Rand) []T {
+	if k <= 0 {
+		return make([]T, 0)
+	}
+	reservoir := make([]T, 0, k)
+	seen := 0
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		seen++
+		if len(reservoir) < k {
+			reservoir = append(reservoir, *next)
+		} else if j := RandIntN(r, seen); j < k {
+			reservoir[j] = *next
+		}
+	}
+	return reservoir
+}
+
+// --- Weighted sampling ---
+
+// Sampler that picks the elements of a collection with probabilities
+// proportional to their weights, using Vose's alias method
+// Building it takes O(n) time, and every sample takes O(1) time
+type AliasSampler[T any] struct {
+	values []T
+	// probability of keeping the column, instead of taking its alias
+	prob  []float64
+	alias []int
+}
+
+// Creates a new sampler of the elements of the given collection, with the
+// given weights, that are normalized to sum up to 1
+// Panics if the number of weights is not the size of the collection, if a
+// weight is negative or not finite, or if they are all zero
+func Alias[T any](coll tau.IdxedColl[T], weights []float64) *AliasSampler[T] {
+	n := len(weights)
+	if n != coll.Size() {
+		panic(fmt.Sprintf("ERROR: [algo.Alias] %d weights for %d elements", n, coll.Size()))
+	}
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			panic(fmt.Sprintf("ERROR: [algo.Alias] invalid weight %v", w))
+		}
+		total += w
+	}
+	if total == 0 || math.IsInf(total, 0) {
+		panic(fmt.Sprintf("ERROR: [algo.Alias] invalid total weight %v", total))
+	}
+	sampler := &AliasSampler[T]{toSlice(coll), make([]float64, n), make([]int, n)}
+	// columns are split in the ones below and above the average height
+	scaled := make([]float64, n)
+	small, large := make([]int, 0), make([]int, 0)
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	// every short column is filled up with a part of a tall one
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		sampler.prob[s], sampler.alias[s] = scaled[s], l
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// the columns left are full, up to rounding errors
+	for _, i := range append(small, large...) {
+		sampler.prob[i], sampler.alias[i] = 1, i
+	}
+	return sampler
+}
+
+// Returns an element, chosen at random according to the weights
+func (sampler *AliasSampler[T]) Sample(r *rand.Rand) T {
+	i := RandIntN(r, len(sampler.values))
+	if float64N(r) < sampler.prob[i] {
+		return sampler.values[i]
+	}
+	return sampler.values[sampler.alias[i]]
+}
+
+// Returns the number of elements of the sampler
+func (sampler *AliasSampler[T]) Size() int {
+	return len(sampler.values)
+}
+
+// Returns a random int in [0, n), from the given source, or from the
+// global one if r is nil. Panics if n is not positive, like [rand.IntN]
+func RandIntN(r *rand.Rand, n int) int {
+	if r == nil {
+		return rand.IntN(n)
+	}
+	return r.IntN(n)
+}
+
+// --- Private helpers ---
+
+func float64N(r *rand.Rand) float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+	return r.Float64()
+}
@@ -0,0 +1,145 @@
+package algo
+
+import (
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// The searching algorithms require the collection to be sorted by the given
+// comparator, in ascending order. They access elements by index, so they run
+// in O(log n) time on collections with constant-time access, like
+// [list.ArrList], and in O(n log n) time on the others, like [list.LkdList].
+
+// Returns the index of an element equal to the given value, or -1 if there's none
+// If there are more equal elements, any of them may be returned
+func BinarySearch[T any](coll tau.IdxedColl[T], value T, cmp tau.Comparator[T]) int {
+	low, high := 0, coll.Size()-1
+	for low <= high {
+		mid := low + (high-low)/2
+		c := cmp(at(coll, mid), value)
+		if c == 0 {
+			return mid
+		} else if c < 0 {
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	return -1
+}
+
+// Returns the index of the first element not less than the given value,
+// or the size of the collection if there's none
+func LowerBound[T any](coll tau.IdxedColl[T], value T, cmp tau.Comparator[T]) int {
+	return partitionPoint(coll, 0, coll.Size(), func(elem T) bool {
+		return cmp(elem, value) < 0
+	})
+}
+
+// Returns the index of the first element greater than the given value,
+// or the size of the collection if there's none
+func UpperBound[T any](coll tau.IdxedColl[T], value T, cmp tau.Comparator[T]) int {
+	return partitionPoint(coll, 0, coll.Size(), func(elem T) bool {
+		return cmp(elem, value) <= 0
+	})
+}
+
+// Returns the range [from, to) of the elements equal to the given value
+// The range is empty, with from equal to to, if there's none
+func EqualRange[T any](coll tau.IdxedColl[T], value T, cmp tau.Comparator[T]) (int, int) {
+	from := LowerBound(coll, value, cmp)
+	to := partitionPoint(coll, from, coll.Size(), func(elem T) bool {
+		return cmp(elem, value) <= 0
+	})
+	return from, to
+}
+
+// Returns the index of the first element equal to the given value, or -1 if there's none
+// It doubles the searched range until it goes past the value, so it takes
+// O(log k) time, where k is the index of the value, and suits values near the start
+func ExponentialSearch[T any](coll tau.IdxedColl[T], value T, cmp tau.Comparator[T]) int {
+	size := coll.Size()
+	bound := 1
+	for bound < size && cmp(at(coll, bound-1), value) < 0 {
+		bound *= 2
+	}
+	index := partitionPoint(coll, bound/2, min(bound, size), func(elem T) bool {
+		return cmp(elem, value) < 0
+	})
+	if index < size && cmp(at(coll, index), value) == 0 {
+		return index
+	}
+	return -1
+}
+
+// Returns the index of an element equal to the given value, or -1 if there's none
+// It guesses the position of the value from the ones of the endpoints, so
+// it needs numbers. It takes O(log log n) time if the numbers are uniformly
+// distributed, but O(n) time in the worst case
+func InterpolationSearch[T tau.Number](coll tau.IdxedColl[T], value T) int {
+	low, high := 0, coll.Size()-1
+	for low <= high {
+		lowVal, highVal := at(coll, low), at(coll, high)
+		if value < lowVal || value > highVal {
+			return -1
+		}
+		if lowVal == highVal {
+			return low
+		}
+		fraction := (float64(value) - float64(lowVal)) / (float64(highVal) - float64(lowVal))
+		mid := low + int(fraction*float64(high-low))
+		midVal := at(coll, mid)
+		if midVal == value {
+			return mid
+		} else if midVal < value {
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	return -1
+}
+
+// Returns true if the given collection is sorted in ascending order by [tau.Cmp]
+func IsSorted[T any](coll tau.Collection[T]) bool {
+	return IsSortedBy(coll, func(a, b T) int { return tau.Cmp(a, b) })
+}
+
+// Returns true if the given collection is sorted in ascending order by the given comparator
+// It iterates over the collection once, so it takes O(n) time on any collection
+func IsSortedBy[T any](coll tau.Collection[T], cmp tau.Comparator[T]) bool {
+	iter := coll.Iter()
+	first, ok := iter.Next()
+	if !ok {
+		return true
+	}
+	prev := *first
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if cmp(prev, *next) > 0 {
+			return false
+		}
+		prev = *next
+	}
+	return true
+}
+
+// --- Private helpers ---
+
+// element at the given index, which must be in range
+func at[T any](coll tau.IdxedColl[T], index int) T {
+	val, _ := coll.Get(index)
+	return *val
+}
+
+// first index in [low, high) whose element doesn't satisfy the predicate,
+// given that the ones satisfying it all come first
+func partitionPoint[T any](coll tau.IdxedColl[T], low, high int, before func(T) bool) int {
+	for low < high {
+		mid := low + (high-low)/2
+		if before(at(coll, mid)) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
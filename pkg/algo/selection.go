@@ -0,0 +1,122 @@
+package algo
+
+import (
+	"math/bits"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Rearranges the given collection so that the element at index n is the one
+// that would be there if the collection were sorted, all the elements before
+// it are not greater and all the ones after it are not less
+// It takes O(n) time on average and O(n log n) time in the worst case
+// Returns an error if n is out of range
+func NthElement[T any](coll tau.IdxedColl[T], n int, cmp tau.Comparator[T]) error {
+	if n < 0 || n >= coll.Size() {
+		return errs.IndexOutOfRange("algo.NthElement", nil, n, coll.Size())
+	}
+	data := toSlice(coll)
+	nthElement(data, cmp, n)
+	writeBack(coll, data)
+	return nil
+}
+
+// Rearranges the given collection so that its first k elements are the k
+// smallest ones, in ascending order. The order of the others is unspecified
+// It takes O(n + k log k) time on average
+// Returns an error if k is not in [0, size]
+func PartialSort[T any](coll tau.IdxedColl[T], k int, cmp tau.Comparator[T]) error {
+	if k < 0 || k > coll.Size() {
+		return errs.IndexOutOfRange("algo.PartialSort", nil, k, coll.Size()+1)
+	}
+	data := toSlice(coll)
+	if k < len(data) {
+		nthElement(data, cmp, k)
+	}
+	IntroSortSlice(data[:k], cmp)
+	writeBack(coll, data)
+	return nil
+}
+
+// Returns the median of the given collection, without modifying it
+// For an even number of elements, the lower of the two middle ones is returned
+// Returns an error if the collection is empty
+func Median[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) (T, error) {
+	if coll.Size() == 0 {
+		var zero T
+		return zero, errs.Empty()
+	}
+	data := toSlice(coll)
+	n := (len(data) - 1) / 2
+	nthElement(data, cmp, n)
+	return data[n], nil
+}
+
+// Returns the k greatest values of the given iterator, in descending order
+// It keeps only k values at a time, so it takes O(k) memory and O(n log k) time
+// If there are less than k values, all of them are returned
+func TopK[T any](iter tau.Iterator[T], k int, cmp tau.Comparator[T]) []T {
+	return BottomK(iter, k, func(a, b T) int { return cmp(b, a) })
+}
+
+// Returns the k smallest values of the given iterator, in ascending order
+// It keeps only k values at a time, so it takes O(k) memory and O(n log k) time
+// If there are less than k values, all of them are returned
+func BottomK[T any](iter tau.Iterator[T], k int, cmp tau.Comparator[T]) []T {
+	if k <= 0 {
+		return make([]T, 0)
+	}
+	// max-heap of the k smallest values so far, whose root is the one to drop
+	heap := make([]T, 0, k)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if len(heap) < k {
+			heap = append(heap, *next)
+			siftUp(heap, cmp, len(heap)-1)
+		} else if cmp(*next, heap[0]) < 0 {
+			heap[0] = *next
+			siftDown(heap, cmp, len(heap), 0)
+		}
+	}
+	for i := len(heap) - 1; i > 0; i-- {
+		heap[0], heap[i] = heap[i], heap[0]
+		siftDown(heap, cmp, i, 0)
+	}
+	return heap
+}
+
+// --- Private helpers ---
+
+// quickselect with median-of-three pivots, switching to HeapSort when the
+// partitions are too unbalanced (introselect)
+func nthElement[T any](data []T, cmp tau.Comparator[T], n int) {
+	lo, hi := 0, len(data)
+	depth := 2 * bits.Len(uint(len(data)))
+	for hi-lo > insertionThreshold {
+		if depth == 0 {
+			heapSortRange(data, cmp, lo, hi)
+			return
+		}
+		depth--
+		p := partition(data, cmp, lo, hi)
+		if p == n {
+			return
+		} else if n < p {
+			hi = p
+		} else {
+			lo = p + 1
+		}
+	}
+	insertionSortRange(data, cmp, lo, hi)
+}
+
+func siftUp[T any](heap []T, cmp tau.Comparator[T], child int) {
+	for child > 0 {
+		parent := (child - 1) / 2
+		if cmp(heap[child], heap[parent]) <= 0 {
+			return
+		}
+		heap[child], heap[parent] = heap[parent], heap[child]
+		child = parent
+	}
+}
@@ -0,0 +1,366 @@
+package algo
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// The sequence algorithms work on collections of any type, comparing
+// elements with [tau.Eq]. The ones based on hashing also need the elements
+// to be hashable with [tau.Hash], and equal elements to have the same hash.
+
+// --- Edit scripts ---
+
+// Kind of step of an edit script
+type EditOp int
+
+const (
+	// the element is the same in both sequences
+	EditKeep EditOp = iota
+	// an element of the second sequence is added
+	EditInsert
+	// an element of the first sequence is removed
+	EditDelete
+	// an element of the first sequence is replaced with one of the second
+	EditSubstitute
+	// two adjacent elements of the first sequence are swapped
+	EditTranspose
+)
+
+func (op EditOp) String() string {
+	switch op {
+	case EditKeep:
+		return "keep"
+	case EditInsert:
+		return "insert"
+	case EditDelete:
+		return "delete"
+	case EditSubstitute:
+		return "substitute"
+	case EditTranspose:
+		return "transpose"
+	default:
+		return fmt.Sprintf("EditOp(%d)", int(op))
+	}
+}
+
+// Step of an edit script, which turns a first sequence into a second one
+// A and B are the positions reached in the two sequences: for a deletion, B
+// is where the second sequence continues, and for an insertion, A is where
+// the first one does. A transposition involves the elements at A and A+1,
+// which become the ones at B and B+1
+type Edit struct {
+	Op EditOp
+	A  int
+	B  int
+}
+
+func (e Edit) String() string {
+	return fmt.Sprintf("%v(%d, %d)", e.Op, e.A, e.B)
+}
+
+// Costs of the edit operations, which must not be negative
+type EditCosts struct {
+	Insert     int
+	Delete     int
+	Substitute int
+	Transpose  int
+}
+
+// Returns the costs where every operation counts 1
+func UnitCosts() EditCosts {
+	return EditCosts{1, 1, 1, 1}
+}
+
+// --- Sub-sequence search ---
+
+// Returns the indexes where the pattern occurs in the text, in ascending
+// order, using the Knuth-Morris-Pratt algorithm
+// Occurrences may overlap, and an empty pattern occurs at every index
+// It takes O(n + m) time
+func KMPSearch[T any](text, pattern tau.IdxedColl[T]) []int {
+	t, p := toSlice(text), toSlice(pattern)
+	if len(p) == 0 {
+		return everyIndex(len(t))
+	}
+	// fail[i] is the length of the longest proper prefix of p[:i+1]
+	// that is also a suffix of it
+	fail := make([]int, len(p))
+	for i, k := 1, 0; i < len(p); i++ {
+		for k > 0 && !tau.Eq(p[i], p[k]) {
+			k = fail[k-1]
+		}
+		if tau.Eq(p[i], p[k]) {
+			k++
+		}
+		fail[i] = k
+	}
+	matches := make([]int, 0)
+	for i, k := 0, 0; i < len(t); i++ {
+		for k > 0 && !tau.Eq(t[i], p[k]) {
+			k = fail[k-1]
+		}
+		if tau.Eq(t[i], p[k]) {
+			k++
+		}
+		if k == len(p) {
+			matches = append(matches, i-k+1)
+			k = fail[k-1]
+		}
+	}
+	return matches
+}
+
+// Returns the indexes where the pattern occurs in the text, in ascending
+// order, using the Boyer-Moore-Horspool algorithm
+// It skips parts of the text using the hashes of the elements, so it's
+// usually sub-linear, and O(n m) in the worst case
+func HorspoolSearch[T any](text, pattern tau.IdxedColl[T]) []int {
+	t, p := toSlice(text), toSlice(pattern)
+	m := len(p)
+	if m == 0 {
+		return everyIndex(len(t))
+	}
+	// later elements overwrite the earlier ones, so every hash gets the
+	// smallest shift, which is safe also for colliding elements
+	shifts := make(map[uint32]int)
+	for i := 0; i < m-1; i++ {
+		shifts[tau.Hash(p[i])] = m - 1 - i
+	}
+	matches := make([]int, 0)
+	for pos := 0; pos+m <= len(t); {
+		j := m - 1
+		for j >= 0 && tau.Eq(t[pos+j], p[j]) {
+			j--
+		}
+		if j < 0 {
+			matches = append(matches, pos)
+		}
+		if shift, ok := shifts[tau.Hash(t[pos+m-1])]; ok {
+			pos += shift
+		} else {
+			pos += m
+		}
+	}
+	return matches
+}
+
+// Returns the indexes where the pattern occurs in the text, in ascending
+// order, using the Rabin-Karp algorithm
+// A rolling hash of the window is compared with the one of the pattern,
+// and the elements are checked only when they match. It takes O(n + m)
+// time on average
+func RabinKarpSearch[T any](text, pattern tau.IdxedColl[T]) []int {
+	t, p := toSlice(text), toSlice(pattern)
+	m := len(p)
+	if m == 0 {
+		return everyIndex(len(t))
+	}
+	matches := make([]int, 0)
+	if m > len(t) {
+		return matches
+	}
+	// polynomial hash modulo 2^64, pow is base^(m-1)
+	var target, window, pow uint64 = 0, 0, 1
+	for i := 0; i < m; i++ {
+		target = target*rollingBase + uint64(tau.Hash(p[i]))
+		window = window*rollingBase + uint64(tau.Hash(t[i]))
+		if i > 0 {
+			pow *= rollingBase
+		}
+	}
+	for pos := 0; ; pos++ {
+		if window == target && equalRange(t[pos:pos+m], p) {
+			matches = append(matches, pos)
+		}
+		if pos+m == len(t) {
+			return matches
+		}
+		window = (window-uint64(tau.Hash(t[pos]))*pow)*rollingBase + uint64(tau.Hash(t[pos+m]))
+	}
+}
+
+// --- Common parts ---
+
+// Returns the longest common subsequence of the two collections, and the
+// edit script turning the first into the second with only keeps, inserts
+// and deletes, where the kept elements are the subsequence
+// It takes O(n m) time and memory
+func LongestCommonSubsequence[T any](a, b tau.IdxedColl[T]) ([]T, []Edit) {
+	x, y := toSlice(a), toSlice(b)
+	n, m := len(x), len(y)
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if tau.Eq(x[i], y[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	common := make([]T, 0, lcs[0][0])
+	script := make([]Edit, 0, n+m-lcs[0][0])
+	i, j := 0, 0
+	for i < n && j < m {
+		if tau.Eq(x[i], y[j]) {
+			common = append(common, x[i])
+			script = append(script, Edit{EditKeep, i, j})
+			i, j = i+1, j+1
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			script = append(script, Edit{EditDelete, i, j})
+			i++
+		} else {
+			script = append(script, Edit{EditInsert, i, j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		script = append(script, Edit{EditDelete, i, j})
+	}
+	for ; j < m; j++ {
+		script = append(script, Edit{EditInsert, i, j})
+	}
+	return common, script
+}
+
+// Returns the start indexes, in the two collections, and the length of
+// their longest common contiguous run of elements
+// If there are more, the first one in a is returned. The length is 0 if
+// there's none. It takes O(n m) time and O(m) memory
+func LongestCommonSubstring[T any](a, b tau.IdxedColl[T]) (int, int, int) {
+	x, y := toSlice(a), toSlice(b)
+	// prev[j] is the length of the common run ending at x[i-1] and y[j-1]
+	prev, curr := make([]int, len(y)+1), make([]int, len(y)+1)
+	bestA, bestB, best := 0, 0, 0
+	for i := 1; i <= len(x); i++ {
+		for j := 1; j <= len(y); j++ {
+			if tau.Eq(x[i-1], y[j-1]) {
+				curr[j] = prev[j-1] + 1
+				if curr[j] > best {
+					bestA, bestB, best = i-curr[j], j-curr[j], curr[j]
+				}
+			} else {
+				curr[j] = 0
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return bestA, bestB, best
+}
+
+// --- Edit distances ---
+
+// Returns the Levenshtein distance between the two collections, that is the
+// minimum cost of the inserts, deletes and substitutions turning the first
+// into the second, and an edit script with that cost
+// It takes O(n m) time and memory
+// Panics if a cost is negative
+func Levenshtein[T any](a, b tau.IdxedColl[T], costs EditCosts) (int, []Edit) {
+	return editDistance(toSlice(a), toSlice(b), costs, false, "Levenshtein")
+}
+
+// Returns the Damerau-Levenshtein distance between the two collections,
+// which also allows transpositions of adjacent elements, and an edit script
+// with that cost
+// This is the restricted distance, also known as optimal string alignment:
+// no element is edited more than once, so the triangle inequality may not
+// hold. It takes O(n m) time and memory
+// Panics if a cost is negative
+func DamerauLevenshtein[T any](a, b tau.IdxedColl[T], costs EditCosts) (int, []Edit) {
+	return editDistance(toSlice(a), toSlice(b), costs, true, "DamerauLevenshtein")
+}
+
+// --- Private helpers ---
+
+// odd multiplier of the Rabin-Karp hash
+const rollingBase = 1099511628211
+
+func everyIndex(n int) []int {
+	indexes := make([]int, n+1)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+func equalRange[T any](a, b []T) bool {
+	for i := range a {
+		if !tau.Eq(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func editDistance[T any](x, y []T, costs EditCosts, transpose bool, caller string) (int, []Edit) {
+	if costs.Insert < 0 || costs.Delete < 0 || costs.Substitute < 0 || costs.Transpose < 0 {
+		panic(fmt.Sprintf("ERROR: [algo.%s] negative cost in %+v", caller, costs))
+	}
+	n, m := len(x), len(y)
+	// dist[i][j] is the distance between x[:i] and y[:j]
+	dist := make([][]int, n+1)
+	for i := range dist {
+		dist[i] = make([]int, m+1)
+		dist[i][0] = i * costs.Delete
+	}
+	for j := 1; j <= m; j++ {
+		dist[0][j] = j * costs.Insert
+	}
+	// eq[i][j] caches whether x[i-1] and y[j-1] are equal
+	eq := make([][]bool, n+1)
+	for i := 1; i <= n; i++ {
+		eq[i] = make([]bool, m+1)
+		for j := 1; j <= m; j++ {
+			eq[i][j] = tau.Eq(x[i-1], y[j-1])
+		}
+	}
+	swapped := func(i, j int) bool {
+		return transpose && i > 1 && j > 1 && eq[i][j-1] && eq[i-1][j]
+	}
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			best := min(dist[i-1][j]+costs.Delete, dist[i][j-1]+costs.Insert)
+			if eq[i][j] {
+				best = min(best, dist[i-1][j-1])
+			} else {
+				best = min(best, dist[i-1][j-1]+costs.Substitute)
+			}
+			if swapped(i, j) {
+				best = min(best, dist[i-2][j-2]+costs.Transpose)
+			}
+			dist[i][j] = best
+		}
+	}
+	// walks back from the end, following a step that gives the distance
+	script := make([]Edit, 0, max(n, m))
+	for i, j := n, m; i > 0 || j > 0; {
+		d := dist[i][j]
+		switch {
+		case i > 0 && j > 0 && eq[i][j] && d == dist[i-1][j-1]:
+			i, j = i-1, j-1
+			script = append(script, Edit{EditKeep, i, j})
+		case i > 0 && j > 0 && !eq[i][j] && d == dist[i-1][j-1]+costs.Substitute:
+			i, j = i-1, j-1
+			script = append(script, Edit{EditSubstitute, i, j})
+		case swapped(i, j) && d == dist[i-2][j-2]+costs.Transpose:
+			i, j = i-2, j-2
+			script = append(script, Edit{EditTranspose, i, j})
+		case i > 0 && d == dist[i-1][j]+costs.Delete:
+			i--
+			script = append(script, Edit{EditDelete, i, j})
+		default:
+			j--
+			script = append(script, Edit{EditInsert, i, j})
+		}
+	}
+	for l, r := 0, len(script)-1; l < r; l, r = l+1, r-1 {
+		script[l], script[r] = script[r], script[l]
+	}
+	return dist[n][m], script
+}
@@ -1,181 +1,355 @@
 // This package contains various algorithms (e.g. sorting, searching, etc.)
 //
-// For sorting algorithm, an object of type [tau.IdxedColl] is required
-// since indexwise operations like [tau.IdxedColl.Get], [tau.IdxedColl.Set]
-// and [tau.IdxedColl.Swap] are used.
+// For sorting algorithm, an object of type [tau.IdxedColl] is required.
+// Every algorithm comes in two variants:
+//   - the plain one (e.g. [TimSort]) makes a copy of the given collection
+//     and sorts it, so the original collection is not modified
+//   - the InPlace one (e.g. [TimSortInPlace]) sorts the given collection
 //
-// A copy of the given collection is made before sorting it, so the original collection
-// is not modified.
+// Both read the elements once, sort them in a slice and write them back,
+// so they take O(n) extra time and memory even on collections without
+// constant-time index access. The hybrid algorithms, [IntroSort] and
+// [TimSort], are also available on slices.
 package algo
 
 import (
+	"math/bits"
+
 	"github.com/luverolla/lexgo/pkg/tau"
 )
 
-// Sorts the given collection using the given comparator and the QuickSort algorithm
+// Sorts a copy of the given collection using the given comparator and the QuickSort algorithm
+// The pivot is the median of the first, middle and last elements
 func QuickSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
-	return quickSort(coll, cmp, 0, coll.Size()-1)
+	return sortCopy(coll, cmp, quickSort[T])
 }
 
-// Sorts the given collection using the given comparator and the MergeSort algorithm
+// Sorts the given collection using the given comparator and the QuickSort algorithm
+func QuickSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, quickSort[T])
+}
+
+// Sorts a copy of the given collection using the given comparator and the MergeSort algorithm
+// The sort is stable
 func MergeSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
-	return mergeSort(coll, cmp, 0, coll.Size()-1)
+	return sortCopy(coll, cmp, mergeSort[T])
 }
 
-// Sorts the given collection using the given comparator and the BubbleSort algorithm
+// Sorts the given collection using the given comparator and the MergeSort algorithm
+func MergeSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, mergeSort[T])
+}
+
+// Sorts a copy of the given collection using the given comparator and the BubbleSort algorithm
 func BubbleSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
+	return sortCopy(coll, cmp, bubbleSort[T])
+}
+
+// Sorts the given collection using the given comparator and the BubbleSort algorithm
+func BubbleSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, bubbleSort[T])
+}
+
+// Sorts a copy of the given collection using the given comparator and the InsertionSort algorithm
+func InsertionSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
+	return sortCopy(coll, cmp, insertionSort[T])
+}
+
+// Sorts the given collection using the given comparator and the InsertionSort algorithm
+func InsertionSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, insertionSort[T])
+}
+
+// Sorts a copy of the given collection using the given comparator and the SelectionSort algorithm
+func SelectionSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
+	return sortCopy(coll, cmp, selectionSort[T])
+}
+
+// Sorts the given collection using the given comparator and the SelectionSort algorithm
+func SelectionSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, selectionSort[T])
+}
+
+// Sorts a copy of the given collection using the given comparator and the HeapSort algorithm
+func HeapSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
+	return sortCopy(coll, cmp, heapSort[T])
+}
+
+// Sorts the given collection using the given comparator and the HeapSort algorithm
+func HeapSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, heapSort[T])
+}
+
+// Sorts a copy of the given collection using the given comparator and the IntroSort algorithm
+//
+// IntroSort is a QuickSort with median-of-three pivots that switches to
+// HeapSort when the recursion gets too deep, and to InsertionSort on short
+// ranges, so it takes O(n log n) time in the worst case. It is not stable
+func IntroSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
+	return sortCopy(coll, cmp, IntroSortSlice[T])
+}
+
+// Sorts the given collection using the given comparator and the IntroSort algorithm
+func IntroSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, IntroSortSlice[T])
+}
+
+// Sorts the given slice using the given comparator and the IntroSort algorithm
+func IntroSortSlice[T any](data []T, cmp tau.Comparator[T]) {
+	introSort(data, cmp, 0, len(data), 2*bits.Len(uint(len(data))))
+}
+
+// Sorts a copy of the given collection using the given comparator and the TimSort algorithm
+//
+// TimSort finds the already sorted runs of the collection, extends the
+// short ones with InsertionSort and merges them in a balanced way. It takes
+// O(n log n) time in the worst case and O(n) time on sorted or reversed
+// input. It is stable: equal elements keep their order
+func TimSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
+	return sortCopy(coll, cmp, TimSortSlice[T])
+}
+
+// Sorts the given collection using the given comparator and the TimSort algorithm
+func TimSortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) {
+	sortInPlace(coll, cmp, TimSortSlice[T])
+}
+
+// Sorts the given slice using the given comparator and the TimSort algorithm
+func TimSortSlice[T any](data []T, cmp tau.Comparator[T]) {
+	newTimSorter(data, cmp).sort()
+}
+
+// --- Private helpers ---
+
+// ranges shorter than this are sorted with InsertionSort by the hybrid algorithms
+const insertionThreshold = 16
+
+func toSlice[T any](coll tau.IdxedColl[T]) []T {
+	data := make([]T, 0, coll.Size())
+	coll.Iter().Each(func(value T) {
+		data = append(data, value)
+	})
+	return data
+}
+
+// writes the sorted elements back, replacing the content of lists as a
+// whole since Set may take linear time on them
+func writeBack[T any](coll tau.IdxedColl[T], data []T) {
+	if list, ok := coll.(tau.List[T]); ok {
+		list.Clear()
+		list.Append(data...)
+		return
+	}
+	for i, value := range data {
+		coll.Set(i, value)
+	}
+}
+
+// reads the elements in a slice, rearranges them and writes them back
+func rearrange[T any](coll tau.IdxedColl[T], f func([]T)) {
+	data := toSlice(coll)
+	f(data)
+	writeBack(coll, data)
+}
+
+// rearranges a copy of the collection
+func rearrangeCopy[T any](coll tau.IdxedColl[T], f func([]T)) tau.IdxedColl[T] {
 	copy := coll.Clone().(tau.IdxedColl[T])
-	for i := 0; i < copy.Size()-1; i++ {
-		for j := 0; j < copy.Size()-i-1; j++ {
-			curr, _ := copy.Get(j)
-			next, _ := copy.Get(j + 1)
-			if cmp(*curr, *next) > 0 {
-				copy.Swap(j, j+1)
+	rearrange(copy, f)
+	return copy
+}
+
+func sortInPlace[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T], sorter func([]T, tau.Comparator[T])) {
+	rearrange(coll, func(data []T) { sorter(data, cmp) })
+}
+
+func sortCopy[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T], sorter func([]T, tau.Comparator[T])) tau.IdxedColl[T] {
+	return rearrangeCopy(coll, func(data []T) { sorter(data, cmp) })
+}
+
+func bubbleSort[T any](data []T, cmp tau.Comparator[T]) {
+	for i := 0; i < len(data)-1; i++ {
+		swapped := false
+		for j := 0; j < len(data)-i-1; j++ {
+			if cmp(data[j], data[j+1]) > 0 {
+				data[j], data[j+1] = data[j+1], data[j]
+				swapped = true
 			}
 		}
+		if !swapped {
+			return
+		}
 	}
-	return copy
 }
 
-// Sorts the given collection using the given comparator and the InsertionSort algorithm
-func InsertionSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
-	copy := coll.Clone().(tau.IdxedColl[T])
-	for i := 1; i < copy.Size(); i++ {
-		curr, _ := copy.Get(i)
+func insertionSort[T any](data []T, cmp tau.Comparator[T]) {
+	insertionSortRange(data, cmp, 0, len(data))
+}
+
+// sorts data[lo:hi]
+func insertionSortRange[T any](data []T, cmp tau.Comparator[T], lo, hi int) {
+	for i := lo + 1; i < hi; i++ {
+		curr := data[i]
 		j := i - 1
-		val, _ := copy.Get(j)
-		for j >= 0 && cmp(*curr, *val) < 0 {
-			val, _ = copy.Get(j)
-			copy.Set(j+1, *val)
-			j--
+		for ; j >= lo && cmp(curr, data[j]) < 0; j-- {
+			data[j+1] = data[j]
 		}
-		copy.Set(j+1, *curr)
+		data[j+1] = curr
 	}
-	return copy
 }
 
-// Sorts the given collection using the given comparator and the SelectionSort algorithm
-func SelectionSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
-	copy := coll.Clone().(tau.IdxedColl[T])
-	for i := 0; i < copy.Size()-1; i++ {
+func selectionSort[T any](data []T, cmp tau.Comparator[T]) {
+	for i := 0; i < len(data)-1; i++ {
 		min := i
-		for j := i + 1; j < copy.Size(); j++ {
-			valJ, _ := copy.Get(j)
-			valMin, _ := copy.Get(min)
-			if cmp(*valJ, *valMin) < 0 {
+		for j := i + 1; j < len(data); j++ {
+			if cmp(data[j], data[min]) < 0 {
 				min = j
 			}
 		}
-		copy.Swap(i, min)
+		data[i], data[min] = data[min], data[i]
 	}
-	return copy
 }
 
-// Sorts the given collection using the given comparator and the HeapSort algorithm
-func HeapSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T]) tau.IdxedColl[T] {
-	copy := coll.Clone().(tau.IdxedColl[T])
-	for i := copy.Size()/2 - 1; i >= 0; i-- {
-		heapify(copy, cmp, copy.Size(), i)
+func heapSort[T any](data []T, cmp tau.Comparator[T]) {
+	heapSortRange(data, cmp, 0, len(data))
+}
+
+// sorts data[lo:hi] with a max-heap rooted at lo
+func heapSortRange[T any](data []T, cmp tau.Comparator[T], lo, hi int) {
+	size := hi - lo
+	for i := size/2 - 1; i >= 0; i-- {
+		siftDown(data[lo:hi], cmp, size, i)
 	}
-	for i := copy.Size() - 1; i >= 0; i-- {
-		copy.Swap(0, i)
-		heapify(copy, cmp, i, 0)
+	for i := size - 1; i > 0; i-- {
+		data[lo], data[lo+i] = data[lo+i], data[lo]
+		siftDown(data[lo:hi], cmp, i, 0)
 	}
-	return copy
 }
 
-// --- Private helpers ---
-func quickSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T], low, high int) tau.IdxedColl[T] {
-	if low < high {
-		pivot := partition(coll, cmp, low, high)
-		quickSort(coll, cmp, low, pivot-1)
-		quickSort(coll, cmp, pivot+1, high)
+func siftDown[T any](heap []T, cmp tau.Comparator[T], size, root int) {
+	for {
+		largest := root
+		left, right := 2*root+1, 2*root+2
+		if left < size && cmp(heap[left], heap[largest]) > 0 {
+			largest = left
+		}
+		if right < size && cmp(heap[right], heap[largest]) > 0 {
+			largest = right
+		}
+		if largest == root {
+			return
+		}
+		heap[root], heap[largest] = heap[largest], heap[root]
+		root = largest
 	}
-	return coll
 }
 
-func partition[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T], low int, high int) int {
-	pivot, _ := coll.Get(high)
-	var i = low - 1
+func quickSort[T any](data []T, cmp tau.Comparator[T]) {
+	quickSortRange(data, cmp, 0, len(data))
+}
 
-	for j := low; j < high; j++ {
-		valJ, _ := coll.Get(j)
-		if cmp(*valJ, *pivot) <= 0 {
-			i++
-			coll.Swap(i, j)
+// sorts data[lo:hi], recursing on the shorter side so that the stack
+// depth is O(log n)
+func quickSortRange[T any](data []T, cmp tau.Comparator[T], lo, hi int) {
+	for hi-lo > 1 {
+		p := partition(data, cmp, lo, hi)
+		if p-lo < hi-p-1 {
+			quickSortRange(data, cmp, lo, p)
+			lo = p + 1
+		} else {
+			quickSortRange(data, cmp, p+1, hi)
+			hi = p
 		}
 	}
-	coll.Swap(i+1, high)
-	return i + 1
-}
-
-func heapify[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T], size, root int) {
-	largest := root
-	left := 2*root + 1
-	right := 2*root + 2
-	if left < size {
-		largestVal, _ := coll.Get(largest)
-		leftVal, _ := coll.Get(left)
-		if cmp(*leftVal, *largestVal) > 0 {
-			largest = left
+}
+
+// sorts data[lo:hi], falling back to HeapSort after depth partitions
+func introSort[T any](data []T, cmp tau.Comparator[T], lo, hi, depth int) {
+	for hi-lo > insertionThreshold {
+		if depth == 0 {
+			heapSortRange(data, cmp, lo, hi)
+			return
+		}
+		depth--
+		p := partition(data, cmp, lo, hi)
+		if p-lo < hi-p-1 {
+			introSort(data, cmp, lo, p, depth)
+			lo = p + 1
+		} else {
+			introSort(data, cmp, p+1, hi, depth)
+			hi = p
 		}
 	}
-	if right < size {
-		largestVal, _ := coll.Get(largest)
-		rightVal, _ := coll.Get(right)
-		if cmp(*rightVal, *largestVal) > 0 {
-			largest = right
+	insertionSortRange(data, cmp, lo, hi)
+}
+
+// partitions data[lo:hi] around the median of its first, middle and last
+// elements, and returns the final index of the pivot
+// Elements equal to the pivot are spread on both sides, so that many
+// duplicates don't unbalance the partition
+func partition[T any](data []T, cmp tau.Comparator[T], lo, hi int) int {
+	mid := lo + (hi-lo)/2
+	if cmp(data[mid], data[lo]) < 0 {
+		data[mid], data[lo] = data[lo], data[mid]
+	}
+	if cmp(data[hi-1], data[mid]) < 0 {
+		data[hi-1], data[mid] = data[mid], data[hi-1]
+		if cmp(data[mid], data[lo]) < 0 {
+			data[mid], data[lo] = data[lo], data[mid]
 		}
 	}
-	if largest != root {
-		coll.Swap(root, largest)
-		heapify(coll, cmp, size, largest)
+	data[lo], data[mid] = data[mid], data[lo]
+	pivot := data[lo]
+	i, j := lo+1, hi-1
+	for {
+		for i <= j && cmp(data[i], pivot) < 0 {
+			i++
+		}
+		for i <= j && cmp(data[j], pivot) > 0 {
+			j--
+		}
+		if i > j {
+			break
+		}
+		data[i], data[j] = data[j], data[i]
+		i++
+		j--
 	}
+	data[lo], data[j] = data[j], data[lo]
+	return j
 }
 
-func mergeSort[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T], start, end int) tau.IdxedColl[T] {
-	copy := coll.Clone().(tau.IdxedColl[T])
-	if start < end {
-		mid := (start + end) / 2
-		mergeSort(copy, cmp, start, mid)
-		mergeSort(copy, cmp, mid+1, end)
-		merge(copy, cmp, start, mid, end)
-	}
-	return copy
+func mergeSort[T any](data []T, cmp tau.Comparator[T]) {
+	mergeSortRange(data, cmp, make([]T, len(data)/2+1), 0, len(data))
 }
 
-func merge[T any](coll tau.IdxedColl[T], cmp tau.Comparator[T], start, mid, end int) {
-	leftSize := mid - start + 1
-	rightSize := end - mid
-	left := make([]T, leftSize)
-	right := make([]T, rightSize)
-	for i := 0; i < leftSize; i++ {
-		val, _ := coll.Get(start + i)
-		left[i] = *val
-	}
-	for i := 0; i < rightSize; i++ {
-		val, _ := coll.Get(mid + 1 + i)
-		right[i] = *val
+// sorts data[lo:hi], using buf for the merges
+func mergeSortRange[T any](data []T, cmp tau.Comparator[T], buf []T, lo, hi int) {
+	if hi-lo < 2 {
+		return
 	}
-	i, j, k := 0, 0, start
-	for i < leftSize && j < rightSize {
-		if cmp(left[i], right[j]) <= 0 {
-			coll.Set(k, left[i])
+	mid := lo + (hi-lo)/2
+	mergeSortRange(data, cmp, buf, lo, mid)
+	mergeSortRange(data, cmp, buf, mid, hi)
+	merge(data, cmp, buf, lo, mid, hi)
+}
+
+// stably merges the sorted ranges data[lo:mid] and data[mid:hi]
+// buf must hold at least mid-lo elements
+func merge[T any](data []T, cmp tau.Comparator[T], buf []T, lo, mid, hi int) {
+	left := buf[:mid-lo]
+	copy(left, data[lo:mid])
+	i, j, k := 0, mid, lo
+	for i < len(left) && j < hi {
+		if cmp(left[i], data[j]) <= 0 {
+			data[k] = left[i]
 			i++
 		} else {
-			coll.Set(k, right[j])
+			data[k] = data[j]
 			j++
 		}
 		k++
 	}
-	for i < leftSize {
-		coll.Set(k, left[i])
-		i++
-		k++
-	}
-	for j < rightSize {
-		coll.Set(k, right[j])
-		j++
-		k++
-	}
+	// the rest of the right range is already in place
+	copy(data[k:], left[i:])
 }
@@ -0,0 +1,151 @@
+package algo
+
+import (
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// --- TimSort ---
+
+// sorted range of the data, waiting to be merged
+type timRun struct {
+	start  int
+	length int
+}
+
+type timSorter[T any] struct {
+	data []T
+	cmp  tau.Comparator[T]
+	// pending runs, from left to right
+	runs []timRun
+	buf  []T
+}
+
+func newTimSorter[T any](data []T, cmp tau.Comparator[T]) *timSorter[T] {
+	return &timSorter[T]{data, cmp, make([]timRun, 0), nil}
+}
+
+func (ts *timSorter[T]) sort() {
+	n := len(ts.data)
+	if n < 2 {
+		return
+	}
+	minRun := minRunLength(n)
+	for lo := 0; lo < n; {
+		length := ts.countRun(lo)
+		if length < minRun {
+			// extends the run with the following elements
+			forced := min(minRun, n-lo)
+			binaryInsertionSort(ts.data, ts.cmp, lo, lo+forced, lo+length)
+			length = forced
+		}
+		ts.runs = append(ts.runs, timRun{lo, length})
+		ts.mergeCollapse()
+		lo += length
+	}
+	for len(ts.runs) > 1 {
+		i := len(ts.runs) - 2
+		if i > 0 && ts.runs[i-1].length < ts.runs[i+1].length {
+			i--
+		}
+		ts.mergeAt(i)
+	}
+}
+
+// length of the run starting at lo, reversing it if strictly descending
+// Descending runs must be strict, or reversing them would break stability
+func (ts *timSorter[T]) countRun(lo int) int {
+	data, hi := ts.data, lo+1
+	if hi == len(data) {
+		return 1
+	}
+	if ts.cmp(data[hi], data[lo]) < 0 {
+		for hi++; hi < len(data) && ts.cmp(data[hi], data[hi-1]) < 0; hi++ {
+		}
+		for i, j := lo, hi-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	} else {
+		for hi++; hi < len(data) && ts.cmp(data[hi], data[hi-1]) >= 0; hi++ {
+		}
+	}
+	return hi - lo
+}
+
+// merges the pending runs until their lengths, from the top of the stack,
+// grow faster than the Fibonacci numbers, so that merges stay balanced
+func (ts *timSorter[T]) mergeCollapse() {
+	for len(ts.runs) > 1 {
+		i := len(ts.runs) - 2
+		runs := ts.runs
+		if (i > 0 && runs[i-1].length <= runs[i].length+runs[i+1].length) ||
+			(i > 1 && runs[i-2].length <= runs[i-1].length+runs[i].length) {
+			if runs[i-1].length < runs[i+1].length {
+				i--
+			}
+		} else if runs[i].length > runs[i+1].length {
+			return
+		}
+		ts.mergeAt(i)
+	}
+}
+
+// merges the i-th and (i+1)-th pending runs
+func (ts *timSorter[T]) mergeAt(i int) {
+	left, right := ts.runs[i], ts.runs[i+1]
+	ts.runs[i].length += right.length
+	ts.runs = append(ts.runs[:i+1], ts.runs[i+2:]...)
+
+	lo, mid, hi := left.start, right.start, right.start+right.length
+	// the elements of the left run not greater than the first of the right
+	// one, and those of the right run not less than the last of the left
+	// one, are already in place
+	data := ts.data[lo:hi]
+	first := data[mid-lo]
+	lo += sliceBound(data[:mid-lo], func(elem T) bool { return ts.cmp(elem, first) <= 0 })
+	last := ts.data[mid-1]
+	hi = mid + sliceBound(ts.data[mid:hi], func(elem T) bool { return ts.cmp(elem, last) < 0 })
+	if lo == mid || mid == hi {
+		return
+	}
+	if cap(ts.buf) < mid-lo {
+		ts.buf = make([]T, mid-lo)
+	}
+	merge(ts.data, ts.cmp, ts.buf[:cap(ts.buf)], lo, mid, hi)
+}
+
+// length of the runs to build, between 16 and 32, such that n divided by
+// it is a power of two or a little less
+func minRunLength(n int) int {
+	extra := 0
+	for n >= 2*insertionThreshold {
+		extra |= n & 1
+		n >>= 1
+	}
+	return n + extra
+}
+
+// sorts data[lo:hi], given that data[lo:sorted] is already sorted
+// Each element is inserted after the ones equal to it, keeping stability
+func binaryInsertionSort[T any](data []T, cmp tau.Comparator[T], lo, hi, sorted int) {
+	for i := sorted; i < hi; i++ {
+		curr := data[i]
+		pos := lo + sliceBound(data[lo:i], func(elem T) bool { return cmp(elem, curr) <= 0 })
+		copy(data[pos+1:i+1], data[pos:i])
+		data[pos] = curr
+	}
+}
+
+// first index of the slice whose element doesn't satisfy the predicate,
+// given that the ones satisfying it all come first
+func sliceBound[T any](data []T, before func(T) bool) int {
+	low, high := 0, len(data)
+	for low < high {
+		mid := low + (high-low)/2
+		if before(data[mid]) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
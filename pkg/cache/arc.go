@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// (A)daptive (R)eplacement (C)ache
+//
+// Cached entries are split between a recency list (T1, entries seen once)
+// and a frequency list (T2, entries seen at least twice). The keys of the
+// entries evicted from each of them are remembered in two "ghost" lists
+// (B1 and B2): a hit on a ghost key moves the target size of T1 towards
+// the list that would have kept it, so the cache adapts to the workload.
+//
+// With a [Weigher], all the list sizes are measured in weight.
+type ARCCache[K any, V any] struct {
+	base[K, V]
+	index *table.HshMap[K, *entry[K, V]]
+	t1    entryList[K, V]
+	t2    entryList[K, V]
+	b1    entryList[K, V]
+	b2    entryList[K, V]
+	// target weight for T1
+	p int
+}
+
+// Creates a new ARC cache with the given capacity
+func ARC[K any, V any](capacity int) *ARCCache[K, V] {
+	return &ARCCache[K, V]{base: newBase[K, V](capacity), index: table.Hsh[K, *entry[K, V]]()}
+}
+
+// --- Methods from Cache[K, V] ---
+func (cache *ARCCache[K, V]) String() string {
+	return keysString("ARCCache", cache.Keys())
+}
+
+func (cache *ARCCache[K, V]) Get(key K) (*V, error) {
+	e := cache.lookup(key)
+	if e == nil {
+		cache.stats.Misses++
+		return nil, errs.NotFound(key)
+	}
+	cache.stats.Hits++
+	e.owner.unlink(e)
+	cache.t2.pushBack(e)
+	return &e.value, nil
+}
+
+func (cache *ARCCache[K, V]) Peek(key K) (*V, error) {
+	e := cache.lookup(key)
+	if e == nil {
+		return nil, errs.NotFound(key)
+	}
+	return &e.value, nil
+}
+
+func (cache *ARCCache[K, V]) Put(key K, value V) {
+	weight := cache.weigh(key, value)
+	found, err := cache.index.Get(key)
+	if err != nil {
+		e := &entry[K, V]{key: key, value: value, weight: weight}
+		cache.index.Put(key, e)
+		cache.t1.pushBack(e)
+		cache.weight += weight
+		cache.trimGhosts()
+		cache.fit(false)
+		return
+	}
+
+	e := *found
+	fromB2 := e.owner == &cache.b2
+	switch e.owner {
+	case &cache.t1, &cache.t2:
+		e.owner.unlink(e)
+		cache.weight -= e.weight
+	case &cache.b1:
+		cache.p = min(cache.capacity, cache.p+max(cache.b2.weight/cache.b1.weight, 1)*e.weight)
+		cache.b1.unlink(e)
+	case &cache.b2:
+		cache.p = max(0, cache.p-max(cache.b1.weight/cache.b2.weight, 1)*e.weight)
+		cache.b2.unlink(e)
+	}
+	e.value = value
+	e.weight = weight
+	cache.t2.pushBack(e)
+	cache.weight += weight
+	cache.fit(fromB2)
+}
+
+func (cache *ARCCache[K, V]) Remove(key K) (*V, error) {
+	e := cache.lookup(key)
+	if e == nil {
+		return nil, errs.NotFound(key)
+	}
+	cache.index.Remove(key)
+	e.owner.unlink(e)
+	cache.weight -= e.weight
+	return &e.value, nil
+}
+
+func (cache *ARCCache[K, V]) HasKey(key K) bool {
+	return cache.lookup(key) != nil
+}
+
+// Iterates over the keys in T1, then over the ones in T2,
+// both from the least to the most recently used
+func (cache *ARCCache[K, V]) Keys() tau.Iterator[K] {
+	return newKeyIter(&cache.t1, &cache.t2)
+}
+
+func (cache *ARCCache[K, V]) Size() int {
+	return cache.t1.size + cache.t2.size
+}
+
+func (cache *ARCCache[K, V]) Weight() int {
+	return cache.weight
+}
+
+func (cache *ARCCache[K, V]) Capacity() int {
+	return cache.capacity
+}
+
+func (cache *ARCCache[K, V]) Clear() {
+	cache.index.Clear()
+	cache.t1 = entryList[K, V]{}
+	cache.t2 = entryList[K, V]{}
+	cache.b1 = entryList[K, V]{}
+	cache.b2 = entryList[K, V]{}
+	cache.weight = 0
+	cache.p = 0
+}
+
+func (cache *ARCCache[K, V]) SetWeigher(weigher Weigher[K, V]) {
+	cache.weigher = weigher
+}
+
+func (cache *ARCCache[K, V]) OnEvict(f EvictFunc[K, V]) {
+	cache.onEvict = f
+}
+
+func (cache *ARCCache[K, V]) Stats() Stats {
+	return cache.stats
+}
+
+// Returns the current target weight for the recency list
+func (cache *ARCCache[K, V]) Target() int {
+	return cache.p
+}
+
+// --- Private methods ---
+
+// returns the cached entry with the given key, ghosts excluded
+func (cache *ARCCache[K, V]) lookup(key K) *entry[K, V] {
+	e, err := cache.index.Get(key)
+	if err != nil || ((*e).owner != &cache.t1 && (*e).owner != &cache.t2) {
+		return nil
+	}
+	return *e
+}
+
+// evicts entries until the cache is within its capacity
+// fromB2 is true when the request was a hit in B2
+func (cache *ARCCache[K, V]) fit(fromB2 bool) {
+	for cache.weight > cache.capacity {
+		cache.replace(fromB2)
+		fromB2 = false
+	}
+}
+
+// moves the LRU entry of T1 or T2 to the corresponding ghost list
+func (cache *ARCCache[K, V]) replace(fromB2 bool) {
+	var e *entry[K, V]
+	t1 := cache.t1.weight
+	if !cache.t1.empty() && (t1 > cache.p || (fromB2 && t1 == cache.p) || cache.t2.empty()) {
+		e = cache.t1.popFront()
+		cache.b1.pushBack(e)
+	} else {
+		e = cache.t2.popFront()
+		cache.b2.pushBack(e)
+	}
+	cache.weight -= e.weight
+	cache.evicted(e)
+	var zero V
+	e.value = zero
+	cache.trimGhosts()
+}
+
+// bounds the ghost lists: T1+B1 and the whole directory
+// can be at most one and two times the capacity respectively
+func (cache *ARCCache[K, V]) trimGhosts() {
+	for cache.t1.weight+cache.b1.weight > cache.capacity && !cache.b1.empty() {
+		cache.index.Remove(cache.b1.popFront().key)
+	}
+	for cache.weight+cache.b1.weight+cache.b2.weight > 2*cache.capacity && !cache.b2.empty() {
+		cache.index.Remove(cache.b2.popFront().key)
+	}
+}
@@ -0,0 +1,224 @@
+// This package contains bounded caches with different eviction policies
+//
+// All the caches index their entries with a [table.HshMap] and keep the
+// eviction order in intrusive doubly linked lists, shaped like the ones
+// of [list.LkdList], so that every operation runs in O(1) average time.
+//
+// The capacity is a limit on the number of entries, unless a [Weigher]
+// is set: in that case it's a limit on the sum of the entries' weights.
+package cache
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Generic bounded cache
+type Cache[K any, V any] interface {
+	fmt.Stringer
+	// Returns the value associated with the given key and marks it as used
+	// Returns an error if the key is not cached
+	Get(K) (*V, error)
+	// Returns the value associated with the given key without marking it as used
+	// Returns an error if the key is not cached
+	Peek(K) (*V, error)
+	// Associates the given value with the given key,
+	// evicting other entries if the capacity is exceeded
+	Put(K, V)
+	// Removes the entry with the given key, without calling the eviction callback
+	// Returns an error if the key is not cached
+	Remove(K) (*V, error)
+	// Returns true if the key is cached. It does not affect the eviction order
+	HasKey(K) bool
+	// Returns an iterator over the cached keys, from the next to be evicted
+	// to the last one. For caches with more than one queue, the order is
+	// implementation-dependent
+	Keys() tau.Iterator[K]
+	// Returns the number of cached entries
+	Size() int
+	// Returns the sum of the weights of the cached entries
+	Weight() int
+	// Returns the maximum weight (or number of entries) the cache can hold
+	Capacity() int
+	// Removes all the entries, without calling the eviction callback
+	// Statistics are not reset
+	Clear()
+	// Sets the function used to compute the weight of the entries
+	// It must be called before putting any entry
+	SetWeigher(Weigher[K, V])
+	// Sets the function called for every entry evicted due to capacity
+	OnEvict(EvictFunc[K, V])
+	// Returns the hit/miss statistics
+	Stats() Stats
+}
+
+// Function returning the weight of an entry. It must be positive
+type Weigher[K any, V any] func(K, V) int
+
+// Function called with the key and value of an evicted entry
+type EvictFunc[K any, V any] func(K, V)
+
+// Hit/miss statistics of a cache
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// Returns the ratio between hits and lookups, or 0 if there were no lookups
+func (stats Stats) HitRate() float64 {
+	total := stats.Hits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(total)
+}
+
+func (stats Stats) String() string {
+	return fmt.Sprintf("Stats{hits: %d, misses: %d, evictions: %d}", stats.Hits, stats.Misses, stats.Evictions)
+}
+
+// --- Shared internals ---
+
+// settings and counters common to all the caches
+type base[K any, V any] struct {
+	capacity int
+	weight   int
+	weigher  Weigher[K, V]
+	onEvict  EvictFunc[K, V]
+	stats    Stats
+}
+
+func newBase[K any, V any](capacity int) base[K, V] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("ERROR: [cache] capacity must be positive, got %d", capacity))
+	}
+	return base[K, V]{capacity: capacity}
+}
+
+func (b *base[K, V]) weigh(key K, value V) int {
+	if b.weigher == nil {
+		return 1
+	}
+	return b.weigher(key, value)
+}
+
+func (b *base[K, V]) evicted(e *entry[K, V]) {
+	b.stats.Evictions++
+	if b.onEvict != nil {
+		b.onEvict(e.key, e.value)
+	}
+}
+
+// --- Entry and intrusive list ---
+type entry[K any, V any] struct {
+	key    K
+	value  V
+	weight int
+	freq   int
+	// list holding the entry
+	owner *entryList[K, V]
+	// frequency list holding the entry, only for LFU
+	freqs *freqList[K, V]
+	prev  *entry[K, V]
+	next  *entry[K, V]
+}
+
+// doubly linked list of entries, from the least recent (head)
+// to the most recent one (tail)
+type entryList[K any, V any] struct {
+	head   *entry[K, V]
+	tail   *entry[K, V]
+	size   int
+	weight int
+}
+
+func (l *entryList[K, V]) pushBack(e *entry[K, V]) {
+	e.owner = l
+	e.prev = l.tail
+	e.next = nil
+	if l.tail == nil {
+		l.head = e
+	} else {
+		l.tail.next = e
+	}
+	l.tail = e
+	l.size++
+	l.weight += e.weight
+}
+
+func (l *entryList[K, V]) unlink(e *entry[K, V]) {
+	if e.prev == nil {
+		l.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		l.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	e.prev = nil
+	e.next = nil
+	e.owner = nil
+	l.size--
+	l.weight -= e.weight
+}
+
+func (l *entryList[K, V]) popFront() *entry[K, V] {
+	e := l.head
+	if e != nil {
+		l.unlink(e)
+	}
+	return e
+}
+
+func (l *entryList[K, V]) empty() bool {
+	return l.size == 0
+}
+
+// --- Iterator ---
+
+// iterates over the keys of a sequence of lists
+type keyIter[K any, V any] struct {
+	lists []*entryList[K, V]
+	node  *entry[K, V]
+}
+
+func newKeyIter[K any, V any](lists ...*entryList[K, V]) *keyIter[K, V] {
+	return &keyIter[K, V]{lists, nil}
+}
+
+func (iter *keyIter[K, V]) Next() (*K, bool) {
+	for iter.node == nil {
+		if len(iter.lists) == 0 {
+			return nil, false
+		}
+		iter.node = iter.lists[0].head
+		iter.lists = iter.lists[1:]
+	}
+	key := iter.node.key
+	iter.node = iter.node.next
+	return &key, true
+}
+
+func (iter *keyIter[K, V]) Each(f func(K)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+func keysString[K any](name string, iter tau.Iterator[K]) string {
+	s := name + "["
+	first := true
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += fmt.Sprintf("%v", *next)
+	}
+	s += "]"
+	return s
+}
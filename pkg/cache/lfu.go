@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Cache evicting the (L)east (F)requently (U)sed entry
+//
+// Entries are grouped in lists by access frequency, and the lists are
+// chained by increasing frequency, so that both the access and the
+// eviction are O(1). Ties between entries with the same frequency are
+// broken by evicting the least recently used.
+type LFUCache[K any, V any] struct {
+	base[K, V]
+	index *table.HshMap[K, *entry[K, V]]
+	// frequency list with the lowest frequency
+	lowest *freqList[K, V]
+}
+
+// Creates a new LFU cache with the given capacity
+func LFU[K any, V any](capacity int) *LFUCache[K, V] {
+	return &LFUCache[K, V]{newBase[K, V](capacity), table.Hsh[K, *entry[K, V]](), nil}
+}
+
+// --- Methods from Cache[K, V] ---
+func (cache *LFUCache[K, V]) String() string {
+	return keysString("LFUCache", cache.Keys())
+}
+
+func (cache *LFUCache[K, V]) Get(key K) (*V, error) {
+	e, err := cache.index.Get(key)
+	if err != nil {
+		cache.stats.Misses++
+		return nil, err
+	}
+	cache.stats.Hits++
+	cache.touch(*e)
+	return &(*e).value, nil
+}
+
+func (cache *LFUCache[K, V]) Peek(key K) (*V, error) {
+	e, err := cache.index.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return &(*e).value, nil
+}
+
+func (cache *LFUCache[K, V]) Put(key K, value V) {
+	weight := cache.weigh(key, value)
+	if old, err := cache.index.Get(key); err == nil {
+		e := *old
+		cache.touch(e)
+		e.owner.weight += weight - e.weight
+		cache.weight += weight - e.weight
+		e.value = value
+		e.weight = weight
+		for cache.weight > cache.capacity && cache.lowest != nil {
+			cache.evict()
+		}
+		return
+	}
+	// room is made before inserting, or the new entry,
+	// having the lowest frequency, would be the first to go
+	for cache.weight+weight > cache.capacity && cache.lowest != nil {
+		cache.evict()
+	}
+	e := &entry[K, V]{key: key, value: value, weight: weight, freq: 1}
+	cache.index.Put(key, e)
+	if cache.lowest == nil || cache.lowest.freq != 1 {
+		cache.lowest = newFreqList(1, nil, cache.lowest)
+	}
+	cache.lowest.pushBack(e)
+	cache.weight += weight
+	if cache.weight > cache.capacity {
+		cache.evict()
+	}
+}
+
+func (cache *LFUCache[K, V]) Remove(key K) (*V, error) {
+	e, err := cache.index.Remove(key)
+	if err != nil {
+		return nil, err
+	}
+	cache.detach(*e)
+	cache.weight -= (*e).weight
+	return &(*e).value, nil
+}
+
+func (cache *LFUCache[K, V]) HasKey(key K) bool {
+	return cache.index.HasKey(key)
+}
+
+// Iterates from the least to the most frequently used key
+func (cache *LFUCache[K, V]) Keys() tau.Iterator[K] {
+	lists := make([]*entryList[K, V], 0)
+	for fl := cache.lowest; fl != nil; fl = fl.next {
+		lists = append(lists, &fl.entryList)
+	}
+	return newKeyIter(lists...)
+}
+
+func (cache *LFUCache[K, V]) Size() int {
+	return cache.index.Size()
+}
+
+func (cache *LFUCache[K, V]) Weight() int {
+	return cache.weight
+}
+
+func (cache *LFUCache[K, V]) Capacity() int {
+	return cache.capacity
+}
+
+func (cache *LFUCache[K, V]) Clear() {
+	cache.index.Clear()
+	cache.lowest = nil
+	cache.weight = 0
+}
+
+func (cache *LFUCache[K, V]) SetWeigher(weigher Weigher[K, V]) {
+	cache.weigher = weigher
+}
+
+func (cache *LFUCache[K, V]) OnEvict(f EvictFunc[K, V]) {
+	cache.onEvict = f
+}
+
+func (cache *LFUCache[K, V]) Stats() Stats {
+	return cache.stats
+}
+
+// Returns the number of accesses to the given key, or 0 if it's not cached
+func (cache *LFUCache[K, V]) Frequency(key K) int {
+	e, err := cache.index.Get(key)
+	if err != nil {
+		return 0
+	}
+	return (*e).freq
+}
+
+// --- Private methods ---
+
+// unlinks the entry from its frequency list, dropping the list if empty
+func (cache *LFUCache[K, V]) detach(e *entry[K, V]) {
+	fl := e.freqs
+	fl.unlink(e)
+	e.freqs = nil
+	if fl.empty() {
+		if fl.prev == nil {
+			cache.lowest = fl.next
+		} else {
+			fl.prev.next = fl.next
+		}
+		if fl.next != nil {
+			fl.next.prev = fl.prev
+		}
+	}
+}
+
+// moves the entry to the list of the next frequency
+func (cache *LFUCache[K, V]) touch(e *entry[K, V]) {
+	fl := e.freqs
+	e.freq++
+	next := fl.next
+	if next == nil || next.freq != e.freq {
+		next = newFreqList(e.freq, fl, next)
+	}
+	cache.detach(e)
+	next.pushBack(e)
+}
+
+func (cache *LFUCache[K, V]) evict() {
+	e := cache.lowest.head
+	cache.detach(e)
+	cache.index.Remove(e.key)
+	cache.weight -= e.weight
+	cache.evicted(e)
+}
+
+// --- Frequency list ---
+type freqList[K any, V any] struct {
+	entryList[K, V]
+	freq int
+	prev *freqList[K, V]
+	next *freqList[K, V]
+}
+
+// creates a frequency list linked between the two given ones
+func newFreqList[K any, V any](freq int, prev, next *freqList[K, V]) *freqList[K, V] {
+	fl := &freqList[K, V]{freq: freq, prev: prev, next: next}
+	if prev != nil {
+		prev.next = fl
+	}
+	if next != nil {
+		next.prev = fl
+	}
+	return fl
+}
+
+func (fl *freqList[K, V]) pushBack(e *entry[K, V]) {
+	fl.entryList.pushBack(e)
+	e.freqs = fl
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Cache evicting the (L)east (R)ecently (U)sed entry
+type LRUCache[K any, V any] struct {
+	base[K, V]
+	index *table.HshMap[K, *entry[K, V]]
+	order entryList[K, V]
+}
+
+// Creates a new LRU cache with the given capacity
+func LRU[K any, V any](capacity int) *LRUCache[K, V] {
+	return &LRUCache[K, V]{newBase[K, V](capacity), table.Hsh[K, *entry[K, V]](), entryList[K, V]{}}
+}
+
+// --- Methods from Cache[K, V] ---
+func (cache *LRUCache[K, V]) String() string {
+	return keysString("LRUCache", cache.Keys())
+}
+
+func (cache *LRUCache[K, V]) Get(key K) (*V, error) {
+	e, err := cache.index.Get(key)
+	if err != nil {
+		cache.stats.Misses++
+		return nil, err
+	}
+	cache.stats.Hits++
+	cache.order.unlink(*e)
+	cache.order.pushBack(*e)
+	return &(*e).value, nil
+}
+
+func (cache *LRUCache[K, V]) Peek(key K) (*V, error) {
+	e, err := cache.index.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return &(*e).value, nil
+}
+
+func (cache *LRUCache[K, V]) Put(key K, value V) {
+	weight := cache.weigh(key, value)
+	if old, err := cache.index.Get(key); err == nil {
+		cache.order.unlink(*old)
+		cache.weight -= (*old).weight
+		(*old).value = value
+		(*old).weight = weight
+		cache.order.pushBack(*old)
+		cache.weight += weight
+	} else {
+		e := &entry[K, V]{key: key, value: value, weight: weight}
+		cache.index.Put(key, e)
+		cache.order.pushBack(e)
+		cache.weight += weight
+	}
+	for cache.weight > cache.capacity && !cache.order.empty() {
+		cache.evict()
+	}
+}
+
+func (cache *LRUCache[K, V]) Remove(key K) (*V, error) {
+	e, err := cache.index.Remove(key)
+	if err != nil {
+		return nil, err
+	}
+	cache.order.unlink(*e)
+	cache.weight -= (*e).weight
+	return &(*e).value, nil
+}
+
+func (cache *LRUCache[K, V]) HasKey(key K) bool {
+	return cache.index.HasKey(key)
+}
+
+func (cache *LRUCache[K, V]) Keys() tau.Iterator[K] {
+	return newKeyIter(&cache.order)
+}
+
+func (cache *LRUCache[K, V]) Size() int {
+	return cache.index.Size()
+}
+
+func (cache *LRUCache[K, V]) Weight() int {
+	return cache.weight
+}
+
+func (cache *LRUCache[K, V]) Capacity() int {
+	return cache.capacity
+}
+
+func (cache *LRUCache[K, V]) Clear() {
+	cache.index.Clear()
+	cache.order = entryList[K, V]{}
+	cache.weight = 0
+}
+
+func (cache *LRUCache[K, V]) SetWeigher(weigher Weigher[K, V]) {
+	cache.weigher = weigher
+}
+
+func (cache *LRUCache[K, V]) OnEvict(f EvictFunc[K, V]) {
+	cache.onEvict = f
+}
+
+func (cache *LRUCache[K, V]) Stats() Stats {
+	return cache.stats
+}
+
+// --- Private methods ---
+func (cache *LRUCache[K, V]) evict() {
+	e := cache.order.popFront()
+	cache.index.Remove(e.key)
+	cache.weight -= e.weight
+	cache.evicted(e)
+}
@@ -33,20 +33,7 @@ func (deque *ArrDeque[T]) String() string {
 }
 
 func (deque *ArrDeque[T]) Cmp(other any) int {
-	otherDeque, ok := other.(*ArrDeque[T])
-	if !ok {
-		return -1
-	}
-	if deque.size != otherDeque.size {
-		return deque.size - otherDeque.size
-	}
-	for index, value := range deque.data {
-		cmp := tau.Cmp(value, otherDeque.data[index])
-		if cmp != 0 {
-			return cmp
-		}
-	}
-	return 0
+	return tau.CollCmp[T](deque, other)
 }
 
 func (deque *ArrDeque[T]) Iter() tau.Iterator[T] {
@@ -156,26 +143,23 @@ func (deque *ArrDeque[T]) LIFOIter() tau.Iterator[T] {
 type adqIter[T any] struct {
 	deque *ArrDeque[T]
 	lifo  bool
+	index int
 }
 
 func newAdqIter[T any](deque *ArrDeque[T], lifo bool) *adqIter[T] {
-	return &adqIter[T]{deque, lifo}
+	return &adqIter[T]{deque, lifo, 0}
 }
 
 func (iter *adqIter[T]) Next() (*T, bool) {
-	if iter.deque.size == 0 {
+	if iter.index >= iter.deque.size {
 		return nil, false
 	}
-	var val T
+	actIdx := iter.index
 	if iter.lifo {
-		val = iter.deque.data[iter.deque.size-1]
-		iter.deque.data = iter.deque.data[:iter.deque.size-1]
-	} else {
-		val = iter.deque.data[0]
-		iter.deque.data = iter.deque.data[1:]
+		actIdx = iter.deque.size - iter.index - 1
 	}
-	iter.deque.size--
-	return &val, true
+	iter.index++
+	return &iter.deque.data[actIdx], true
 }
 
 func (iter *adqIter[T]) Each(f func(T)) {
@@ -35,7 +35,7 @@ func (deque *LkDeque[T]) String() string {
 }
 
 func (deque *LkDeque[T]) Cmp(other any) int {
-	return deque.inner.Cmp(other)
+	return tau.CollCmp[T](deque, other)
 }
 
 func (deque *LkDeque[T]) Iter() tau.Iterator[T] {
@@ -1,7 +1,52 @@
 // This package contains custom error and error messages
 package errs
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors, to check the kind of an error with [errors.Is], e.g.
+//
+//	if errors.Is(err, errs.ErrIndexOutOfRange) { ... }
+//
+// Every error of this package matches the sentinel of its kind
+var (
+	ErrNotFound               = errors.New("not found")
+	ErrEmpty                  = errors.New("empty collection")
+	ErrCycle                  = errors.New("cycle found")
+	ErrNegativeWeight         = errors.New("negative weight")
+	ErrMismatch               = errors.New("mismatch")
+	ErrIndexOutOfRange        = errors.New("index out of range")
+	ErrTypeMismatch           = errors.New("type mismatch")
+	ErrCapacityExceeded       = errors.New("capacity exceeded")
+	ErrUnsupportedOp          = errors.New("unsupported operation")
+	ErrConcurrentModification = errors.New("concurrent modification")
+)
+
+// Where an error happened, embedded in the errors that carry it
+type Context struct {
+	// The name of the operation, e.g. "Get"
+	Op string
+	// The type of the collection, e.g. "*list.ArrList[int]"
+	// It's empty for the functions that are not bound to a collection
+	Coll string
+}
+
+func newContext(op string, coll any) Context {
+	if coll == nil {
+		return Context{op, ""}
+	}
+	return Context{op, fmt.Sprintf("%T", coll)}
+}
+
+// Returns the context as a prefix for the error messages
+func (ctx Context) String() string {
+	if ctx.Coll == "" {
+		return fmt.Sprintf("[%s]", ctx.Op)
+	}
+	return fmt.Sprintf("[%s.%s]", ctx.Coll, ctx.Op)
+}
 
 // This error is thrown when a method attempts to access an element,
 // in a collection, that does not exist
@@ -18,6 +63,10 @@ func (err NotFoundErr) Error() string {
 	return fmt.Sprintf("Element %v not found", err.Value)
 }
 
+func (err NotFoundErr) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // This error is thrown when a method attempts to Get/Peek/Pop/Remove
 // from an empty collection
 type EmptyErr struct{}
@@ -29,3 +78,165 @@ func Empty() EmptyErr {
 func (err EmptyErr) Error() string {
 	return "Attempted to Get/Peek/Pop/Remove from an empty collection"
 }
+
+func (err EmptyErr) Is(target error) bool {
+	return target == ErrEmpty
+}
+
+// This error is returned when an operation requires a graph without
+// cycles (or without negative cycles) and one is found
+type CycleErr struct {
+	// The vertices of the cycle, in order, with the first one repeated at the end
+	Cycle any
+}
+
+func Cycle(c any) CycleErr {
+	return CycleErr{c}
+}
+
+func (err CycleErr) Error() string {
+	return fmt.Sprintf("Cycle found: %v", err.Cycle)
+}
+
+func (err CycleErr) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// This error is returned when an algorithm that requires non-negative
+// weights finds an edge with a negative one
+type NegativeWeightErr struct {
+	// The edge with the negative weight
+	Edge any
+}
+
+func NegativeWeight(e any) NegativeWeightErr {
+	return NegativeWeightErr{e}
+}
+
+func (err NegativeWeightErr) Error() string {
+	return fmt.Sprintf("Edge %v has a negative weight", err.Edge)
+}
+
+func (err NegativeWeightErr) Is(target error) bool {
+	return target == ErrNegativeWeight
+}
+
+// This error is returned when two objects, or an object and its
+// serialized form, are not compatible with each other
+type MismatchErr struct {
+	// The expected value, e.g. a size or a kind
+	Expected any
+	// The value that was found instead
+	Found any
+}
+
+func Mismatch(expected, found any) MismatchErr {
+	return MismatchErr{expected, found}
+}
+
+func (err MismatchErr) Error() string {
+	return fmt.Sprintf("Mismatch: expected %v, found %v", err.Expected, err.Found)
+}
+
+func (err MismatchErr) Is(target error) bool {
+	return target == ErrMismatch
+}
+
+// This error is returned when a method is given an index, or a count,
+// outside of the range allowed by the collection
+type IndexOutOfRangeErr struct {
+	Context
+	// The given index
+	Index int
+	// The size of the collection. Valid indexes are in [0, Size)
+	Size int
+}
+
+func IndexOutOfRange(op string, coll any, index, size int) IndexOutOfRangeErr {
+	return IndexOutOfRangeErr{newContext(op, coll), index, size}
+}
+
+func (err IndexOutOfRangeErr) Error() string {
+	return fmt.Sprintf("%v index %d out of range for size %d", err.Context, err.Index, err.Size)
+}
+
+func (err IndexOutOfRangeErr) Is(target error) bool {
+	return target == ErrIndexOutOfRange
+}
+
+// This error is returned, or used to panic, when a value is not of the
+// type required by an operation, e.g. when comparing incomparable values
+type TypeMismatchErr struct {
+	Context
+	// The name of the expected type
+	Expected string
+	// The name of the type that was found instead
+	Found string
+}
+
+func TypeMismatch(op string, coll any, expected string, found any) TypeMismatchErr {
+	return TypeMismatchErr{newContext(op, coll), expected, fmt.Sprintf("%T", found)}
+}
+
+func (err TypeMismatchErr) Error() string {
+	return fmt.Sprintf("%v expected %s, found %s", err.Context, err.Expected, err.Found)
+}
+
+func (err TypeMismatchErr) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+// This error is returned when an operation would grow a collection
+// beyond its capacity
+type CapacityExceededErr struct {
+	Context
+	// The maximum number of elements
+	Capacity int
+}
+
+func CapacityExceeded(op string, coll any, capacity int) CapacityExceededErr {
+	return CapacityExceededErr{newContext(op, coll), capacity}
+}
+
+func (err CapacityExceededErr) Error() string {
+	return fmt.Sprintf("%v capacity of %d exceeded", err.Context, err.Capacity)
+}
+
+func (err CapacityExceededErr) Is(target error) bool {
+	return target == ErrCapacityExceeded
+}
+
+// This error is returned when a collection does not support an operation
+type UnsupportedOpErr struct {
+	Context
+}
+
+func UnsupportedOp(op string, coll any) UnsupportedOpErr {
+	return UnsupportedOpErr{newContext(op, coll)}
+}
+
+func (err UnsupportedOpErr) Error() string {
+	return fmt.Sprintf("%v operation not supported", err.Context)
+}
+
+func (err UnsupportedOpErr) Is(target error) bool {
+	return target == ErrUnsupportedOp
+}
+
+// This error is returned when a collection is changed while an
+// operation is iterating over it
+type ConcurrentModificationErr struct {
+	Context
+}
+
+func ConcurrentModification(op string, coll any) ConcurrentModificationErr {
+	return ConcurrentModificationErr{newContext(op, coll)}
+}
+
+func (err ConcurrentModificationErr) Error() string {
+	return fmt.Sprintf("%v collection changed while iterating", err.Context)
+}
+
+func (err ConcurrentModificationErr) Is(target error) bool {
+	return target == ErrConcurrentModification
+}
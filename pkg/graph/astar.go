@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Estimate of the distance from a vertex to the target of a search
+type Heuristic[V any] func(V) float64
+
+// Returns the vertices along the shortest path between the given ones,
+// both included, and its length, found with the A* algorithm
+// Returns an error if there's no path, or a [errs.NegativeWeightErr] if
+// an edge with a negative weight is met
+//
+// The result is a shortest path as long as the heuristic never
+// overestimates the actual distance. A heuristic always returning 0
+// makes it the same as [Dijkstra].
+func AStar[V any](graph Graph[V], from, to V, heuristic Heuristic[V]) (tau.List[V], float64, error) {
+	paths := newShortestPaths(from)
+	done := table.Hsh[V, bool]()
+	queue := &pqueue[V]{}
+	queue.push(from, heuristic(from))
+	for !queue.empty() {
+		item := queue.pop()
+		if done.HasKey(item.vertex) {
+			continue
+		}
+		if tau.Eq(item.vertex, to) {
+			path, _ := paths.PathTo(to)
+			dist, _ := paths.DistTo(to)
+			return path, dist, nil
+		}
+		done.Put(item.vertex, true)
+		dist, _ := paths.DistTo(item.vertex)
+		iter := graph.Neighbors(item.vertex)
+		for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+			weight, _ := graph.Weight(item.vertex, *next)
+			if weight < 0 {
+				return nil, 0, errs.NegativeWeight(Edge[V]{item.vertex, *next, weight})
+			}
+			if paths.relax(item.vertex, *next, dist+weight) {
+				// a vertex already done can be reopened if the heuristic is not consistent
+				done.Remove(*next)
+				queue.push(*next, dist+weight+heuristic(*next))
+			}
+		}
+	}
+	return nil, 0, errs.NotFound(to)
+}
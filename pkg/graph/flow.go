@@ -0,0 +1,180 @@
+package graph
+
+import (
+	"math"
+
+	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Maximum flow between two vertices, along with the corresponding minimum cut
+type Flow[V any] struct {
+	value    float64
+	vertices []V
+	ids      *table.HshMap[V, int]
+	arcs     [][]flowArc
+	// vertices reachable from the source in the residual graph
+	sourceSide []bool
+}
+
+// Returns the total amount of flow from the source to the sink
+func (flow *Flow[V]) Value() float64 {
+	return flow.value
+}
+
+// Returns the amount of flow along the edge between the given vertices
+// It's zero if there's no such edge
+func (flow *Flow[V]) FlowOn(from, to V) float64 {
+	fromId, err := flow.ids.Get(from)
+	if err != nil {
+		return 0
+	}
+	toId, err := flow.ids.Get(to)
+	if err != nil {
+		return 0
+	}
+	for _, arc := range flow.arcs[*fromId] {
+		if arc.to == *toId && arc.capacity > 0 {
+			return math.Max(arc.flow, 0)
+		}
+	}
+	return 0
+}
+
+// Returns the vertices on the source side of a minimum cut
+func (flow *Flow[V]) SourceSide() tau.Set[V] {
+	side := set.Hsh[V]()
+	for id, vertex := range flow.vertices {
+		if flow.sourceSide[id] {
+			side.Add(vertex)
+		}
+	}
+	return side
+}
+
+// Returns the edges of a minimum cut, whose capacities sum up to the flow value
+func (flow *Flow[V]) CutEdges() tau.List[Edge[V]] {
+	cut := list.Arr[Edge[V]]()
+	for id, arcs := range flow.arcs {
+		if !flow.sourceSide[id] {
+			continue
+		}
+		for _, arc := range arcs {
+			if arc.capacity > 0 && !flow.sourceSide[arc.to] {
+				cut.Append(Edge[V]{flow.vertices[id], flow.vertices[arc.to], arc.capacity})
+			}
+		}
+	}
+	return cut
+}
+
+// Finds the maximum flow from the source to the sink with Dinic's algorithm,
+// using the edge weights as capacities
+// Returns an error if one of the vertices is not in the graph, or a
+// [errs.NegativeWeightErr] if an edge has a negative capacity
+//
+// For undirected graphs, every edge can carry flow in both directions.
+func MaxFlow[V any](graph Graph[V], source, sink V) (*Flow[V], error) {
+	vertices, ids := indexVertices(graph)
+	s, err := ids.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	t, err := ids.Get(sink)
+	if err != nil {
+		return nil, err
+	}
+	flow := &Flow[V]{0, vertices, ids, make([][]flowArc, len(vertices)), make([]bool, len(vertices))}
+	for from, vertex := range vertices {
+		iter := graph.Neighbors(vertex)
+		for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+			capacity, _ := graph.Weight(vertex, *next)
+			if capacity < 0 {
+				return nil, errs.NegativeWeight(Edge[V]{vertex, *next, capacity})
+			}
+			to, _ := ids.Get(*next)
+			flow.addArc(from, *to, capacity)
+		}
+	}
+	if *s != *t {
+		level := make([]int, len(vertices))
+		next := make([]int, len(vertices))
+		for flow.levels(*s, level); level[*t] >= 0; flow.levels(*s, level) {
+			for i := range next {
+				next[i] = 0
+			}
+			for pushed := flow.augment(*s, *t, math.Inf(1), level, next); pushed > 0; pushed = flow.augment(*s, *t, math.Inf(1), level, next) {
+				flow.value += pushed
+			}
+		}
+	}
+	level := make([]int, len(vertices))
+	flow.levels(*s, level)
+	for id := range level {
+		flow.sourceSide[id] = level[id] >= 0
+	}
+	return flow, nil
+}
+
+// --- Private methods ---
+
+// arc of the residual graph
+// Every edge becomes an arc with its capacity, paired with a reverse one
+// with no capacity; rev is the position of the pair in the arcs of "to"
+type flowArc struct {
+	to       int
+	rev      int
+	capacity float64
+	flow     float64
+}
+
+func (flow *Flow[V]) addArc(from, to int, capacity float64) {
+	flow.arcs[from] = append(flow.arcs[from], flowArc{to, len(flow.arcs[to]), capacity, 0})
+	if from == to {
+		flow.arcs[from][len(flow.arcs[from])-1].rev++
+	}
+	flow.arcs[to] = append(flow.arcs[to], flowArc{from, len(flow.arcs[from]) - 1, 0, 0})
+}
+
+// computes the BFS level of every vertex in the residual graph, -1 if unreachable
+func (flow *Flow[V]) levels(source int, level []int) {
+	for i := range level {
+		level[i] = -1
+	}
+	level[source] = 0
+	queue := deque.Arr(source)
+	for vertex, err := queue.PopFront(); err == nil; vertex, err = queue.PopFront() {
+		for _, arc := range flow.arcs[*vertex] {
+			if level[arc.to] < 0 && arc.capacity-arc.flow > 0 {
+				level[arc.to] = level[*vertex] + 1
+				queue.PushBack(arc.to)
+			}
+		}
+	}
+}
+
+// sends at most limit flow from the vertex to the sink along the level graph
+// Returns the amount sent
+func (flow *Flow[V]) augment(vertex, sink int, limit float64, level, next []int) float64 {
+	if vertex == sink {
+		return limit
+	}
+	for ; next[vertex] < len(flow.arcs[vertex]); next[vertex]++ {
+		arc := &flow.arcs[vertex][next[vertex]]
+		residual := arc.capacity - arc.flow
+		if residual <= 0 || level[arc.to] != level[vertex]+1 {
+			continue
+		}
+		pushed := flow.augment(arc.to, sink, math.Min(limit, residual), level, next)
+		if pushed > 0 {
+			arc.flow += pushed
+			flow.arcs[arc.to][arc.rev].flow -= pushed
+			return pushed
+		}
+	}
+	return 0
+}
@@ -0,0 +1,303 @@
+// This package contains graphs and graph algorithms
+//
+// Algorithms work on any type implementing the [Graph] interface, and
+// return their results as lexgo collections ([tau.List], [tau.Set] and
+// [tau.Map]). Vertices can be of any type accepted by [tau.Hash] and
+// [tau.Eq], since they are used as keys of hash maps and sets.
+package graph
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Generic graph, as seen by the algorithms of this package
+//
+// Unweighted graphs should report a weight of 1 for every edge.
+type Graph[V any] interface {
+	// Returns true if the edges are directed
+	Directed() bool
+	// Returns the number of vertices
+	Order() int
+	// Returns an iterator over the vertices
+	Vertices() tau.Iterator[V]
+	// Returns an iterator over the vertices reachable from the given
+	// one through a single edge. For undirected graphs, these are all
+	// the adjacent vertices
+	Neighbors(V) tau.Iterator[V]
+	// Returns the weight of the edge between the two given vertices
+	// Returns an error if there's no such edge
+	Weight(V, V) (float64, error)
+}
+
+// Edge of a graph
+type Edge[V any] struct {
+	From   V
+	To     V
+	Weight float64
+}
+
+func (edge Edge[V]) String() string {
+	return fmt.Sprintf("(%v -%v-> %v)", edge.From, edge.Weight, edge.To)
+}
+
+// Graph implemented with adjacency maps
+//
+// Every vertex is associated with the map of its neighbors to the weights
+// of the edges towards them. Directed graphs also keep the reverse map,
+// so that incoming edges can be found as fast as the outgoing ones.
+// Edges between a vertex and itself are allowed, while parallel edges
+// are not: adding an existing edge updates its weight.
+type AdjGraph[V any] struct {
+	directed bool
+	out      *table.HshMap[V, *table.HshMap[V, float64]]
+	in       *table.HshMap[V, *table.HshMap[V, float64]]
+	edges    int
+}
+
+// Creates a new empty directed graph
+func Directed[V any]() *AdjGraph[V] {
+	return &AdjGraph[V]{true, table.Hsh[V, *table.HshMap[V, float64]](), table.Hsh[V, *table.HshMap[V, float64]](), 0}
+}
+
+// Creates a new empty undirected graph
+func Undirected[V any]() *AdjGraph[V] {
+	graph := &AdjGraph[V]{false, table.Hsh[V, *table.HshMap[V, float64]](), nil, 0}
+	graph.in = graph.out
+	return graph
+}
+
+// --- Methods from Graph[V] ---
+func (graph *AdjGraph[V]) Directed() bool {
+	return graph.directed
+}
+
+func (graph *AdjGraph[V]) Order() int {
+	return graph.out.Size()
+}
+
+func (graph *AdjGraph[V]) Vertices() tau.Iterator[V] {
+	return graph.out.Keys()
+}
+
+// The iterator is empty if the vertex is not in the graph
+func (graph *AdjGraph[V]) Neighbors(vertex V) tau.Iterator[V] {
+	adj, err := graph.out.Get(vertex)
+	if err != nil {
+		return table.Hsh[V, float64]().Keys()
+	}
+	return (*adj).Keys()
+}
+
+func (graph *AdjGraph[V]) Weight(from, to V) (float64, error) {
+	adj, err := graph.out.Get(from)
+	if err != nil {
+		return 0, err
+	}
+	weight, err := (*adj).Get(to)
+	if err != nil {
+		return 0, errs.NotFound(Edge[V]{from, to, 0})
+	}
+	return *weight, nil
+}
+
+// --- Vertices ---
+
+// Adds the given vertices, skipping the ones already present
+func (graph *AdjGraph[V]) AddVertex(vertices ...V) {
+	for _, vertex := range vertices {
+		if !graph.out.HasKey(vertex) {
+			graph.out.Put(vertex, table.Hsh[V, float64]())
+			if graph.directed {
+				graph.in.Put(vertex, table.Hsh[V, float64]())
+			}
+		}
+	}
+}
+
+// Removes the given vertex along with all its edges
+// Returns an error if the vertex is not in the graph
+func (graph *AdjGraph[V]) RemoveVertex(vertex V) error {
+	out, err := graph.out.Get(vertex)
+	if err != nil {
+		return err
+	}
+	in, _ := graph.in.Get(vertex)
+	outAdj, inAdj := *out, *in
+	outAdj.Keys().Each(func(to V) {
+		graph.RemoveEdge(vertex, to)
+	})
+	if graph.directed {
+		inAdj.Keys().Each(func(from V) {
+			graph.RemoveEdge(from, vertex)
+		})
+		graph.in.Remove(vertex)
+	}
+	graph.out.Remove(vertex)
+	return nil
+}
+
+func (graph *AdjGraph[V]) HasVertex(vertex V) bool {
+	return graph.out.HasKey(vertex)
+}
+
+// --- Edges ---
+
+// Adds an edge of weight 1 between the given vertices
+// Missing vertices are added too
+func (graph *AdjGraph[V]) AddEdge(from, to V) {
+	graph.AddWeightedEdge(from, to, 1)
+}
+
+// Adds an edge with the given weight between the given vertices,
+// or updates the weight if the edge exists
+// Missing vertices are added too
+func (graph *AdjGraph[V]) AddWeightedEdge(from, to V, weight float64) {
+	graph.AddVertex(from, to)
+	out, _ := graph.out.Get(from)
+	if !(*out).HasKey(to) {
+		graph.edges++
+	}
+	(*out).Put(to, weight)
+	in, _ := graph.in.Get(to)
+	(*in).Put(from, weight)
+}
+
+// Removes the edge between the given vertices
+// Returns an error if there's no such edge
+func (graph *AdjGraph[V]) RemoveEdge(from, to V) error {
+	out, err := graph.out.Get(from)
+	if err != nil {
+		return err
+	}
+	if _, err := (*out).Remove(to); err != nil {
+		return errs.NotFound(Edge[V]{from, to, 0})
+	}
+	if in, err := graph.in.Get(to); err == nil {
+		(*in).Remove(from)
+	}
+	graph.edges--
+	return nil
+}
+
+func (graph *AdjGraph[V]) HasEdge(from, to V) bool {
+	_, err := graph.Weight(from, to)
+	return err == nil
+}
+
+// Returns the number of edges
+func (graph *AdjGraph[V]) Size() int {
+	return graph.edges
+}
+
+// Returns an iterator over the vertices with an edge towards the given one
+// For undirected graphs, it's the same as [AdjGraph.Neighbors]
+func (graph *AdjGraph[V]) InNeighbors(vertex V) tau.Iterator[V] {
+	adj, err := graph.in.Get(vertex)
+	if err != nil {
+		return table.Hsh[V, float64]().Keys()
+	}
+	return (*adj).Keys()
+}
+
+// Returns the number of edges leaving the given vertex
+// For undirected graphs, it's the number of adjacent vertices
+func (graph *AdjGraph[V]) OutDegree(vertex V) int {
+	adj, err := graph.out.Get(vertex)
+	if err != nil {
+		return 0
+	}
+	return (*adj).Size()
+}
+
+// Returns the number of edges entering the given vertex
+// For undirected graphs, it's the number of adjacent vertices
+func (graph *AdjGraph[V]) InDegree(vertex V) int {
+	adj, err := graph.in.Get(vertex)
+	if err != nil {
+		return 0
+	}
+	return (*adj).Size()
+}
+
+// Returns an iterator over the edges
+// For undirected graphs, every edge is returned once
+func (graph *AdjGraph[V]) Edges() tau.Iterator[Edge[V]] {
+	return newEdgeIter(graph)
+}
+
+func (graph *AdjGraph[V]) String() string {
+	s := "AdjGraph{"
+	first := true
+	graph.Edges().Each(func(edge Edge[V]) {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += edge.String()
+	})
+	s += "}"
+	return s
+}
+
+// Makes a copy of the graph
+func (graph *AdjGraph[V]) Clone() *AdjGraph[V] {
+	var clone *AdjGraph[V]
+	if graph.directed {
+		clone = Directed[V]()
+	} else {
+		clone = Undirected[V]()
+	}
+	graph.Vertices().Each(func(vertex V) {
+		clone.AddVertex(vertex)
+	})
+	graph.Edges().Each(func(edge Edge[V]) {
+		clone.AddWeightedEdge(edge.From, edge.To, edge.Weight)
+	})
+	return clone
+}
+
+// --- Iterator ---
+type edgeIter[V any] struct {
+	graph    *AdjGraph[V]
+	vertices tau.Iterator[V]
+	from     V
+	adj      *table.HshMap[V, float64]
+	targets  tau.Iterator[V]
+	// vertices whose edges were all returned, for undirected graphs
+	seen *table.HshMap[V, bool]
+}
+
+func newEdgeIter[V any](graph *AdjGraph[V]) *edgeIter[V] {
+	return &edgeIter[V]{graph: graph, vertices: graph.Vertices(), seen: table.Hsh[V, bool]()}
+}
+
+func (iter *edgeIter[V]) Next() (*Edge[V], bool) {
+	for {
+		if iter.targets != nil {
+			for to, ok := iter.targets.Next(); ok; to, ok = iter.targets.Next() {
+				if !iter.graph.directed && iter.seen.HasKey(*to) {
+					continue
+				}
+				weight, _ := iter.adj.Get(*to)
+				return &Edge[V]{iter.from, *to, *weight}, true
+			}
+			iter.seen.Put(iter.from, true)
+		}
+		from, ok := iter.vertices.Next()
+		if !ok {
+			return nil, false
+		}
+		adj, _ := iter.graph.out.Get(*from)
+		iter.from, iter.adj, iter.targets = *from, *adj, (*adj).Keys()
+	}
+}
+
+func (iter *edgeIter[V]) Each(f func(Edge[V])) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
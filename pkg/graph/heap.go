@@ -0,0 +1,56 @@
+package graph
+
+// vertex with the priority it was queued with
+type pqItem[V any] struct {
+	vertex   V
+	priority float64
+}
+
+// binary min-heap of vertices, ordered by priority
+//
+// Priorities are never decreased: a vertex is pushed again with the new
+// priority, and the stale items are skipped by the caller when popped.
+type pqueue[V any] struct {
+	items []pqItem[V]
+}
+
+func (queue *pqueue[V]) empty() bool {
+	return len(queue.items) == 0
+}
+
+func (queue *pqueue[V]) push(vertex V, priority float64) {
+	queue.items = append(queue.items, pqItem[V]{vertex, priority})
+	i := len(queue.items) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if queue.items[parent].priority <= queue.items[i].priority {
+			break
+		}
+		queue.items[parent], queue.items[i] = queue.items[i], queue.items[parent]
+		i = parent
+	}
+}
+
+// the queue must not be empty
+func (queue *pqueue[V]) pop() pqItem[V] {
+	top := queue.items[0]
+	last := len(queue.items) - 1
+	queue.items[0] = queue.items[last]
+	queue.items = queue.items[:last]
+	i := 0
+	for {
+		min, left, right := i, 2*i+1, 2*i+2
+		if left < last && queue.items[left].priority < queue.items[min].priority {
+			min = left
+		}
+		if right < last && queue.items[right].priority < queue.items[min].priority {
+			min = right
+		}
+		if min == i {
+			break
+		}
+		queue.items[min], queue.items[i] = queue.items[i], queue.items[min]
+		i = min
+	}
+	return top
+}
@@ -0,0 +1,202 @@
+package graph
+
+import (
+	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Splits the vertices of the given graph in two sets, such that every edge
+// goes from one set to the other
+// Returns a [errs.CycleErr], holding a [tau.List] with a cycle of odd
+// length, if the graph is not bipartite
+//
+// For directed graphs, the direction of the edges is ignored.
+func Bipartition[V any](graph Graph[V]) (tau.Set[V], tau.Set[V], error) {
+	adjacent := undirectedNeighbors(graph)
+	side := table.Hsh[V, bool]()
+	parent := table.Hsh[V, V]()
+	var oddCycle tau.List[V]
+	graph.Vertices().Each(func(root V) {
+		if oddCycle != nil || side.HasKey(root) {
+			return
+		}
+		side.Put(root, false)
+		queue := deque.Arr(root)
+		for vertex, err := queue.PopFront(); err == nil && oddCycle == nil; vertex, err = queue.PopFront() {
+			current, _ := side.Get(*vertex)
+			iter := adjacent(*vertex)
+			for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+				other, err := side.Get(*next)
+				if err != nil {
+					side.Put(*next, !*current)
+					parent.Put(*next, *vertex)
+					queue.PushBack(*next)
+				} else if *other == *current {
+					oddCycle = joinTreePaths(parent, *vertex, *next)
+					break
+				}
+			}
+		}
+	})
+	if oddCycle != nil {
+		return nil, nil, errs.Cycle(oddCycle)
+	}
+	left, right := set.Hsh[V](), set.Hsh[V]()
+	side.Keys().Each(func(vertex V) {
+		if isRight, _ := side.Get(vertex); *isRight {
+			right.Add(vertex)
+		} else {
+			left.Add(vertex)
+		}
+	})
+	return left, right, nil
+}
+
+// Finds a maximum matching of the given bipartite graph with the
+// Hopcroft-Karp algorithm
+// The result maps every matched vertex of the first set returned by
+// [Bipartition] to its mate
+// Returns the same error of [Bipartition] if the graph is not bipartite
+func HopcroftKarp[V any](graph Graph[V]) (tau.Map[V, V], error) {
+	left, right, err := Bipartition(graph)
+	if err != nil {
+		return nil, err
+	}
+	leftVertices, rightVertices := collectSet(left), collectSet(right)
+	rightIds := table.Hsh[V, int]()
+	for id, vertex := range rightVertices {
+		rightIds.Put(vertex, id)
+	}
+	adjacent := undirectedNeighbors(graph)
+	m := &matcher{adj: make([][]int, len(leftVertices))}
+	for id, vertex := range leftVertices {
+		adjacent(vertex).Each(func(next V) {
+			if other, err := rightIds.Get(next); err == nil {
+				m.adj[id] = append(m.adj[id], *other)
+			}
+		})
+	}
+	m.run(len(rightVertices))
+	matching := table.Hsh[V, V]()
+	for id, mate := range m.mateOfLeft {
+		if mate >= 0 {
+			matching.Put(leftVertices[id], rightVertices[mate])
+		}
+	}
+	return matching, nil
+}
+
+// --- Private functions ---
+
+// builds the cycle made of the tree paths from the two given vertices to
+// their common ancestor, closed by the edge between them
+func joinTreePaths[V any](parent *table.HshMap[V, V], u, v V) tau.List[V] {
+	ancestors := table.Hsh[V, bool]()
+	ancestors.Put(u, true)
+	for prev, err := parent.Get(u); err == nil; prev, err = parent.Get(*prev) {
+		ancestors.Put(*prev, true)
+	}
+	// path from v up to the common ancestor
+	fromV := list.Arr(v)
+	common := v
+	for !ancestors.HasKey(common) {
+		prev, _ := parent.Get(common)
+		common = *prev
+		fromV.Append(common)
+	}
+	// path from u up to the common ancestor, in reverse
+	cycle := list.Arr(u)
+	for vertex := u; !tau.Eq(vertex, common); {
+		prev, _ := parent.Get(vertex)
+		vertex = *prev
+		cycle.Prepend(vertex)
+	}
+	// from the common ancestor down to u, then v up to the common ancestor
+	fromV.Iter().Each(func(vertex V) {
+		cycle.Append(vertex)
+	})
+	return cycle
+}
+
+func collectSet[V any](s tau.Set[V]) []V {
+	res := make([]V, 0, s.Size())
+	s.Iter().Each(func(vertex V) {
+		res = append(res, vertex)
+	})
+	return res
+}
+
+// Hopcroft-Karp over integer vertices: left ones in [0, len(adj)),
+// right ones in [0, n) as given to run
+type matcher struct {
+	adj         [][]int
+	mateOfLeft  []int
+	mateOfRight []int
+	dist        []int
+}
+
+func (m *matcher) run(n int) {
+	m.mateOfLeft = make([]int, len(m.adj))
+	m.mateOfRight = make([]int, n)
+	m.dist = make([]int, len(m.adj))
+	for i := range m.mateOfLeft {
+		m.mateOfLeft[i] = -1
+	}
+	for i := range m.mateOfRight {
+		m.mateOfRight[i] = -1
+	}
+	for m.layers() {
+		for u := range m.adj {
+			if m.mateOfLeft[u] < 0 {
+				m.augment(u)
+			}
+		}
+	}
+}
+
+// layers the left vertices by the length of the shortest alternating path
+// from a free one
+// Returns true if a free right vertex is reachable
+func (m *matcher) layers() bool {
+	queue := deque.Arr[int]()
+	for u := range m.adj {
+		if m.mateOfLeft[u] < 0 {
+			m.dist[u] = 0
+			queue.PushBack(u)
+		} else {
+			m.dist[u] = -1
+		}
+	}
+	found := false
+	for u, err := queue.PopFront(); err == nil; u, err = queue.PopFront() {
+		for _, v := range m.adj[*u] {
+			mate := m.mateOfRight[v]
+			if mate < 0 {
+				found = true
+			} else if m.dist[mate] < 0 {
+				m.dist[mate] = m.dist[*u] + 1
+				queue.PushBack(mate)
+			}
+		}
+	}
+	return found
+}
+
+// looks for an augmenting path from the given left vertex along the layers
+func (m *matcher) augment(u int) bool {
+	for _, v := range m.adj[u] {
+		mate := m.mateOfRight[v]
+		if mate < 0 || (m.dist[mate] == m.dist[u]+1 && m.augment(mate)) {
+			m.mateOfLeft[u] = v
+			m.mateOfRight[v] = u
+			return true
+		}
+	}
+	// dead end for this phase
+	m.dist[u] = -1
+	return false
+}
@@ -0,0 +1,258 @@
+package graph
+
+import (
+	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Returns the vertices of the given directed graph in topological order,
+// that is, every vertex comes before the ones its edges point to
+// Returns a [errs.CycleErr], holding a [tau.List] with one of the cycles,
+// if the graph is not acyclic, or an [errs.UnsupportedOpErr] if it's
+// undirected
+func TopoSort[V any](graph Graph[V]) (tau.List[V], error) {
+	if !graph.Directed() {
+		return nil, errs.UnsupportedOp("TopoSort", graph)
+	}
+	inDegree := table.Hsh[V, int]()
+	graph.Vertices().Each(func(vertex V) {
+		if !inDegree.HasKey(vertex) {
+			inDegree.Put(vertex, 0)
+		}
+		graph.Neighbors(vertex).Each(func(next V) {
+			degree, err := inDegree.Get(next)
+			if err != nil {
+				inDegree.Put(next, 1)
+			} else {
+				inDegree.Put(next, *degree+1)
+			}
+		})
+	})
+	queue := deque.Arr[V]()
+	inDegree.Keys().Each(func(vertex V) {
+		if degree, _ := inDegree.Get(vertex); *degree == 0 {
+			queue.PushBack(vertex)
+		}
+	})
+	order := list.Arr[V]()
+	for vertex, err := queue.PopFront(); err == nil; vertex, err = queue.PopFront() {
+		order.Append(*vertex)
+		inDegree.Remove(*vertex)
+		graph.Neighbors(*vertex).Each(func(next V) {
+			degree, _ := inDegree.Get(next)
+			left := *degree - 1
+			inDegree.Put(next, left)
+			if left == 0 {
+				queue.PushBack(next)
+			}
+		})
+	}
+	if !inDegree.Empty() {
+		// the vertices left are on a cycle or reachable from one
+		return nil, errs.Cycle(findCycle(graph, inDegree))
+	}
+	return order, nil
+}
+
+// Returns the connected components of the given graph
+// For directed graphs, the weakly connected ones are returned, that is,
+// the direction of the edges is ignored
+func ConnectedComponents[V any](graph Graph[V]) tau.List[tau.Set[V]] {
+	adjacent := undirectedNeighbors(graph)
+	components := list.Arr[tau.Set[V]]()
+	visited := set.Hsh[V]()
+	graph.Vertices().Each(func(start V) {
+		if visited.Contains(start) {
+			return
+		}
+		component := set.Hsh[V]()
+		component.Add(start)
+		visited.Add(start)
+		queue := deque.Arr(start)
+		for vertex, err := queue.PopFront(); err == nil; vertex, err = queue.PopFront() {
+			adjacent(*vertex).Each(func(next V) {
+				if !visited.Contains(next) {
+					visited.Add(next)
+					component.Add(next)
+					queue.PushBack(next)
+				}
+			})
+		}
+		components.Append(component)
+	})
+	return components
+}
+
+// Returns the strongly connected components of the given graph,
+// in reverse topological order of the condensed graph
+//
+// It's an iterative version of Tarjan's algorithm.
+// For undirected graphs, it's the same as [ConnectedComponents].
+func StronglyConnectedComponents[V any](graph Graph[V]) tau.List[tau.Set[V]] {
+	components := list.Arr[tau.Set[V]]()
+	index := table.Hsh[V, int]()
+	lowLink := table.Hsh[V, int]()
+	onStack := set.Hsh[V]()
+	stack := deque.Arr[V]()
+	calls := deque.Arr[dfsFrame[V]]()
+	counter := 0
+
+	visit := func(vertex V) {
+		index.Put(vertex, counter)
+		lowLink.Put(vertex, counter)
+		counter++
+		stack.PushBack(vertex)
+		onStack.Add(vertex)
+		calls.PushBack(dfsFrame[V]{vertex, graph.Neighbors(vertex)})
+	}
+	lower := func(vertex V, value int) {
+		if low, _ := lowLink.Get(vertex); value < *low {
+			lowLink.Put(vertex, value)
+		}
+	}
+
+	graph.Vertices().Each(func(start V) {
+		if index.HasKey(start) {
+			return
+		}
+		visit(start)
+		for !calls.Empty() {
+			frame, _ := calls.Back()
+			descended := false
+			for next, ok := frame.neighbors.Next(); ok; next, ok = frame.neighbors.Next() {
+				if !index.HasKey(*next) {
+					visit(*next)
+					descended = true
+					break
+				}
+				if onStack.Contains(*next) {
+					nextIndex, _ := index.Get(*next)
+					lower(frame.vertex, *nextIndex)
+				}
+			}
+			if descended {
+				continue
+			}
+			vertex := frame.vertex
+			calls.PopBack()
+			low, _ := lowLink.Get(vertex)
+			if caller, err := calls.Back(); err == nil {
+				lower(caller.vertex, *low)
+			}
+			if idx, _ := index.Get(vertex); *idx == *low {
+				component := set.Hsh[V]()
+				for {
+					member, _ := stack.PopBack()
+					onStack.Remove(*member)
+					component.Add(*member)
+					if tau.Eq(*member, vertex) {
+						break
+					}
+				}
+				components.Append(component)
+			}
+		}
+	})
+	return components
+}
+
+// --- Private functions ---
+
+// returns a function giving the adjacent vertices of a vertex,
+// regardless of the direction of the edges
+func undirectedNeighbors[V any](graph Graph[V]) func(V) tau.Iterator[V] {
+	if !graph.Directed() {
+		return graph.Neighbors
+	}
+	reverse := table.Hsh[V, *list.ArrList[V]]()
+	graph.Vertices().Each(func(vertex V) {
+		graph.Neighbors(vertex).Each(func(next V) {
+			from, err := reverse.Get(next)
+			if err != nil {
+				reverse.Put(next, list.Arr(vertex))
+			} else {
+				(*from).Append(vertex)
+			}
+		})
+	})
+	return func(vertex V) tau.Iterator[V] {
+		both := list.Arr[V]()
+		graph.Neighbors(vertex).Each(func(next V) {
+			both.Append(next)
+		})
+		if from, err := reverse.Get(vertex); err == nil {
+			(*from).Iter().Each(func(prev V) {
+				both.Append(prev)
+			})
+		}
+		return both.Iter()
+	}
+}
+
+// returns the vertices of the graph, along with the map from each one to its position
+func indexVertices[V any](graph Graph[V]) ([]V, *table.HshMap[V, int]) {
+	vertices := make([]V, 0, graph.Order())
+	ids := table.Hsh[V, int]()
+	graph.Vertices().Each(func(vertex V) {
+		ids.Put(vertex, len(vertices))
+		vertices = append(vertices, vertex)
+	})
+	return vertices, ids
+}
+
+// finds a cycle among the given vertices, every one of which must have
+// an incoming edge from another one of them
+func findCycle[V any](graph Graph[V], among *table.HshMap[V, int]) tau.List[V] {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	color := table.Hsh[V, int]()
+	parent := table.Hsh[V, V]()
+	calls := deque.Arr[dfsFrame[V]]()
+	iter := among.Keys()
+	for start, ok := iter.Next(); ok; start, ok = iter.Next() {
+		if color.HasKey(*start) {
+			continue
+		}
+		color.Put(*start, visiting)
+		calls.PushBack(dfsFrame[V]{*start, graph.Neighbors(*start)})
+		for !calls.Empty() {
+			frame, _ := calls.Back()
+			descended := false
+			for next, ok := frame.neighbors.Next(); ok; next, ok = frame.neighbors.Next() {
+				if !among.HasKey(*next) {
+					continue
+				}
+				state, err := color.Get(*next)
+				if err != nil {
+					color.Put(*next, visiting)
+					parent.Put(*next, frame.vertex)
+					calls.PushBack(dfsFrame[V]{*next, graph.Neighbors(*next)})
+					descended = true
+					break
+				}
+				if *state == visiting {
+					// back edge, the cycle goes from next to the current vertex
+					cycle := list.Arr(frame.vertex)
+					for vertex := frame.vertex; !tau.Eq(vertex, *next); {
+						prev, _ := parent.Get(vertex)
+						vertex = *prev
+						cycle.Prepend(vertex)
+					}
+					cycle.Append(*next)
+					return cycle
+				}
+			}
+			if !descended {
+				color.Put(frame.vertex, done)
+				calls.PopBack()
+			}
+		}
+	}
+	return list.Arr[V]()
+}
@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Shortest paths from a single source vertex to all the reachable ones
+type ShortestPaths[V any] struct {
+	source V
+	dist   *table.HshMap[V, float64]
+	// previous vertex along the shortest path
+	prev *table.HshMap[V, V]
+}
+
+func newShortestPaths[V any](source V) *ShortestPaths[V] {
+	paths := &ShortestPaths[V]{source, table.Hsh[V, float64](), table.Hsh[V, V]()}
+	paths.dist.Put(source, 0)
+	return paths
+}
+
+// Returns the vertex the paths start from
+func (paths *ShortestPaths[V]) Source() V {
+	return paths.source
+}
+
+// Returns a map from every reachable vertex to its distance from the source
+// A copy is made, so the result can be freely modified
+func (paths *ShortestPaths[V]) Distances() tau.Map[V, float64] {
+	return paths.dist.Clone().(*table.HshMap[V, float64])
+}
+
+// Returns the distance of the given vertex from the source
+// Returns an error if the vertex is not reachable
+func (paths *ShortestPaths[V]) DistTo(vertex V) (float64, error) {
+	dist, err := paths.dist.Get(vertex)
+	if err != nil {
+		return 0, err
+	}
+	return *dist, nil
+}
+
+func (paths *ShortestPaths[V]) HasPathTo(vertex V) bool {
+	return paths.dist.HasKey(vertex)
+}
+
+// Returns the vertices along the shortest path from the source to the
+// given vertex, both included
+// Returns an error if the vertex is not reachable
+func (paths *ShortestPaths[V]) PathTo(vertex V) (tau.List[V], error) {
+	if !paths.dist.HasKey(vertex) {
+		return nil, errs.NotFound(vertex)
+	}
+	path := list.Arr(vertex)
+	for prev, err := paths.prev.Get(vertex); err == nil; prev, err = paths.prev.Get(*prev) {
+		path.Prepend(*prev)
+	}
+	return path, nil
+}
+
+// Finds the shortest paths from the given vertex with Dijkstra's algorithm
+// Returns a [errs.NegativeWeightErr] if a reachable edge has a negative weight
+func Dijkstra[V any](graph Graph[V], source V) (*ShortestPaths[V], error) {
+	paths := newShortestPaths(source)
+	done := table.Hsh[V, bool]()
+	queue := &pqueue[V]{}
+	queue.push(source, 0)
+	for !queue.empty() {
+		item := queue.pop()
+		if done.HasKey(item.vertex) {
+			continue
+		}
+		done.Put(item.vertex, true)
+		iter := graph.Neighbors(item.vertex)
+		for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+			weight, _ := graph.Weight(item.vertex, *next)
+			if weight < 0 {
+				return nil, errs.NegativeWeight(Edge[V]{item.vertex, *next, weight})
+			}
+			if paths.relax(item.vertex, *next, item.priority+weight) {
+				queue.push(*next, item.priority+weight)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// Finds the shortest paths from the given vertex with the Bellman-Ford
+// algorithm, which allows negative weights
+// Returns a [errs.CycleErr], holding a [tau.List] with the cycle, if a
+// negative cycle is reachable from the source
+//
+// In undirected graphs, every edge with a negative weight is a negative cycle.
+func BellmanFord[V any](graph Graph[V], source V) (*ShortestPaths[V], error) {
+	paths := newShortestPaths(source)
+	// relaxes all the edges and returns the last updated vertex, if any
+	round := func() *V {
+		var updated *V
+		graph.Vertices().Each(func(from V) {
+			dist, err := paths.dist.Get(from)
+			if err != nil {
+				return
+			}
+			base := *dist
+			graph.Neighbors(from).Each(func(to V) {
+				weight, _ := graph.Weight(from, to)
+				if paths.relax(from, to, base+weight) {
+					updated = &to
+				}
+			})
+		})
+		return updated
+	}
+	for i := 1; i < graph.Order(); i++ {
+		if round() == nil {
+			return paths, nil
+		}
+	}
+	updated := round()
+	if updated == nil {
+		return paths, nil
+	}
+	// going back as many times as the vertices surely ends in the cycle
+	vertex := *updated
+	for i := 0; i < graph.Order(); i++ {
+		prev, _ := paths.prev.Get(vertex)
+		vertex = *prev
+	}
+	cycle := list.Arr(vertex)
+	for prev, _ := paths.prev.Get(vertex); !tau.Eq(*prev, vertex); prev, _ = paths.prev.Get(*prev) {
+		cycle.Prepend(*prev)
+	}
+	cycle.Prepend(vertex)
+	return nil, errs.Cycle(cycle)
+}
+
+// Returns the vertices along the shortest path between the given ones,
+// both included, and its length
+// Dijkstra's algorithm is used, unless there are negative weights
+// Returns an error if there's no path or if a negative cycle is found
+func ShortestPath[V any](graph Graph[V], from, to V) (tau.List[V], float64, error) {
+	paths, err := Dijkstra(graph, from)
+	if errors.As(err, new(errs.NegativeWeightErr)) {
+		paths, err = BellmanFord(graph, from)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	path, err := paths.PathTo(to)
+	if err != nil {
+		return nil, 0, err
+	}
+	dist, _ := paths.DistTo(to)
+	return path, dist, nil
+}
+
+// --- Private methods ---
+
+// updates the distance of the given vertex, if the new one is shorter
+// Returns true if the distance was updated
+func (paths *ShortestPaths[V]) relax(from, to V, dist float64) bool {
+	old, err := paths.dist.Get(to)
+	if err == nil && *old <= dist {
+		return false
+	}
+	paths.dist.Put(to, dist)
+	paths.prev.Put(to, from)
+	return true
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Returns the edges of a minimum spanning forest of the given undirected
+// graph, found with Kruskal's algorithm, along with their total weight
+//
+// If the graph is not connected, the result spans every component.
+// For directed graphs, the direction of the edges is ignored.
+func Kruskal[V any](graph Graph[V]) (tau.List[Edge[V]], float64) {
+	forest := set.Disjoint[V]()
+	edges := make([]Edge[V], 0)
+	graph.Vertices().Each(func(from V) {
+		forest.Add(from)
+		graph.Neighbors(from).Each(func(to V) {
+			weight, _ := graph.Weight(from, to)
+			edges = append(edges, Edge[V]{from, to, weight})
+		})
+	})
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].Weight < edges[j].Weight
+	})
+	tree, total := list.Arr[Edge[V]](), 0.0
+	for _, edge := range edges {
+		if forest.Union(edge.From, edge.To) {
+			tree.Append(edge)
+			total += edge.Weight
+		}
+	}
+	return tree, total
+}
+
+// Returns the edges of a minimum spanning forest of the given undirected
+// graph, found with Prim's algorithm, along with their total weight
+//
+// If the graph is not connected, the result spans every component.
+// For directed graphs, only the outgoing edges of the vertices already
+// in the tree are considered, so the result may not be minimum.
+func Prim[V any](graph Graph[V]) (tau.List[Edge[V]], float64) {
+	inTree := table.Hsh[V, bool]()
+	// lightest known edge reaching every vertex not in the tree
+	best := table.Hsh[V, Edge[V]]()
+	tree, total := list.Arr[Edge[V]](), 0.0
+	graph.Vertices().Each(func(root V) {
+		if inTree.HasKey(root) {
+			return
+		}
+		queue := &pqueue[V]{}
+		queue.push(root, 0)
+		for !queue.empty() {
+			item := queue.pop()
+			if inTree.HasKey(item.vertex) {
+				continue
+			}
+			inTree.Put(item.vertex, true)
+			if edge, err := best.Remove(item.vertex); err == nil {
+				tree.Append(*edge)
+				total += edge.Weight
+			}
+			graph.Neighbors(item.vertex).Each(func(next V) {
+				if inTree.HasKey(next) {
+					return
+				}
+				weight, _ := graph.Weight(item.vertex, next)
+				if edge, err := best.Get(next); err == nil && edge.Weight <= weight {
+					return
+				}
+				best.Put(next, Edge[V]{item.vertex, next, weight})
+				queue.push(next, weight)
+			})
+		}
+	})
+	return tree, total
+}
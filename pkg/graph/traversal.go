@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Returns an iterator visiting, in breadth-first order,
+// the vertices reachable from the given one
+func BFS[V any](graph Graph[V], start V) tau.Iterator[V] {
+	iter := &bfsIter[V]{graph, deque.Arr[V](start), set.Hsh[V]()}
+	iter.visited.Add(start)
+	return iter
+}
+
+// Returns an iterator visiting, in depth-first pre-order,
+// the vertices reachable from the given one
+//
+// The order is the same of a recursive visit, but an explicit stack is
+// used, so deep graphs do not overflow the call stack.
+func DFS[V any](graph Graph[V], start V) tau.Iterator[V] {
+	iter := &dfsIter[V]{graph, deque.Arr[dfsFrame[V]](), set.Hsh[V](), &start}
+	return iter
+}
+
+// --- Iterators ---
+type bfsIter[V any] struct {
+	graph   Graph[V]
+	queue   *deque.ArrDeque[V]
+	visited *set.HshSet[V]
+}
+
+func (iter *bfsIter[V]) Next() (*V, bool) {
+	vertex, err := iter.queue.PopFront()
+	if err != nil {
+		return nil, false
+	}
+	iter.graph.Neighbors(*vertex).Each(func(next V) {
+		if !iter.visited.Contains(next) {
+			iter.visited.Add(next)
+			iter.queue.PushBack(next)
+		}
+	})
+	return vertex, true
+}
+
+func (iter *bfsIter[V]) Each(f func(V)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+// vertex being visited, with the neighbors still to check
+type dfsFrame[V any] struct {
+	vertex    V
+	neighbors tau.Iterator[V]
+}
+
+type dfsIter[V any] struct {
+	graph   Graph[V]
+	stack   *deque.ArrDeque[dfsFrame[V]]
+	visited *set.HshSet[V]
+	// start vertex, until it's returned
+	start *V
+}
+
+func (iter *dfsIter[V]) Next() (*V, bool) {
+	if iter.start != nil {
+		start := *iter.start
+		iter.start = nil
+		return iter.visit(start), true
+	}
+	for !iter.stack.Empty() {
+		top, _ := iter.stack.Back()
+		for next, ok := top.neighbors.Next(); ok; next, ok = top.neighbors.Next() {
+			if !iter.visited.Contains(*next) {
+				return iter.visit(*next), true
+			}
+		}
+		iter.stack.PopBack()
+	}
+	return nil, false
+}
+
+func (iter *dfsIter[V]) visit(vertex V) *V {
+	iter.visited.Add(vertex)
+	iter.stack.PushBack(dfsFrame[V]{vertex, iter.graph.Neighbors(vertex)})
+	return &vertex
+}
+
+func (iter *dfsIter[V]) Each(f func(V)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
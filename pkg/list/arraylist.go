@@ -3,15 +3,16 @@ package list
 
 import (
 	"fmt"
-	"sort"
 
+	"github.com/luverolla/lexgo/pkg/algo"
 	"github.com/luverolla/lexgo/pkg/errs"
 	"github.com/luverolla/lexgo/pkg/tau"
 )
 
 // List implemented with a dynamic array
 type ArrList[T any] struct {
-	data []T
+	data   []T
+	strict bool
 }
 
 // Creates a new list implemented with a dynamic array
@@ -22,6 +23,15 @@ func Arr[T any](data ...T) *ArrList[T] {
 	return list
 }
 
+// Creates a new list implemented with a dynamic array, in strict mode
+// Indexes out of [-size, size) are errors instead of wrapping around,
+// see [ArrList.StrictGet]
+func StrictArr[T any](data ...T) *ArrList[T] {
+	list := Arr(data...)
+	list.strict = true
+	return list
+}
+
 // --- Methods from Collection[T] ---
 func (list *ArrList[T]) String() string {
 	s := "ArrList["
@@ -36,20 +46,7 @@ func (list *ArrList[T]) String() string {
 }
 
 func (list *ArrList[T]) Cmp(other any) int {
-	otherList, ok := other.(*ArrList[T])
-	if !ok {
-		return -1
-	}
-	if len(list.data) != len(otherList.data) {
-		return len(list.data) - len(otherList.data)
-	}
-	for index, value := range list.data {
-		cmp := tau.Cmp(value, otherList.data[index])
-		if cmp != 0 {
-			return cmp
-		}
-	}
-	return 0
+	return tau.CollCmp[T](list, other)
 }
 
 func (list *ArrList[T]) Iter() tau.Iterator[T] {
@@ -93,32 +90,41 @@ func (list *ArrList[T]) ContainsAny(other tau.Collection[T]) bool {
 }
 
 func (list *ArrList[T]) Clone() tau.Collection[T] {
-	return Arr(list.data...)
+	return list.derive(list.data)
 }
 
 // --- Methods from IdxedColl[T] ---
+// Returns an error if the list is empty or, in strict mode, if the index
+// is out of range. In strict mode, both are an [errs.IndexOutOfRangeErr]
 func (list *ArrList[T]) Get(index int) (*T, error) {
-	if list.Empty() {
-		return nil, errs.Empty()
-	}
-	index = list.sanify(index)
-	return &list.data[index], nil
+	return list.get("Get", index, list.strict)
 }
 
+// Panics if the list is empty or, in strict mode, if the index is out of range
+// See [ArrList.StrictSet] for a version returning the error
 func (list *ArrList[T]) Set(index int, data T) {
-	index = list.sanify(index)
-	list.data[index] = data
+	if err := list.set("Set", index, data, list.strict); err != nil {
+		panic(err)
+	}
 }
 
+// The index is sanified with size+1, as the end of the list is a valid
+// position too, so that Get(index) returns the new element afterwards
+// Panics in strict mode if the index is out of range
+// See [ArrList.StrictInsert] for a version returning the error
 func (list *ArrList[T]) Insert(index int, data T) {
-	list.data = append(list.data[:index], append([]T{data}, list.data[index:]...)...)
+	if err := list.insert("Insert", index, data, list.strict); err != nil {
+		panic(err)
+	}
 }
 
+// Returns an error if the list is empty or, in strict mode, if the index
+// is out of range. In strict mode, both are an [errs.IndexOutOfRangeErr]
 func (list *ArrList[T]) RemoveAt(index int) (*T, error) {
-	if list.Empty() {
-		return nil, errs.Empty()
+	index, err := sanify("RemoveAt", list, index, len(list.data), list.strict)
+	if err != nil {
+		return nil, err
 	}
-	index = list.sanify(index)
 	data := list.data[index]
 	list.data = append(list.data[:index], list.data[index+1:]...)
 	return &data, nil
@@ -142,9 +148,16 @@ func (list *ArrList[T]) LastIndexOf(data T) int {
 	return -1
 }
 
+// Panics if the list is empty or, in strict mode, if an index is out of range
 func (list *ArrList[T]) Swap(i, j int) {
-	i = list.sanify(i)
-	j = list.sanify(j)
+	i, err := sanify("Swap", list, i, len(list.data), list.strict)
+	if err != nil {
+		panic(err)
+	}
+	j, err = sanify("Swap", list, j, len(list.data), list.strict)
+	if err != nil {
+		panic(err)
+	}
 
 	if i == j {
 		return
@@ -153,19 +166,28 @@ func (list *ArrList[T]) Swap(i, j int) {
 	list.data[i], list.data[j] = list.data[j], list.data[i]
 }
 
+// In strict mode, the bounds must be in [-size, size], and they count
+// from the end if negative. It panics with an [errs.IndexOutOfRangeErr] otherwise
 func (list *ArrList[T]) Slice(start, end int) tau.IdxedColl[T] {
-	if start >= end || start == end {
-		return Arr[T]()
+	if list.strict {
+		start, end, err := strictBounds("Slice", list, start, end, len(list.data))
+		if err != nil {
+			panic(err)
+		}
+		return list.derive(list.data[start:end])
+	}
+	if list.Empty() || start == end {
+		return list.derive(nil)
 	}
 
-	var actStart = list.sanify(start)
-	var actEnd = list.sanify(end)
+	var actStart = wrap(start, len(list.data))
+	var actEnd = wrap(end-1, len(list.data)) + 1
 
 	if actStart > actEnd {
 		actStart, actEnd = actEnd, actStart
 	}
 
-	return Arr(list.data[actStart:actEnd]...)
+	return list.derive(list.data[actStart:actEnd])
 }
 
 // --- Methods from List[T] ---
@@ -201,10 +223,8 @@ func (list *ArrList[T]) RemoveAll(data T) error {
 func (list *ArrList[T]) Sort(comparator tau.Comparator[T]) tau.List[T] {
 	data := make([]T, len(list.data))
 	copy(data, list.data)
-	sort.Slice(data, func(i, j int) bool {
-		return comparator(data[i], data[j]) < 0
-	})
-	return Arr(data...)
+	algo.TimSortSlice(data, comparator)
+	return list.derive(data)
 }
 
 // create a new list with the data that satisfies the filter function
@@ -215,15 +235,69 @@ func (list *ArrList[T]) Sublist(filter tau.Filter[T]) tau.List[T] {
 			data = append(data, value)
 		}
 	}
-	return Arr(data...)
+	return list.derive(data)
+}
+
+// --- Strict indexing ---
+
+// Returns true if the list was created in strict mode
+func (list *ArrList[T]) Strict() bool {
+	return list.strict
+}
+
+// Same as [ArrList.Get], but the index must be in [-size, size), even if
+// the list is not in strict mode
+// Returns an [errs.IndexOutOfRangeErr] otherwise
+func (list *ArrList[T]) StrictGet(index int) (*T, error) {
+	return list.get("StrictGet", index, true)
+}
+
+// Same as [ArrList.Set], but the index must be in [-size, size), even if
+// the list is not in strict mode
+// Returns an [errs.IndexOutOfRangeErr] otherwise
+func (list *ArrList[T]) StrictSet(index int, data T) error {
+	return list.set("StrictSet", index, data, true)
+}
+
+// Same as [ArrList.Insert], but the index must be in [-(size+1), size],
+// even if the list is not in strict mode
+// Returns an [errs.IndexOutOfRangeErr] otherwise
+func (list *ArrList[T]) StrictInsert(index int, data T) error {
+	return list.insert("StrictInsert", index, data, true)
 }
 
 // --- Private methods ---
-func (list *ArrList[T]) sanify(index int) int {
-	if index < 0 {
-		index += len(list.data)
+func (list *ArrList[T]) get(op string, index int, strict bool) (*T, error) {
+	index, err := sanify(op, list, index, len(list.data), strict)
+	if err != nil {
+		return nil, err
+	}
+	return &list.data[index], nil
+}
+
+func (list *ArrList[T]) set(op string, index int, data T, strict bool) error {
+	index, err := sanify(op, list, index, len(list.data), strict)
+	if err != nil {
+		return err
+	}
+	list.data[index] = data
+	return nil
+}
+
+func (list *ArrList[T]) insert(op string, index int, data T, strict bool) error {
+	index, err := sanify(op, list, index, len(list.data)+1, strict)
+	if err != nil {
+		return err
 	}
-	return index % len(list.data)
+	list.data = append(list.data[:index], append([]T{data}, list.data[index:]...)...)
+	return nil
+}
+
+// new list with a copy of the given data, in the same mode
+func (list *ArrList[T]) derive(data []T) *ArrList[T] {
+	derived := Arr(data...)
+	derived.strict = list.strict
+	return derived
 }
 
 // --- Iterator struct and constructor ---
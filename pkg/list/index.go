@@ -0,0 +1,57 @@
+package list
+
+import "github.com/luverolla/lexgo/pkg/errs"
+
+// --- Index sanification ---
+
+// Maps the given index into [0, size), counting from the end if negative,
+// as described in [tau.IdxedColl]
+// In strict mode, the index must be in [-size, size), otherwise an
+// [errs.IndexOutOfRangeErr] is returned. Else, it wraps around
+// An empty range is an error in both modes: an [errs.EmptyErr], or an
+// [errs.IndexOutOfRangeErr] in strict mode, like any other index
+func sanify(op string, coll any, index, size int, strict bool) (int, error) {
+	if size == 0 {
+		if strict {
+			return 0, errs.IndexOutOfRange(op, coll, index, size)
+		}
+		return 0, errs.Empty()
+	}
+	if strict {
+		if index < -size || index >= size {
+			return 0, errs.IndexOutOfRange(op, coll, index, size)
+		}
+		if index < 0 {
+			index += size
+		}
+		return index, nil
+	}
+	return wrap(index, size), nil
+}
+
+// circular index sanification, with a positive size
+func wrap(index, size int) int {
+	index %= size
+	if index < 0 {
+		index += size
+	}
+	return index
+}
+
+// Maps the bounds of a slice into [0, size], counting from the end if
+// negative, and swaps them if start is after end
+// It's for strict mode, so the bounds must be in [-size, size]
+func strictBounds(op string, coll any, start, end, size int) (int, int, error) {
+	for _, bound := range []*int{&start, &end} {
+		if *bound < -size || *bound > size {
+			return 0, 0, errs.IndexOutOfRange(op, coll, *bound, size+1)
+		}
+		if *bound < 0 {
+			*bound += size
+		}
+	}
+	if start > end {
+		start, end = end, start
+	}
+	return start, end, nil
+}
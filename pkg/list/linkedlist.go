@@ -2,18 +2,18 @@ package list
 
 import (
 	"fmt"
-	"log"
-	"sort"
 
+	"github.com/luverolla/lexgo/pkg/algo"
 	"github.com/luverolla/lexgo/pkg/errs"
 	"github.com/luverolla/lexgo/pkg/tau"
 )
 
 // List implemented with a doubly linked list
 type LkdList[T any] struct {
-	head *node[T]
-	tail *node[T]
-	size int
+	head   *node[T]
+	tail   *node[T]
+	size   int
+	strict bool
 }
 
 // Creates a new list implemented with a doubly linked list
@@ -23,6 +23,15 @@ func Lkd[T any](data ...T) *LkdList[T] {
 	return list
 }
 
+// Creates a new list implemented with a doubly linked list, in strict mode
+// Indexes out of [-size, size) are errors instead of wrapping around,
+// see [LkdList.StrictGet]
+func StrictLkd[T any](data ...T) *LkdList[T] {
+	list := Lkd(data...)
+	list.strict = true
+	return list
+}
+
 // --- Methods from Collection[T] ---
 func (list *LkdList[T]) String() string {
 	s := "LkdList["
@@ -40,26 +49,7 @@ func (list *LkdList[T]) String() string {
 }
 
 func (list *LkdList[T]) Cmp(other any) int {
-	otherList, ok := other.(*LkdList[T])
-	if !ok {
-		return -1
-	}
-	if list.size != otherList.size {
-		return list.size - otherList.size
-	}
-	iter := list.Iter()
-	otherIter := otherList.Iter()
-	next, hasNext := iter.Next()
-	otherNext, hasOtherNext := otherIter.Next()
-	for hasNext && hasOtherNext {
-		cmp := tau.Cmp(*next, *otherNext)
-		if cmp != 0 {
-			return cmp
-		}
-		next, hasNext = iter.Next()
-		otherNext, hasOtherNext = otherIter.Next()
-	}
-	return 0
+	return tau.CollCmp[T](list, other)
 }
 
 func (list *LkdList[T]) Iter() tau.Iterator[T] {
@@ -109,43 +99,37 @@ func (list *LkdList[T]) Clone() tau.Collection[T] {
 }
 
 // --- Methods from IdxedColl[T] ---
+// Returns an error if the list is empty or, in strict mode, if the index
+// is out of range. In strict mode, both are an [errs.IndexOutOfRangeErr]
 func (list *LkdList[T]) Get(index int) (*T, error) {
-	if list.Empty() {
-		return nil, errs.Empty()
-	}
-
-	index = list.sanify(index)
-	return &list.getNode(index).data, nil
+	return list.get("Get", index, list.strict)
 }
 
+// Panics if the list is empty or, in strict mode, if the index is out of range
+// See [LkdList.StrictSet] for a version returning the error
 func (list *LkdList[T]) Set(index int, data T) {
-	index = list.sanify(index)
-	list.getNode(index).data = data
+	if err := list.set("Set", index, data, list.strict); err != nil {
+		panic(err)
+	}
 }
 
+// The index is sanified with size+1, as the end of the list is a valid
+// position too, so that Get(index) returns the new element afterwards
+// Panics in strict mode if the index is out of range
+// See [LkdList.StrictInsert] for a version returning the error
 func (list *LkdList[T]) Insert(index int, data T) {
-	index = index % list.size
-	if index < 0 {
-		index += list.size
-	}
-	if index == 0 {
-		list.Prepend(data)
-	} else if index == list.size {
-		list.Append(data)
-	} else {
-		tgt := list.getNode(index)
-		newNode := &node[T]{data: data}
-		tgt.prev.append(newNode)
-		newNode.append(tgt)
+	if err := list.insert("Insert", index, data, list.strict); err != nil {
+		panic(err)
 	}
-	list.size++
 }
 
+// Returns an error if the list is empty or, in strict mode, if the index
+// is out of range. In strict mode, both are an [errs.IndexOutOfRangeErr]
 func (list *LkdList[T]) RemoveAt(index int) (*T, error) {
-	if list.Empty() {
-		return nil, errs.Empty()
+	index, err := sanify("RemoveAt", list, index, list.size, list.strict)
+	if err != nil {
+		return nil, err
 	}
-	index = list.sanify(index)
 	target := list.getNode(index)
 	list.remove(target)
 	return &target.data, nil
@@ -173,9 +157,16 @@ func (list *LkdList[T]) LastIndexOf(data T) int {
 	return -1
 }
 
+// Panics if the list is empty or, in strict mode, if an index is out of range
 func (list *LkdList[T]) Swap(i, j int) {
-	i = list.sanify(i)
-	j = list.sanify(j)
+	i, err := sanify("Swap", list, i, list.size, list.strict)
+	if err != nil {
+		panic(err)
+	}
+	j, err = sanify("Swap", list, j, list.size, list.strict)
+	if err != nil {
+		panic(err)
+	}
 	if i == j {
 		return
 	}
@@ -184,18 +175,28 @@ func (list *LkdList[T]) Swap(i, j int) {
 	nodeI.data, nodeJ.data = nodeJ.data, nodeI.data
 }
 
+// In strict mode, the bounds must be in [-size, size], and they count
+// from the end if negative. It panics with an [errs.IndexOutOfRangeErr] otherwise
 func (list *LkdList[T]) Slice(start, end int) tau.IdxedColl[T] {
-	if list.Empty() || start == end {
-		return Lkd[T]()
-	}
-	var actStart = list.sanify(start)
-	var actEnd = list.sanify(end-1) + 1
-
-	if actStart > actEnd {
-		actStart, actEnd = actEnd, actStart
+	var actStart, actEnd int
+	if list.strict {
+		var err error
+		actStart, actEnd, err = strictBounds("Slice", list, start, end, list.size)
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		if list.Empty() || start == end {
+			return list.derive()
+		}
+		actStart = wrap(start, list.size)
+		actEnd = wrap(end-1, list.size) + 1
+		if actStart > actEnd {
+			actStart, actEnd = actEnd, actStart
+		}
 	}
 
-	sub := Lkd[T]()
+	sub := list.derive()
 	for i := actStart; i < actEnd; i++ {
 		sub.Append(list.getNode(i).data)
 	}
@@ -253,7 +254,7 @@ func (list *LkdList[T]) RemoveAll(data T) error {
 }
 
 func (list *LkdList[T]) Sublist(filter tau.Filter[T]) tau.List[T] {
-	sub := Lkd[T]()
+	sub := list.derive()
 	for node := list.head; node != nil; node = node.next {
 		if filter(node.data) {
 			sub.Append(node.data)
@@ -267,18 +268,80 @@ func (list *LkdList[T]) Sort(comparator tau.Comparator[T]) tau.List[T] {
 	for node, i := list.head, 0; node != nil; node, i = node.next, i+1 {
 		data[i] = node.data
 	}
-	sort.Slice(data, func(i, j int) bool {
-		return comparator(data[i], data[j]) < 0
-	})
-	return Lkd(data...)
+	algo.TimSortSlice(data, comparator)
+	sorted := list.derive()
+	sorted.Append(data...)
+	return sorted
+}
+
+// --- Strict indexing ---
+
+// Returns true if the list was created in strict mode
+func (list *LkdList[T]) Strict() bool {
+	return list.strict
+}
+
+// Same as [LkdList.Get], but the index must be in [-size, size), even if
+// the list is not in strict mode
+// Returns an [errs.IndexOutOfRangeErr] otherwise
+func (list *LkdList[T]) StrictGet(index int) (*T, error) {
+	return list.get("StrictGet", index, true)
+}
+
+// Same as [LkdList.Set], but the index must be in [-size, size), even if
+// the list is not in strict mode
+// Returns an [errs.IndexOutOfRangeErr] otherwise
+func (list *LkdList[T]) StrictSet(index int, data T) error {
+	return list.set("StrictSet", index, data, true)
+}
+
+// Same as [LkdList.Insert], but the index must be in [-(size+1), size],
+// even if the list is not in strict mode
+// Returns an [errs.IndexOutOfRangeErr] otherwise
+func (list *LkdList[T]) StrictInsert(index int, data T) error {
+	return list.insert("StrictInsert", index, data, true)
 }
 
 // --- Private Methods ---
-func (list *LkdList[T]) sanify(index int) int {
-	if index < 0 {
-		index += list.size
+func (list *LkdList[T]) get(op string, index int, strict bool) (*T, error) {
+	index, err := sanify(op, list, index, list.size, strict)
+	if err != nil {
+		return nil, err
+	}
+	return &list.getNode(index).data, nil
+}
+
+func (list *LkdList[T]) set(op string, index int, data T, strict bool) error {
+	index, err := sanify(op, list, index, list.size, strict)
+	if err != nil {
+		return err
+	}
+	list.getNode(index).data = data
+	return nil
+}
+
+func (list *LkdList[T]) insert(op string, index int, data T, strict bool) error {
+	index, err := sanify(op, list, index, list.size+1, strict)
+	if err != nil {
+		return err
 	}
-	return index % list.size
+	if index == 0 {
+		list.Prepend(data)
+	} else if index == list.size {
+		list.Append(data)
+	} else {
+		tgt := list.getNode(index)
+		newNode := &node[T]{data: data}
+		tgt.prev.append(newNode)
+		newNode.append(tgt)
+		list.size++
+	}
+	return nil
+}
+
+// new empty list, in the same mode
+func (list *LkdList[T]) derive() *LkdList[T] {
+	return &LkdList[T]{strict: list.strict}
 }
 
 func (list *LkdList[T]) getNode(index int) *node[T] {
@@ -299,27 +362,15 @@ func (list *LkdList[T]) getNode(index int) *node[T] {
 }
 
 func (list *LkdList[T]) remove(n *node[T]) {
-	if tau.Nil(n) {
-		log.Fatal("[Linked] attempted to remove a nil node")
-		return
-	}
 	if tau.Nil(n.prev) {
 		list.head = n.next
-		list.head.prev = nil
 	} else {
 		n.prev.next = n.next
-		if n.next != nil {
-			n.next.prev = n.prev
-		}
 	}
 	if tau.Nil(n.next) {
 		list.tail = n.prev
-		list.tail.next = nil
 	} else {
 		n.next.prev = n.prev
-		if n.prev != nil {
-			n.prev.next = n.next
-		}
 	}
 	list.size--
 }
@@ -2,6 +2,7 @@ package set
 
 import (
 	"fmt"
+	"math/rand/v2"
 
 	"github.com/luverolla/lexgo/pkg/table"
 	"github.com/luverolla/lexgo/pkg/tau"
@@ -35,22 +36,7 @@ func (set *AVLSet[T]) String() string {
 }
 
 func (set *AVLSet[T]) Cmp(other any) int {
-	otherSet, ok := other.(*AVLSet[T])
-	if !ok {
-		panic(fmt.Sprintf("ERROR: [AVLSet.Cmp] %v is not a *AVLSet", other))
-	}
-	if set.Size() != otherSet.Size() {
-		return set.Size() - otherSet.Size()
-	}
-	iter, otherIter := set.Iter(), otherSet.Iter()
-	for next, hasNext := iter.Next(); hasNext; next, hasNext = iter.Next() {
-		otherNext, _ := otherIter.Next()
-		cmp := tau.Cmp(*next, *otherNext)
-		if cmp != 0 {
-			return cmp
-		}
-	}
-	return 0
+	return tau.CollCmp[T](set, other)
 }
 
 func (set *AVLSet[T]) Size() int {
@@ -110,3 +96,10 @@ func (set *AVLSet[T]) Remove(value T) error {
 	_, err := set.table.Remove(value)
 	return err
 }
+
+// Returns an element chosen at random, every one with the same probability
+// It takes O(log n) time, see [table.AVLMap.RandomElement]
+// Returns an error if the set is empty
+func (set *AVLSet[T]) RandomElement(r *rand.Rand) (*T, error) {
+	return set.table.RandomElement(r)
+}
@@ -0,0 +1,301 @@
+package set
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Set of non-negative integers, stored as a growable array of bits
+//
+// It takes one bit for every integer up to the greatest element, so it
+// suits dense sets of small integers, like IDs. For sparse sets, see
+// [RoaringBitmap]. Elements are iterated in ascending order.
+type BitSet struct {
+	words []uint64
+	count int
+}
+
+// Creates a new bit set holding the given values
+// Panics if a value is negative
+func Bits(values ...int) *BitSet {
+	set := &BitSet{make([]uint64, 0), 0}
+	set.Add(values...)
+	return set
+}
+
+// --- Methods from Collection[int] ---
+func (set *BitSet) String() string {
+	s := "BitSet{"
+	first := true
+	set.Iter().Each(func(value int) {
+		if !first {
+			s += ", "
+		}
+		first = false
+		s += fmt.Sprintf("%d", value)
+	})
+	s += "}"
+	return s
+}
+
+func (set *BitSet) Cmp(other any) int {
+	return tau.CollCmp[int](set, other)
+}
+
+// Iterates over the elements in ascending order
+func (set *BitSet) Iter() tau.Iterator[int] {
+	return &bitSetIter{set, 0}
+}
+
+func (set *BitSet) Size() int {
+	return set.count
+}
+
+func (set *BitSet) Empty() bool {
+	return set.count == 0
+}
+
+func (set *BitSet) Clear() {
+	set.words = set.words[:0]
+	set.count = 0
+}
+
+func (set *BitSet) Contains(value int) bool {
+	word := value / 64
+	return value >= 0 && word < len(set.words) && set.words[word]&(1<<(value%64)) != 0
+}
+
+func (set *BitSet) ContainsAll(coll tau.Collection[int]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !set.Contains(*next) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *BitSet) ContainsAny(coll tau.Collection[int]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if set.Contains(*next) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *BitSet) Clone() tau.Collection[int] {
+	clone := &BitSet{make([]uint64, len(set.words)), set.count}
+	copy(clone.words, set.words)
+	return clone
+}
+
+// --- Methods from Set[int] ---
+
+// Adds the given values, growing the set if needed
+// Panics if a value is negative
+func (set *BitSet) Add(values ...int) {
+	for _, value := range values {
+		if value < 0 {
+			panic(fmt.Sprintf("ERROR: [BitSet.Add] values must not be negative, got %d", value))
+		}
+		word := value / 64
+		for len(set.words) <= word {
+			set.words = append(set.words, 0)
+		}
+		if set.words[word]&(1<<(value%64)) == 0 {
+			set.words[word] |= 1 << (value % 64)
+			set.count++
+		}
+	}
+}
+
+func (set *BitSet) Remove(value int) error {
+	if !set.Contains(value) {
+		return errs.NotFound(value)
+	}
+	set.words[value/64] &^= 1 << (value % 64)
+	set.count--
+	set.trim()
+	return nil
+}
+
+func (set *BitSet) Subset(filter tau.Filter[int]) tau.Set[int] {
+	subset := Bits()
+	set.Iter().Each(func(value int) {
+		if filter(value) {
+			subset.Add(value)
+		}
+	})
+	return subset
+}
+
+// --- Set algebra ---
+
+// Returns a new set with the elements in both this set and the other one
+func (set *BitSet) And(other *BitSet) *BitSet {
+	return bitSetOp(set, other, func(a, b uint64) uint64 { return a & b })
+}
+
+// Returns a new set with the elements in either this set or the other one
+func (set *BitSet) Or(other *BitSet) *BitSet {
+	return bitSetOp(set, other, func(a, b uint64) uint64 { return a | b })
+}
+
+// Returns a new set with the elements in exactly one of this set and the other one
+func (set *BitSet) Xor(other *BitSet) *BitSet {
+	return bitSetOp(set, other, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// Returns a new set with the elements in this set but not in the other one
+func (set *BitSet) AndNot(other *BitSet) *BitSet {
+	return bitSetOp(set, other, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// --- Order statistics ---
+
+// Returns the number of elements less than the given value
+func (set *BitSet) Rank(value int) int {
+	if value <= 0 {
+		return 0
+	}
+	word := value / 64
+	rank := 0
+	for i := 0; i < word && i < len(set.words); i++ {
+		rank += bits.OnesCount64(set.words[i])
+	}
+	if word < len(set.words) {
+		rank += bits.OnesCount64(set.words[word] & (1<<(value%64) - 1))
+	}
+	return rank
+}
+
+// Returns the element with exactly k smaller elements, so that
+// Select(Rank(v)) is v for all the elements v
+// Returns an error if k is not in [0, size)
+func (set *BitSet) Select(k int) (int, error) {
+	if k < 0 || k >= set.count {
+		return 0, errs.IndexOutOfRange("Select", set, k, set.count)
+	}
+	for i, word := range set.words {
+		ones := bits.OnesCount64(word)
+		if k < ones {
+			return 64*i + selectInWord(word, k), nil
+		}
+		k -= ones
+	}
+	// unreachable, as count is the number of set bits
+	return 0, errs.IndexOutOfRange("Select", set, k, set.count)
+}
+
+// Returns an element chosen at random, every one with the same probability
+// The global random source is used if r is nil. It takes the time of [BitSet.Select]
+// Returns an error if the set is empty
+func (set *BitSet) RandomElement(r *rand.Rand) (*int, error) {
+	if set.count == 0 {
+		return nil, errs.Empty()
+	}
+	value, err := set.Select(algo.RandIntN(r, set.count))
+	return &value, err
+}
+
+// --- Serialization ---
+
+func (set *BitSet) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 8*len(set.words))
+	for _, word := range set.words {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// Replaces the content of the set with the serialized one
+// Returns an error if the data length is not a multiple of 8
+func (set *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return errs.Mismatch("multiple of 8 bytes", len(data))
+	}
+	words := make([]uint64, len(data)/8)
+	count := 0
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+		count += bits.OnesCount64(words[i])
+	}
+	set.words, set.count = words, count
+	set.trim()
+	return nil
+}
+
+// --- Private ---
+
+// removes the trailing zero words
+func (set *BitSet) trim() {
+	n := len(set.words)
+	for n > 0 && set.words[n-1] == 0 {
+		n--
+	}
+	set.words = set.words[:n]
+}
+
+func bitSetOp(a, b *BitSet, op func(uint64, uint64) uint64) *BitSet {
+	n := max(len(a.words), len(b.words))
+	result := &BitSet{make([]uint64, n), 0}
+	for i := range result.words {
+		var wa, wb uint64
+		if i < len(a.words) {
+			wa = a.words[i]
+		}
+		if i < len(b.words) {
+			wb = b.words[i]
+		}
+		result.words[i] = op(wa, wb)
+		result.count += bits.OnesCount64(result.words[i])
+	}
+	result.trim()
+	return result
+}
+
+// position of the k-th set bit of the word, starting from 0
+func selectInWord(word uint64, k int) int {
+	for ; k > 0; k-- {
+		// clears the lowest set bit
+		word &= word - 1
+	}
+	return bits.TrailingZeros64(word)
+}
+
+type bitSetIter struct {
+	set *BitSet
+	// next value to look at
+	next int
+}
+
+func (iter *bitSetIter) Next() (*int, bool) {
+	words := iter.set.words
+	for word := iter.next / 64; word < len(words); word++ {
+		rest := words[word]
+		if word == iter.next/64 {
+			rest &^= 1<<(iter.next%64) - 1
+		}
+		if rest != 0 {
+			value := 64*word + bits.TrailingZeros64(rest)
+			iter.next = value + 1
+			return &value, true
+		}
+	}
+	iter.next = 64 * len(words)
+	return nil, false
+}
+
+func (iter *bitSetIter) Each(f func(int)) {
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		f(*next)
+	}
+}
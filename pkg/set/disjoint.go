@@ -0,0 +1,461 @@
+package set
+
+import (
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Partition of elements in disjoint sets (union-find)
+//
+// Elements are kept in a hash table, so any type accepted by [tau.Hash]
+// and [tau.Eq] can be used. With union by rank and path compression,
+// Union and Find take nearly constant amortized time.
+//
+// As a collection, it contains all the elements of all the sets.
+type DisjointSet[T any] struct {
+	dsBase[T]
+}
+
+// Creates a new disjoint set, with a singleton for each of the given elements
+func Disjoint[T any](elements ...T) *DisjointSet[T] {
+	set := &DisjointSet[T]{newDsBase[T]()}
+	set.Add(elements...)
+	return set
+}
+
+// Disjoint set whose unions can be undone
+//
+// Paths are not compressed, so that every union changes a single link and
+// can be reverted in constant time; Find takes logarithmic time thanks to
+// the union by rank. It's meant for offline algorithms that explore a
+// choice and then go back, like dynamic connectivity over time ranges.
+type UndoDisjointSet[T any] struct {
+	dsBase[T]
+	history []dsStep
+}
+
+// Creates a new disjoint set with undo, with a singleton for each of the given elements
+func UndoDisjoint[T any](elements ...T) *UndoDisjointSet[T] {
+	set := &UndoDisjointSet[T]{newDsBase[T](), make([]dsStep, 0)}
+	set.Add(elements...)
+	return set
+}
+
+// --- Methods from Collection[T] ---
+func (set *DisjointSet[T]) String() string {
+	return set.string("DisjointSet", set.root)
+}
+
+// Compares the elements in insertion order, regardless of how they are
+// partitioned
+// See [DisjointSet.SamePartition] to compare the sets too
+func (set *DisjointSet[T]) Cmp(other any) int {
+	return tau.CollCmp[T](set, other)
+}
+
+func (set *DisjointSet[T]) Clear() {
+	set.dsBase = newDsBase[T]()
+}
+
+func (set *DisjointSet[T]) Clone() tau.Collection[T] {
+	return &DisjointSet[T]{set.clone()}
+}
+
+func (set *UndoDisjointSet[T]) String() string {
+	return set.string("UndoDisjointSet", set.root)
+}
+
+// Compares the elements in insertion order, regardless of how they are
+// partitioned
+// See [UndoDisjointSet.SamePartition] to compare the sets too
+func (set *UndoDisjointSet[T]) Cmp(other any) int {
+	return tau.CollCmp[T](set, other)
+}
+
+// Removes all the elements, along with the history
+func (set *UndoDisjointSet[T]) Clear() {
+	set.dsBase = newDsBase[T]()
+	set.history = set.history[:0]
+}
+
+func (set *UndoDisjointSet[T]) Clone() tau.Collection[T] {
+	history := make([]dsStep, len(set.history))
+	copy(history, set.history)
+	return &UndoDisjointSet[T]{set.clone(), history}
+}
+
+// --- Methods from DisjointSet[T] ---
+
+// Adds the given elements, each one in its own set
+// Elements already present are skipped
+func (set *DisjointSet[T]) Add(elements ...T) {
+	for _, element := range elements {
+		set.add(element)
+	}
+}
+
+// Merges the sets containing the two given elements
+// Missing elements are added first
+// Returns false if the elements were already in the same set
+func (set *DisjointSet[T]) Union(a, b T) bool {
+	x, _ := set.add(a)
+	y, _ := set.add(b)
+	_, _, _, merged := set.link(set.root(x), set.root(y))
+	return merged
+}
+
+// Returns the representative of the set containing the given element
+// Two elements are in the same set if and only if they have the same representative
+// Returns an error if the element is not present
+func (set *DisjointSet[T]) Find(element T) (*T, error) {
+	return set.find(element, set.root)
+}
+
+// Returns true if the two given elements are in the same set
+// Returns false if any of them is not present
+func (set *DisjointSet[T]) Connected(a, b T) bool {
+	return set.connected(a, b, set.root)
+}
+
+// Returns the size of the set containing the given element
+// Returns 0 if the element is not present
+func (set *DisjointSet[T]) SetSize(element T) int {
+	return set.setSize(element, set.root)
+}
+
+// Returns an iterator over the sets, each one as a new [tau.Set]
+func (set *DisjointSet[T]) Sets() tau.Iterator[tau.Set[T]] {
+	return set.sets(set.root)
+}
+
+// Returns true if the other disjoint set has the same elements, split
+// in the same sets
+func (set *DisjointSet[T]) SamePartition(other *DisjointSet[T]) bool {
+	return set.samePartition(&other.dsBase, set.root, other.root)
+}
+
+// --- Methods from UndoDisjointSet[T] ---
+
+// Adds the given elements, each one in its own set
+// Elements already present are skipped
+func (set *UndoDisjointSet[T]) Add(elements ...T) {
+	for _, element := range elements {
+		if id, added := set.add(element); added {
+			set.history = append(set.history, dsStep{id, -1, false})
+		}
+	}
+}
+
+// Merges the sets containing the two given elements
+// Missing elements are added first
+// Returns false if the elements were already in the same set
+func (set *UndoDisjointSet[T]) Union(a, b T) bool {
+	set.Add(a, b)
+	x, _ := set.ids.Get(a)
+	y, _ := set.ids.Get(b)
+	child, parent, rankUp, merged := set.link(set.root(*x), set.root(*y))
+	if merged {
+		set.history = append(set.history, dsStep{child, parent, rankUp})
+	}
+	return merged
+}
+
+// Returns the representative of the set containing the given element
+// Two elements are in the same set if and only if they have the same representative
+// Returns an error if the element is not present
+func (set *UndoDisjointSet[T]) Find(element T) (*T, error) {
+	return set.find(element, set.root)
+}
+
+// Returns true if the two given elements are in the same set
+// Returns false if any of them is not present
+func (set *UndoDisjointSet[T]) Connected(a, b T) bool {
+	return set.connected(a, b, set.root)
+}
+
+// Returns the size of the set containing the given element
+// Returns 0 if the element is not present
+func (set *UndoDisjointSet[T]) SetSize(element T) int {
+	return set.setSize(element, set.root)
+}
+
+// Returns an iterator over the sets, each one as a new [tau.Set]
+func (set *UndoDisjointSet[T]) Sets() tau.Iterator[tau.Set[T]] {
+	return set.sets(set.root)
+}
+
+// Returns true if the other disjoint set has the same elements, split
+// in the same sets
+func (set *UndoDisjointSet[T]) SamePartition(other *UndoDisjointSet[T]) bool {
+	return set.samePartition(&other.dsBase, set.root, other.root)
+}
+
+// Returns a checkpoint of the current state, to be given to [UndoDisjointSet.Rollback]
+func (set *UndoDisjointSet[T]) Checkpoint() int {
+	return len(set.history)
+}
+
+// Undoes the last change, either an addition or a union that merged two sets
+// Returns false if there's nothing to undo
+func (set *UndoDisjointSet[T]) Undo() bool {
+	if len(set.history) == 0 {
+		return false
+	}
+	last := len(set.history) - 1
+	step := set.history[last]
+	set.history = set.history[:last]
+	if step.parent < 0 {
+		// the added element is always the last one
+		set.ids.Remove(set.elements[step.child])
+		set.elements = set.elements[:step.child]
+		set.parent = set.parent[:step.child]
+		set.rank = set.rank[:step.child]
+		set.size = set.size[:step.child]
+		set.count--
+		return true
+	}
+	set.parent[step.child] = step.child
+	set.size[step.parent] -= set.size[step.child]
+	if step.rankUp {
+		set.rank[step.parent]--
+	}
+	set.count++
+	return true
+}
+
+// Undoes all the changes made after the given checkpoint
+// Returns an error if the checkpoint is not valid, for example because
+// it comes after a previous rollback
+func (set *UndoDisjointSet[T]) Rollback(checkpoint int) error {
+	if checkpoint < 0 || checkpoint > len(set.history) {
+		return errs.IndexOutOfRange("Rollback", set, checkpoint, len(set.history)+1)
+	}
+	for len(set.history) > checkpoint {
+		set.Undo()
+	}
+	return nil
+}
+
+// --- Base ---
+
+// state shared by the disjoint sets, elements are identified by their position
+type dsBase[T any] struct {
+	ids      *table.HshMap[T, int]
+	elements []T
+	parent   []int
+	rank     []int
+	// size of the set, only meaningful for the roots
+	size []int
+	// number of sets
+	count int
+}
+
+func newDsBase[T any]() dsBase[T] {
+	return dsBase[T]{ids: table.Hsh[T, int]()}
+}
+
+// undoable change: if parent is -1, child was added, otherwise child was
+// linked to parent, whose rank was increased if rankUp is true
+type dsStep struct {
+	child  int
+	parent int
+	rankUp bool
+}
+
+func (set *dsBase[T]) Iter() tau.Iterator[T] {
+	return list.Arr(set.elements...).Iter()
+}
+
+func (set *dsBase[T]) Size() int {
+	return len(set.elements)
+}
+
+func (set *dsBase[T]) Empty() bool {
+	return len(set.elements) == 0
+}
+
+func (set *dsBase[T]) Contains(element T) bool {
+	return set.ids.HasKey(element)
+}
+
+func (set *dsBase[T]) ContainsAll(other tau.Collection[T]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !set.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *dsBase[T]) ContainsAny(other tau.Collection[T]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if set.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the number of disjoint sets
+func (set *dsBase[T]) Count() int {
+	return set.count
+}
+
+// returns the position of the element and true if it was just added
+func (set *dsBase[T]) add(element T) (int, bool) {
+	if id, err := set.ids.Get(element); err == nil {
+		return *id, false
+	}
+	id := len(set.elements)
+	set.ids.Put(element, id)
+	set.elements = append(set.elements, element)
+	set.parent = append(set.parent, id)
+	set.rank = append(set.rank, 0)
+	set.size = append(set.size, 1)
+	set.count++
+	return id, true
+}
+
+// root without path compression
+func (set *dsBase[T]) walk(id int) int {
+	for set.parent[id] != id {
+		id = set.parent[id]
+	}
+	return id
+}
+
+// links the two roots by rank, returns the one linked, the one it was
+// linked to, and whether the rank of the latter was increased
+func (set *dsBase[T]) link(x, y int) (int, int, bool, bool) {
+	if x == y {
+		return x, y, false, false
+	}
+	if set.rank[x] > set.rank[y] {
+		x, y = y, x
+	}
+	set.parent[x] = y
+	set.size[y] += set.size[x]
+	rankUp := set.rank[x] == set.rank[y]
+	if rankUp {
+		set.rank[y]++
+	}
+	set.count--
+	return x, y, rankUp, true
+}
+
+func (set *dsBase[T]) find(element T, root func(int) int) (*T, error) {
+	id, err := set.ids.Get(element)
+	if err != nil {
+		return nil, err
+	}
+	representative := set.elements[root(*id)]
+	return &representative, nil
+}
+
+func (set *dsBase[T]) connected(a, b T, root func(int) int) bool {
+	x, err := set.ids.Get(a)
+	if err != nil {
+		return false
+	}
+	y, err := set.ids.Get(b)
+	if err != nil {
+		return false
+	}
+	return root(*x) == root(*y)
+}
+
+func (set *dsBase[T]) setSize(element T, root func(int) int) int {
+	id, err := set.ids.Get(element)
+	if err != nil {
+		return 0
+	}
+	return set.size[root(*id)]
+}
+
+func (set *dsBase[T]) sets(root func(int) int) tau.Iterator[tau.Set[T]] {
+	groups := make(map[int]*HshSet[T])
+	res := list.Arr[tau.Set[T]]()
+	for id, element := range set.elements {
+		r := root(id)
+		group, ok := groups[r]
+		if !ok {
+			group = Hsh[T]()
+			groups[r] = group
+			res.Append(group)
+		}
+		group.Add(element)
+	}
+	return res.Iter()
+}
+
+func (set *dsBase[T]) string(name string, root func(int) int) string {
+	s := name + "{"
+	first := true
+	set.sets(root).Each(func(group tau.Set[T]) {
+		if !first {
+			s += ", "
+		}
+		first = false
+		s += group.String()
+	})
+	s += "}"
+	return s
+}
+
+// true if the other base has the same elements and every set maps to
+// exactly one set of the other: as the number of sets is the same, the
+// mapping is then one-to-one
+func (set *dsBase[T]) samePartition(other *dsBase[T], root, otherRoot func(int) int) bool {
+	if set.Size() != other.Size() || set.count != other.count {
+		return false
+	}
+	mapping := make(map[int]int)
+	for id, element := range set.elements {
+		otherId, err := other.ids.Get(element)
+		if err != nil {
+			return false
+		}
+		mapped, ok := mapping[root(id)]
+		if !ok {
+			mapping[root(id)] = otherRoot(*otherId)
+		} else if mapped != otherRoot(*otherId) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *dsBase[T]) clone() dsBase[T] {
+	clone := dsBase[T]{
+		set.ids.Clone().(*table.HshMap[T, int]),
+		make([]T, len(set.elements)),
+		make([]int, len(set.parent)),
+		make([]int, len(set.rank)),
+		make([]int, len(set.size)),
+		set.count,
+	}
+	copy(clone.elements, set.elements)
+	copy(clone.parent, set.parent)
+	copy(clone.rank, set.rank)
+	copy(clone.size, set.size)
+	return clone
+}
+
+// --- Roots ---
+
+// root with path compression
+func (set *DisjointSet[T]) root(id int) int {
+	root := set.walk(id)
+	for id != root {
+		id, set.parent[id] = set.parent[id], root
+	}
+	return root
+}
+
+func (set *UndoDisjointSet[T]) root(id int) int {
+	return set.walk(id)
+}
@@ -3,6 +3,7 @@ package set
 
 import (
 	"fmt"
+	"math/rand/v2"
 
 	"github.com/luverolla/lexgo/pkg/table"
 	"github.com/luverolla/lexgo/pkg/tau"
@@ -36,22 +37,7 @@ func (set *HshSet[T]) String() string {
 }
 
 func (set *HshSet[T]) Cmp(other any) int {
-	otherSet, ok := other.(*HshSet[T])
-	if !ok {
-		panic(fmt.Sprintf("ERROR: [HshSet.Cmp] %v is not a *HshSet", other))
-	}
-	if set.Size() != otherSet.Size() {
-		return set.Size() - otherSet.Size()
-	}
-	iter, otherIter := set.Iter(), otherSet.Iter()
-	for next, hasNext := iter.Next(); hasNext; next, hasNext = iter.Next() {
-		otherNext, _ := otherIter.Next()
-		cmp := tau.Cmp(*next, *otherNext)
-		if cmp != 0 {
-			return cmp
-		}
-	}
-	return 0
+	return tau.CollCmp[T](set, other)
 }
 
 func (set *HshSet[T]) Size() int {
@@ -111,3 +97,20 @@ func (set *HshSet[T]) Remove(value T) error {
 	_, err := set.table.Remove(value)
 	return err
 }
+
+func (set *HshSet[T]) Subset(filter tau.Filter[T]) tau.Set[T] {
+	subset := Hsh[T]()
+	set.Iter().Each(func(value T) {
+		if filter(value) {
+			subset.Add(value)
+		}
+	})
+	return subset
+}
+
+// Returns an element chosen at random, every one with the same probability
+// It takes O(1) expected time, see [table.HshMap.RandomElement]
+// Returns an error if the set is empty
+func (set *HshSet[T]) RandomElement(r *rand.Rand) (*T, error) {
+	return set.table.RandomElement(r)
+}
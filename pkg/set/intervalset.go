@@ -0,0 +1,258 @@
+package set
+
+import (
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+// Set of points, stored as the sorted list of its maximal disjoint ranges
+//
+// Ranges are half-open intervals, see [tree.Interval]. Overlapping or
+// adjacent ranges are merged when added, so [1, 3) and [3, 5) become
+// [1, 5). As a collection, its elements are the maximal ranges, and an
+// interval is contained if all its points are in the set.
+type IntervalSet[E any] struct {
+	tree *tree.BTree[tree.Interval[E]]
+}
+
+// Creates a new interval set holding the given ranges
+func Itv[E any](ranges ...tree.Interval[E]) *IntervalSet[E] {
+	set := &IntervalSet[E]{tree.B[tree.Interval[E]](itvDegree)}
+	set.Add(ranges...)
+	return set
+}
+
+// --- Methods from Collection[Interval[E]] ---
+func (set *IntervalSet[E]) String() string {
+	s := "IntervalSet{"
+	first := true
+	set.Iter().Each(func(iv tree.Interval[E]) {
+		if !first {
+			s += ", "
+		}
+		first = false
+		s += iv.String()
+	})
+	s += "}"
+	return s
+}
+
+func (set *IntervalSet[E]) Cmp(other any) int {
+	return tau.CollCmp[tree.Interval[E]](set, other)
+}
+
+// Iterates over the maximal ranges in ascending order
+func (set *IntervalSet[E]) Iter() tau.Iterator[tree.Interval[E]] {
+	return set.tree.InOrder()
+}
+
+// Returns the number of maximal ranges
+func (set *IntervalSet[E]) Size() int {
+	return set.tree.Size()
+}
+
+func (set *IntervalSet[E]) Empty() bool {
+	return set.tree.Empty()
+}
+
+func (set *IntervalSet[E]) Clear() {
+	set.tree.Clear()
+}
+
+// Returns true if all the points of the given interval are in the set
+func (set *IntervalSet[E]) Contains(iv tree.Interval[E]) bool {
+	if iv.Empty() {
+		return true
+	}
+	covering, ok := set.covering(iv.Lo)
+	return ok && tau.Cmp(covering.Hi, iv.Hi) >= 0
+}
+
+func (set *IntervalSet[E]) ContainsAll(coll tau.Collection[tree.Interval[E]]) bool {
+	iter := coll.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !set.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *IntervalSet[E]) ContainsAny(coll tau.Collection[tree.Interval[E]]) bool {
+	iter := coll.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if set.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *IntervalSet[E]) Clone() tau.Collection[tree.Interval[E]] {
+	return &IntervalSet[E]{set.tree.Clone().(*tree.BTree[tree.Interval[E]])}
+}
+
+// --- Methods from Set[Interval[E]] ---
+
+// Adds the points of the given intervals, merging the ranges that overlap
+// or touch each other
+// Empty intervals are skipped
+func (set *IntervalSet[E]) Add(ranges ...tree.Interval[E]) {
+	for _, iv := range ranges {
+		if iv.Empty() {
+			continue
+		}
+		lo, hi := iv.Lo, iv.Hi
+		if prev, ok := set.pred(lo); ok && tau.Cmp(prev.Hi, lo) >= 0 {
+			lo, hi = prev.Lo, tau.Max(hi, prev.Hi).(E)
+			set.tree.Remove(prev)
+		}
+		merged := make([]tree.Interval[E], 0)
+		iter := set.tree.From(tree.Interval[E]{Lo: lo, Hi: lo})
+		for next, ok := iter.Next(); ok && tau.Cmp(next.Lo, hi) <= 0; next, ok = iter.Next() {
+			merged = append(merged, *next)
+			hi = tau.Max(hi, next.Hi).(E)
+		}
+		for _, other := range merged {
+			set.tree.Remove(other)
+		}
+		set.tree.Insert(tree.Interval[E]{Lo: lo, Hi: hi})
+	}
+}
+
+// Removes the points of the given interval, splitting the ranges if needed
+// Returns an error if none of the points was in the set
+func (set *IntervalSet[E]) Remove(iv tree.Interval[E]) error {
+	if iv.Empty() {
+		return errs.NotFound(iv)
+	}
+	found := false
+	kept := make([]tree.Interval[E], 0)
+	if prev, ok := set.pred(iv.Lo); ok && tau.Cmp(prev.Hi, iv.Lo) > 0 {
+		found = true
+		set.tree.Remove(prev)
+		kept = append(kept, tree.Interval[E]{Lo: prev.Lo, Hi: iv.Lo})
+		if tau.Cmp(prev.Hi, iv.Hi) > 0 {
+			kept = append(kept, tree.Interval[E]{Lo: iv.Hi, Hi: prev.Hi})
+		}
+	}
+	removed := make([]tree.Interval[E], 0)
+	iter := set.tree.From(tree.Interval[E]{Lo: iv.Lo, Hi: iv.Lo})
+	for next, ok := iter.Next(); ok && tau.Cmp(next.Lo, iv.Hi) < 0; next, ok = iter.Next() {
+		found = true
+		removed = append(removed, *next)
+		if tau.Cmp(next.Hi, iv.Hi) > 0 {
+			kept = append(kept, tree.Interval[E]{Lo: iv.Hi, Hi: next.Hi})
+		}
+	}
+	for _, other := range removed {
+		set.tree.Remove(other)
+	}
+	for _, other := range kept {
+		set.tree.Insert(other)
+	}
+	if !found {
+		return errs.NotFound(iv)
+	}
+	return nil
+}
+
+// Returns a new set with the maximal ranges satisfying the given filter
+func (set *IntervalSet[E]) Subset(filter tau.Filter[tree.Interval[E]]) tau.Set[tree.Interval[E]] {
+	subset := Itv[E]()
+	set.Iter().Each(func(iv tree.Interval[E]) {
+		if filter(iv) {
+			subset.tree.Insert(iv)
+		}
+	})
+	return subset
+}
+
+// --- Range operations ---
+
+// Returns true if the given point is in the set
+func (set *IntervalSet[E]) ContainsPoint(point E) bool {
+	_, ok := set.covering(point)
+	return ok
+}
+
+// Returns the maximal range containing the given point
+// Returns an error if the point is not in the set
+func (set *IntervalSet[E]) RangeOf(point E) (*tree.Interval[E], error) {
+	covering, ok := set.covering(point)
+	if !ok {
+		return nil, errs.NotFound(point)
+	}
+	return &covering, nil
+}
+
+// Returns a new set with the points in either this set or the other one
+func (set *IntervalSet[E]) Union(other *IntervalSet[E]) *IntervalSet[E] {
+	union := set.Clone().(*IntervalSet[E])
+	other.Iter().Each(func(iv tree.Interval[E]) {
+		union.Add(iv)
+	})
+	return union
+}
+
+// Returns a new set with the points in both this set and the other one
+func (set *IntervalSet[E]) Intersect(other *IntervalSet[E]) *IntervalSet[E] {
+	inter := Itv[E]()
+	iter, otherIter := set.Iter(), other.Iter()
+	a, hasA := iter.Next()
+	b, hasB := otherIter.Next()
+	for hasA && hasB {
+		lo, hi := tau.Max(a.Lo, b.Lo).(E), tau.Min(a.Hi, b.Hi).(E)
+		if tau.Cmp(lo, hi) < 0 {
+			inter.tree.Insert(tree.Interval[E]{Lo: lo, Hi: hi})
+		}
+		if tau.Cmp(a.Hi, b.Hi) < 0 {
+			a, hasA = iter.Next()
+		} else {
+			b, hasB = otherIter.Next()
+		}
+	}
+	return inter
+}
+
+// Returns a new set with the points in this set but not in the other one
+func (set *IntervalSet[E]) Difference(other *IntervalSet[E]) *IntervalSet[E] {
+	diff := set.Clone().(*IntervalSet[E])
+	other.Iter().Each(func(iv tree.Interval[E]) {
+		diff.Remove(iv)
+	})
+	return diff
+}
+
+// Returns a new set with the points in [lo, hi) that are not in this set
+func (set *IntervalSet[E]) Complement(lo, hi E) *IntervalSet[E] {
+	return Itv(tree.Interval[E]{Lo: lo, Hi: hi}).Difference(set)
+}
+
+// --- Private ---
+
+// minimum degree of the underlying B+ tree
+const itvDegree = 16
+
+// range with the greatest lower endpoint less than the given one
+func (set *IntervalSet[E]) pred(lo E) (tree.Interval[E], bool) {
+	prev, err := set.tree.Pred(tree.Interval[E]{Lo: lo, Hi: lo})
+	if err != nil {
+		return tree.Interval[E]{}, false
+	}
+	return *prev, true
+}
+
+// range containing the given point
+func (set *IntervalSet[E]) covering(point E) (tree.Interval[E], bool) {
+	next, ok := set.tree.From(tree.Interval[E]{Lo: point, Hi: point}).Next()
+	if ok && tau.Cmp(next.Lo, point) == 0 {
+		return *next, true
+	}
+	prev, ok := set.pred(point)
+	if ok && prev.Contains(point) {
+		return prev, true
+	}
+	return tree.Interval[E]{}, false
+}
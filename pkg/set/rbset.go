@@ -2,6 +2,7 @@ package set
 
 import (
 	"fmt"
+	"math/rand/v2"
 
 	"github.com/luverolla/lexgo/pkg/table"
 	"github.com/luverolla/lexgo/pkg/tau"
@@ -35,22 +36,7 @@ func (set *RBSet[T]) String() string {
 }
 
 func (set *RBSet[T]) Cmp(other any) int {
-	otherSet, ok := other.(*RBSet[T])
-	if !ok {
-		panic(fmt.Sprintf("ERROR: [RBSet.Cmp] %v is not a *RBSet", other))
-	}
-	if set.Size() != otherSet.Size() {
-		return set.Size() - otherSet.Size()
-	}
-	iter, otherIter := set.Iter(), otherSet.Iter()
-	for next, hasNext := iter.Next(); hasNext; next, hasNext = iter.Next() {
-		otherNext, _ := otherIter.Next()
-		cmp := tau.Cmp(*next, *otherNext)
-		if cmp != 0 {
-			return cmp
-		}
-	}
-	return 0
+	return tau.CollCmp[T](set, other)
 }
 
 func (set *RBSet[T]) Size() int {
@@ -110,3 +96,10 @@ func (set *RBSet[T]) Remove(value T) error {
 	_, err := set.table.Remove(value)
 	return err
 }
+
+// Returns an element chosen at random, every one with the same probability
+// It takes O(log n) time, see [table.RBMap.RandomElement]
+// Returns an error if the set is empty
+func (set *RBSet[T]) RandomElement(r *rand.Rand) (*T, error) {
+	return set.table.RandomElement(r)
+}
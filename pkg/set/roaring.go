@@ -0,0 +1,582 @@
+package set
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Compressed bitmap set of 32-bit integers (Roaring bitmap)
+//
+// Elements are grouped by their upper 16 bits. Each group is stored in
+// a container that is either a sorted array of the lower 16 bits, when
+// it has at most 4096 elements, or a 65536-bit bitmap otherwise, so
+// that no container takes more than 8 KiB. Both sparse and dense sets
+// take little memory, and set algebra works container by container.
+// Elements are iterated in ascending order.
+type RoaringBitmap struct {
+	// upper 16 bits of the containers, sorted
+	keys  []uint16
+	conts []*container
+	count int
+}
+
+// Creates a new roaring bitmap holding the given values
+func Roaring(values ...uint32) *RoaringBitmap {
+	set := &RoaringBitmap{make([]uint16, 0), make([]*container, 0), 0}
+	set.Add(values...)
+	return set
+}
+
+// --- Methods from Collection[uint32] ---
+func (set *RoaringBitmap) String() string {
+	s := "RoaringBitmap{"
+	first := true
+	set.Iter().Each(func(value uint32) {
+		if !first {
+			s += ", "
+		}
+		first = false
+		s += fmt.Sprintf("%d", value)
+	})
+	s += "}"
+	return s
+}
+
+func (set *RoaringBitmap) Cmp(other any) int {
+	return tau.CollCmp[uint32](set, other)
+}
+
+// Iterates over the elements in ascending order
+func (set *RoaringBitmap) Iter() tau.Iterator[uint32] {
+	return &roaringIter{set, 0, 0}
+}
+
+func (set *RoaringBitmap) Size() int {
+	return set.count
+}
+
+func (set *RoaringBitmap) Empty() bool {
+	return set.count == 0
+}
+
+func (set *RoaringBitmap) Clear() {
+	set.keys = set.keys[:0]
+	set.conts = set.conts[:0]
+	set.count = 0
+}
+
+func (set *RoaringBitmap) Contains(value uint32) bool {
+	pos, found := slices.BinarySearch(set.keys, uint16(value>>16))
+	return found && set.conts[pos].contains(uint16(value))
+}
+
+func (set *RoaringBitmap) ContainsAll(coll tau.Collection[uint32]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !set.Contains(*next) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *RoaringBitmap) ContainsAny(coll tau.Collection[uint32]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if set.Contains(*next) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *RoaringBitmap) Clone() tau.Collection[uint32] {
+	clone := &RoaringBitmap{slices.Clone(set.keys), make([]*container, len(set.conts)), set.count}
+	for i, cont := range set.conts {
+		clone.conts[i] = cont.clone()
+	}
+	return clone
+}
+
+// --- Methods from Set[uint32] ---
+func (set *RoaringBitmap) Add(values ...uint32) {
+	for _, value := range values {
+		key := uint16(value >> 16)
+		pos, found := slices.BinarySearch(set.keys, key)
+		if !found {
+			set.keys = slices.Insert(set.keys, pos, key)
+			set.conts = slices.Insert(set.conts, pos, &container{make([]uint16, 0), nil, 0})
+		}
+		if set.conts[pos].add(uint16(value)) {
+			set.count++
+		}
+	}
+}
+
+func (set *RoaringBitmap) Remove(value uint32) error {
+	pos, found := slices.BinarySearch(set.keys, uint16(value>>16))
+	if !found || !set.conts[pos].remove(uint16(value)) {
+		return errs.NotFound(value)
+	}
+	set.count--
+	if set.conts[pos].card == 0 {
+		set.keys = slices.Delete(set.keys, pos, pos+1)
+		set.conts = slices.Delete(set.conts, pos, pos+1)
+	}
+	return nil
+}
+
+func (set *RoaringBitmap) Subset(filter tau.Filter[uint32]) tau.Set[uint32] {
+	subset := Roaring()
+	set.Iter().Each(func(value uint32) {
+		if filter(value) {
+			subset.Add(value)
+		}
+	})
+	return subset
+}
+
+// --- Set algebra ---
+
+// Returns a new set with the elements in both this set and the other one
+func (set *RoaringBitmap) And(other *RoaringBitmap) *RoaringBitmap {
+	return roaringOp(set, other, opAnd)
+}
+
+// Returns a new set with the elements in either this set or the other one
+func (set *RoaringBitmap) Or(other *RoaringBitmap) *RoaringBitmap {
+	return roaringOp(set, other, opOr)
+}
+
+// Returns a new set with the elements in exactly one of this set and the other one
+func (set *RoaringBitmap) Xor(other *RoaringBitmap) *RoaringBitmap {
+	return roaringOp(set, other, opXor)
+}
+
+// Returns a new set with the elements in this set but not in the other one
+func (set *RoaringBitmap) AndNot(other *RoaringBitmap) *RoaringBitmap {
+	return roaringOp(set, other, opAndNot)
+}
+
+// --- Order statistics ---
+
+// Returns the number of elements less than the given value
+func (set *RoaringBitmap) Rank(value uint32) int {
+	key := uint16(value >> 16)
+	rank := 0
+	for i, cont := range set.conts {
+		if set.keys[i] > key {
+			break
+		}
+		if set.keys[i] < key {
+			rank += cont.card
+		} else {
+			rank += cont.rank(uint16(value))
+		}
+	}
+	return rank
+}
+
+// Returns the element with exactly k smaller elements, so that
+// Select(Rank(v)) is v for all the elements v
+// Returns an error if k is not in [0, size)
+func (set *RoaringBitmap) Select(k int) (uint32, error) {
+	if k < 0 || k >= set.count {
+		return 0, errs.IndexOutOfRange("Select", set, k, set.count)
+	}
+	for i, cont := range set.conts {
+		if k < cont.card {
+			return uint32(set.keys[i])<<16 | uint32(cont.selectK(k)), nil
+		}
+		k -= cont.card
+	}
+	// unreachable, as count is the sum of the cardinalities
+	return 0, errs.IndexOutOfRange("Select", set, k, set.count)
+}
+
+// Returns an element chosen at random, every one with the same probability
+// The global random source is used if r is nil. It takes the time of [RoaringBitmap.Select]
+// Returns an error if the set is empty
+func (set *RoaringBitmap) RandomElement(r *rand.Rand) (*uint32, error) {
+	if set.count == 0 {
+		return nil, errs.Empty()
+	}
+	value, err := set.Select(algo.RandIntN(r, set.count))
+	return &value, err
+}
+
+// --- Serialization ---
+
+// Serializes the set as the number of containers, followed by the key,
+// cardinality and content of every container, all in little endian
+func (set *RoaringBitmap) MarshalBinary() ([]byte, error) {
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(set.keys)))
+	for i, cont := range set.conts {
+		data = binary.LittleEndian.AppendUint16(data, set.keys[i])
+		data = binary.LittleEndian.AppendUint32(data, uint32(cont.card))
+		if cont.bitmap == nil {
+			for _, low := range cont.array {
+				data = binary.LittleEndian.AppendUint16(data, low)
+			}
+		} else {
+			for _, word := range cont.bitmap {
+				data = binary.LittleEndian.AppendUint64(data, word)
+			}
+		}
+	}
+	return data, nil
+}
+
+// Replaces the content of the set with the serialized one
+// Returns an error if the data is not a serialized roaring bitmap
+func (set *RoaringBitmap) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return errs.Mismatch(4, len(data))
+	}
+	n := int(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+	result := Roaring()
+	for i := 0; i < n; i++ {
+		if len(data) < 6 {
+			return errs.Mismatch(6, len(data))
+		}
+		key := binary.LittleEndian.Uint16(data)
+		card := int(binary.LittleEndian.Uint32(data[2:]))
+		data = data[6:]
+		if card == 0 || card > 1<<16 || (i > 0 && key <= result.keys[i-1]) {
+			return errs.Mismatch("sorted non-empty containers", key)
+		}
+		cont := &container{nil, nil, card}
+		if card <= arrayMax {
+			if len(data) < 2*card {
+				return errs.Mismatch(2*card, len(data))
+			}
+			cont.array = make([]uint16, card)
+			for j := range cont.array {
+				cont.array[j] = binary.LittleEndian.Uint16(data[2*j:])
+				if j > 0 && cont.array[j] <= cont.array[j-1] {
+					return errs.Mismatch("sorted array container", cont.array)
+				}
+			}
+			data = data[2*card:]
+		} else {
+			if len(data) < 8*bitmapWords {
+				return errs.Mismatch(8*bitmapWords, len(data))
+			}
+			cont.bitmap = make([]uint64, bitmapWords)
+			ones := 0
+			for j := range cont.bitmap {
+				cont.bitmap[j] = binary.LittleEndian.Uint64(data[8*j:])
+				ones += bits.OnesCount64(cont.bitmap[j])
+			}
+			if ones != card {
+				return errs.Mismatch(card, ones)
+			}
+			data = data[8*bitmapWords:]
+		}
+		result.keys = append(result.keys, key)
+		result.conts = append(result.conts, cont)
+		result.count += card
+	}
+	if len(data) != 0 {
+		return errs.Mismatch(0, len(data))
+	}
+	*set = *result
+	return nil
+}
+
+// --- Private ---
+
+const (
+	// greatest cardinality of an array container
+	arrayMax = 4096
+	// number of words of a bitmap container
+	bitmapWords = 1 << 16 / 64
+)
+
+// operations of the set algebra
+type bitOp int
+
+const (
+	opAnd bitOp = iota
+	opOr
+	opXor
+	opAndNot
+)
+
+func (op bitOp) words(a, b uint64) uint64 {
+	switch op {
+	case opAnd:
+		return a & b
+	case opOr:
+		return a | b
+	case opXor:
+		return a ^ b
+	default:
+		return a &^ b
+	}
+}
+
+// true if the result keeps the values only in a, only in b and in both
+func (op bitOp) keeps() (onlyA, onlyB, both bool) {
+	return op != opAnd, op == opOr || op == opXor, op == opAnd || op == opOr
+}
+
+func roaringOp(a, b *RoaringBitmap, op bitOp) *RoaringBitmap {
+	onlyA, onlyB, _ := op.keeps()
+	result := Roaring()
+	push := func(key uint16, cont *container) {
+		if cont != nil && cont.card != 0 {
+			result.keys = append(result.keys, key)
+			result.conts = append(result.conts, cont)
+			result.count += cont.card
+		}
+	}
+	i, j := 0, 0
+	for i < len(a.keys) || j < len(b.keys) {
+		switch {
+		case j == len(b.keys) || (i < len(a.keys) && a.keys[i] < b.keys[j]):
+			if onlyA {
+				push(a.keys[i], a.conts[i].clone())
+			}
+			i++
+		case i == len(a.keys) || b.keys[j] < a.keys[i]:
+			if onlyB {
+				push(b.keys[j], b.conts[j].clone())
+			}
+			j++
+		default:
+			push(a.keys[i], containerOp(a.conts[i], b.conts[j], op))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// set of 16-bit values, see [RoaringBitmap]
+type container struct {
+	// sorted values, used when bitmap is nil
+	array  []uint16
+	bitmap []uint64
+	card   int
+}
+
+func (cont *container) contains(low uint16) bool {
+	if cont.bitmap != nil {
+		return cont.bitmap[low/64]&(1<<(low%64)) != 0
+	}
+	_, found := slices.BinarySearch(cont.array, low)
+	return found
+}
+
+func (cont *container) add(low uint16) bool {
+	if cont.bitmap != nil {
+		if cont.bitmap[low/64]&(1<<(low%64)) != 0 {
+			return false
+		}
+		cont.bitmap[low/64] |= 1 << (low % 64)
+		cont.card++
+		return true
+	}
+	pos, found := slices.BinarySearch(cont.array, low)
+	if found {
+		return false
+	}
+	cont.array = slices.Insert(cont.array, pos, low)
+	cont.card++
+	cont.normalize()
+	return true
+}
+
+func (cont *container) remove(low uint16) bool {
+	if cont.bitmap != nil {
+		if cont.bitmap[low/64]&(1<<(low%64)) == 0 {
+			return false
+		}
+		cont.bitmap[low/64] &^= 1 << (low % 64)
+		cont.card--
+		cont.normalize()
+		return true
+	}
+	pos, found := slices.BinarySearch(cont.array, low)
+	if !found {
+		return false
+	}
+	cont.array = slices.Delete(cont.array, pos, pos+1)
+	cont.card--
+	return true
+}
+
+// switches to the representation fitting the cardinality
+func (cont *container) normalize() {
+	if cont.bitmap == nil && cont.card > arrayMax {
+		cont.bitmap = cont.words()
+		cont.array = nil
+	} else if cont.bitmap != nil && cont.card <= arrayMax {
+		cont.array = make([]uint16, 0, cont.card)
+		for i, word := range cont.bitmap {
+			for ; word != 0; word &= word - 1 {
+				cont.array = append(cont.array, uint16(64*i+bits.TrailingZeros64(word)))
+			}
+		}
+		cont.bitmap = nil
+	}
+}
+
+// the container as a bitmap, as a new slice
+func (cont *container) words() []uint64 {
+	if cont.bitmap != nil {
+		return slices.Clone(cont.bitmap)
+	}
+	words := make([]uint64, bitmapWords)
+	for _, low := range cont.array {
+		words[low/64] |= 1 << (low % 64)
+	}
+	return words
+}
+
+func (cont *container) clone() *container {
+	return &container{slices.Clone(cont.array), slices.Clone(cont.bitmap), cont.card}
+}
+
+// number of values less than the given one
+func (cont *container) rank(low uint16) int {
+	if cont.bitmap == nil {
+		pos, _ := slices.BinarySearch(cont.array, low)
+		return pos
+	}
+	rank := 0
+	for i := 0; i < int(low/64); i++ {
+		rank += bits.OnesCount64(cont.bitmap[i])
+	}
+	return rank + bits.OnesCount64(cont.bitmap[low/64]&(1<<(low%64)-1))
+}
+
+// value with k smaller values
+func (cont *container) selectK(k int) uint16 {
+	if cont.bitmap == nil {
+		return cont.array[k]
+	}
+	for i, word := range cont.bitmap {
+		ones := bits.OnesCount64(word)
+		if k < ones {
+			return uint16(64*i + selectInWord(word, k))
+		}
+		k -= ones
+	}
+	return 0
+}
+
+func containerOp(a, b *container, op bitOp) *container {
+	if a.bitmap == nil && b.bitmap == nil {
+		array := mergeArrays(a.array, b.array, op)
+		result := &container{array, nil, len(array)}
+		result.normalize()
+		return result
+	}
+	// filtering the array is faster than building a bitmap
+	if op == opAnd && (a.bitmap == nil || b.bitmap == nil) {
+		if a.bitmap != nil {
+			a, b = b, a
+		}
+		return filterArray(a.array, func(low uint16) bool { return b.contains(low) })
+	}
+	if op == opAndNot && a.bitmap == nil {
+		return filterArray(a.array, func(low uint16) bool { return !b.contains(low) })
+	}
+	wa, wb := a.words(), b.words()
+	result := &container{nil, wa, 0}
+	for i := range wa {
+		wa[i] = op.words(wa[i], wb[i])
+		result.card += bits.OnesCount64(wa[i])
+	}
+	result.normalize()
+	return result
+}
+
+func filterArray(array []uint16, keep func(uint16) bool) *container {
+	filtered := make([]uint16, 0)
+	for _, low := range array {
+		if keep(low) {
+			filtered = append(filtered, low)
+		}
+	}
+	return &container{filtered, nil, len(filtered)}
+}
+
+// merges two sorted arrays, keeping the values as the operation says
+func mergeArrays(a, b []uint16, op bitOp) []uint16 {
+	onlyA, onlyB, both := op.keeps()
+	merged := make([]uint16, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			if onlyA {
+				merged = append(merged, a[i])
+			}
+			i++
+		case i == len(a) || b[j] < a[i]:
+			if onlyB {
+				merged = append(merged, b[j])
+			}
+			j++
+		default:
+			if both {
+				merged = append(merged, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	return merged
+}
+
+type roaringIter struct {
+	set *RoaringBitmap
+	// index of the current container
+	cont int
+	// index in the array, or next value to look at in the bitmap
+	pos int
+}
+
+func (iter *roaringIter) Next() (*uint32, bool) {
+	for iter.cont < len(iter.set.conts) {
+		cont := iter.set.conts[iter.cont]
+		high := uint32(iter.set.keys[iter.cont]) << 16
+		if cont.bitmap == nil && iter.pos < len(cont.array) {
+			value := high | uint32(cont.array[iter.pos])
+			iter.pos++
+			return &value, true
+		}
+		for word := iter.pos / 64; cont.bitmap != nil && word < bitmapWords; word++ {
+			rest := cont.bitmap[word]
+			if word == iter.pos/64 {
+				rest &^= 1<<(iter.pos%64) - 1
+			}
+			if rest != 0 {
+				low := 64*word + bits.TrailingZeros64(rest)
+				iter.pos = low + 1
+				value := high | uint32(low)
+				return &value, true
+			}
+		}
+		iter.cont++
+		iter.pos = 0
+	}
+	return nil, false
+}
+
+func (iter *roaringIter) Each(f func(uint32)) {
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		f(*next)
+	}
+}
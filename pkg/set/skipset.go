@@ -0,0 +1,113 @@
+package set
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Implementation of a set using a skip list
+type SkipSet[T any] struct {
+	table *table.SkipMap[T, any]
+}
+
+// Creates a new set implemented with a skip list, with a random seed
+func Skip[T any]() *SkipSet[T] {
+	return &SkipSet[T]{table.Skip[T, any]()}
+}
+
+// Creates a new set implemented with a skip list, whose levels
+// are drawn from a random source with the given seed
+func SkipSeeded[T any](seed uint64) *SkipSet[T] {
+	return &SkipSet[T]{table.SkipSeeded[T, any](seed)}
+}
+
+// --- Methods from Collection[T] ---
+func (set *SkipSet[T]) String() string {
+	s := "SkipSet{"
+	iter := set.Iter()
+	first := true
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if first {
+			first = false
+		} else {
+			s += ", "
+		}
+		s += fmt.Sprintf("%v", *next)
+	}
+	s += "}"
+	return s
+}
+
+func (set *SkipSet[T]) Cmp(other any) int {
+	return tau.CollCmp[T](set, other)
+}
+
+func (set *SkipSet[T]) Size() int {
+	return set.table.Size()
+}
+
+func (set *SkipSet[T]) Empty() bool {
+	return set.table.Empty()
+}
+
+func (set *SkipSet[T]) Clear() {
+	set.table.Clear()
+}
+
+func (set *SkipSet[T]) Contains(value T) bool {
+	return set.table.HasKey(value)
+}
+
+func (set *SkipSet[T]) ContainsAll(coll tau.Collection[T]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !set.table.HasKey(*next) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *SkipSet[T]) ContainsAny(coll tau.Collection[T]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if set.table.HasKey(*next) {
+			return true
+		}
+	}
+	return false
+}
+
+func (set *SkipSet[T]) Iter() tau.Iterator[T] {
+	return set.table.Keys()
+}
+
+func (set *SkipSet[T]) Clone() tau.Collection[T] {
+	return &SkipSet[T]{set.table.Clone().(*table.SkipMap[T, any])}
+}
+
+// --- Methods from Set[T] ---
+func (set *SkipSet[T]) Add(values ...T) {
+	for _, value := range values {
+		if !set.table.HasKey(value) {
+			set.table.Put(value, nil)
+		}
+	}
+}
+
+func (set *SkipSet[T]) Remove(value T) error {
+	_, err := set.table.Remove(value)
+	return err
+}
+
+func (set *SkipSet[T]) Subset(filter tau.Filter[T]) tau.Set[T] {
+	subset := &SkipSet[T]{set.table.Clone().(*table.SkipMap[T, any])}
+	set.Iter().Each(func(value T) {
+		if !filter(value) {
+			subset.Remove(value)
+		}
+	})
+	return subset
+}
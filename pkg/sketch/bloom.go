@@ -0,0 +1,258 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+)
+
+// Bloom filter, answering whether an element may have been added
+//
+// There are no false negatives: Contains always returns true for the
+// added elements. It may return true for other elements too, with a
+// probability close to the one chosen at creation, as long as no more
+// than the expected number of elements are added.
+type BloomFilter[T any] struct {
+	bits []uint64
+	// number of bits and of hash functions
+	m, k uint64
+}
+
+// Creates a new Bloom filter sized for n elements and the given false positive rate
+// Panics if n is not positive or the rate is not in (0, 1)
+func Bloom[T any](n int, fpRate float64) *BloomFilter[T] {
+	m, k := bloomParams("Bloom", n, fpRate)
+	return &BloomFilter[T]{make([]uint64, m/64), m, k}
+}
+
+func (bf *BloomFilter[T]) String() string {
+	return fmt.Sprintf("BloomFilter{bits: %d, hashes: %d}", bf.m, bf.k)
+}
+
+// Returns the number of bits
+func (bf *BloomFilter[T]) Bits() int {
+	return int(bf.m)
+}
+
+// Returns the number of hash functions
+func (bf *BloomFilter[T]) Hashes() int {
+	return int(bf.k)
+}
+
+// Adds the given elements
+func (bf *BloomFilter[T]) Add(values ...T) {
+	for _, value := range values {
+		h1, h2 := hashPair(value)
+		for i := uint64(0); i < bf.k; i++ {
+			bit := (h1 + i*h2) % bf.m
+			bf.bits[bit/64] |= 1 << (bit % 64)
+		}
+	}
+}
+
+// Returns false if the given element was surely never added
+func (bf *BloomFilter[T]) Contains(value T) bool {
+	h1, h2 := hashPair(value)
+	for i := uint64(0); i < bf.k; i++ {
+		bit := (h1 + i*h2) % bf.m
+		if bf.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the false positive rate, estimated from the bits that are set
+func (bf *BloomFilter[T]) FalsePositiveRate() float64 {
+	set := 0
+	for _, word := range bf.bits {
+		set += bits.OnesCount64(word)
+	}
+	return math.Pow(float64(set)/float64(bf.m), float64(bf.k))
+}
+
+// Removes all the elements
+func (bf *BloomFilter[T]) Clear() {
+	clear(bf.bits)
+}
+
+// Makes a copy of the filter
+func (bf *BloomFilter[T]) Clone() *BloomFilter[T] {
+	clone := &BloomFilter[T]{make([]uint64, len(bf.bits)), bf.m, bf.k}
+	copy(clone.bits, bf.bits)
+	return clone
+}
+
+// Adds all the elements of the other filter to this one
+// Returns an error if the two filters were created with different parameters
+func (bf *BloomFilter[T]) Merge(other *BloomFilter[T]) error {
+	if bf.m != other.m || bf.k != other.k {
+		return errs.Mismatch(bf, other)
+	}
+	for i, word := range other.bits {
+		bf.bits[i] |= word
+	}
+	return nil
+}
+
+func (bf *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	enc := newEncoder(bloomKind)
+	enc.uint64(bf.m)
+	enc.uint64(bf.k)
+	for _, word := range bf.bits {
+		enc.uint64(word)
+	}
+	return enc.buf, nil
+}
+
+// Replaces the content of the filter with the serialized one
+// Returns an error if the data is not a serialized Bloom filter
+func (bf *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	dec := newDecoder(data, bloomKind)
+	m, k := dec.uint64(), dec.uint64()
+	if dec.err == nil && (m == 0 || m%64 != 0 || k == 0) {
+		return errs.Mismatch("positive multiple of 64 bits", m)
+	}
+	// checked before allocating, as m comes from the data
+	if dec.err == nil && uint64(len(dec.buf)) != m/8 {
+		return errs.Mismatch(m/8, len(dec.buf))
+	}
+	words := make([]uint64, m/64)
+	for i := range words {
+		words[i] = dec.uint64()
+	}
+	if err := dec.finish(); err != nil {
+		return err
+	}
+	bf.bits, bf.m, bf.k = words, m, k
+	return nil
+}
+
+// Bloom filter that also allows removals, by keeping a counter for
+// every position instead of a single bit
+//
+// Counters saturate at 255 and then are never decremented, so that
+// removals can't cause false negatives. Removing an element that was
+// never added may cause them, though.
+type CountingBloomFilter[T any] struct {
+	counters []uint8
+	// number of counters and of hash functions
+	m, k uint64
+}
+
+// Creates a new counting Bloom filter sized for n elements and the given false positive rate
+// Panics if n is not positive or the rate is not in (0, 1)
+func CountingBloom[T any](n int, fpRate float64) *CountingBloomFilter[T] {
+	m, k := bloomParams("CountingBloom", n, fpRate)
+	return &CountingBloomFilter[T]{make([]uint8, m), m, k}
+}
+
+func (cbf *CountingBloomFilter[T]) String() string {
+	return fmt.Sprintf("CountingBloomFilter{counters: %d, hashes: %d}", cbf.m, cbf.k)
+}
+
+// Adds the given elements
+func (cbf *CountingBloomFilter[T]) Add(values ...T) {
+	for _, value := range values {
+		h1, h2 := hashPair(value)
+		for i := uint64(0); i < cbf.k; i++ {
+			if pos := (h1 + i*h2) % cbf.m; cbf.counters[pos] != math.MaxUint8 {
+				cbf.counters[pos]++
+			}
+		}
+	}
+}
+
+// Removes one occurrence of the given element
+// Returns an error if the element was surely never added
+func (cbf *CountingBloomFilter[T]) Remove(value T) error {
+	if !cbf.Contains(value) {
+		return errs.NotFound(value)
+	}
+	h1, h2 := hashPair(value)
+	for i := uint64(0); i < cbf.k; i++ {
+		if pos := (h1 + i*h2) % cbf.m; cbf.counters[pos] != math.MaxUint8 {
+			cbf.counters[pos]--
+		}
+	}
+	return nil
+}
+
+// Returns false if the given element was surely never added, or removed
+// as many times as it was added
+func (cbf *CountingBloomFilter[T]) Contains(value T) bool {
+	h1, h2 := hashPair(value)
+	for i := uint64(0); i < cbf.k; i++ {
+		if cbf.counters[(h1+i*h2)%cbf.m] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Removes all the elements
+func (cbf *CountingBloomFilter[T]) Clear() {
+	clear(cbf.counters)
+}
+
+// Makes a copy of the filter
+func (cbf *CountingBloomFilter[T]) Clone() *CountingBloomFilter[T] {
+	clone := &CountingBloomFilter[T]{make([]uint8, len(cbf.counters)), cbf.m, cbf.k}
+	copy(clone.counters, cbf.counters)
+	return clone
+}
+
+// Adds all the elements of the other filter to this one
+// Returns an error if the two filters were created with different parameters
+func (cbf *CountingBloomFilter[T]) Merge(other *CountingBloomFilter[T]) error {
+	if cbf.m != other.m || cbf.k != other.k {
+		return errs.Mismatch(cbf, other)
+	}
+	for i, count := range other.counters {
+		sum := int(cbf.counters[i]) + int(count)
+		cbf.counters[i] = uint8(min(sum, math.MaxUint8))
+	}
+	return nil
+}
+
+func (cbf *CountingBloomFilter[T]) MarshalBinary() ([]byte, error) {
+	enc := newEncoder(countingBloomKind)
+	enc.uint64(cbf.m)
+	enc.uint64(cbf.k)
+	enc.bytes(cbf.counters)
+	return enc.buf, nil
+}
+
+// Replaces the content of the filter with the serialized one
+// Returns an error if the data is not a serialized counting Bloom filter
+func (cbf *CountingBloomFilter[T]) UnmarshalBinary(data []byte) error {
+	dec := newDecoder(data, countingBloomKind)
+	m, k := dec.uint64(), dec.uint64()
+	counters := dec.bytes()
+	if err := dec.finish(); err != nil {
+		return err
+	}
+	if m == 0 || k == 0 || uint64(len(counters)) != m {
+		return errs.Mismatch(m, len(counters))
+	}
+	cbf.counters, cbf.m, cbf.k = counters, m, k
+	return nil
+}
+
+// --- Private functions ---
+
+// optimal number of bits, rounded up to a multiple of 64, and of hash functions
+func bloomParams(name string, n int, fpRate float64) (uint64, uint64) {
+	if n <= 0 {
+		panic(fmt.Sprintf("ERROR: [sketch.%s] number of elements must be positive, got %d", name, n))
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		panic(fmt.Sprintf("ERROR: [sketch.%s] false positive rate must be in (0, 1), got %v", name, fpRate))
+	}
+	bitsPerElem := -math.Log(fpRate) / (math.Ln2 * math.Ln2)
+	m := uint64(math.Ceil(float64(n)*bitsPerElem/64)) * 64
+	k := uint64(math.Round(bitsPerElem * math.Ln2))
+	return m, max(k, 1)
+}
@@ -0,0 +1,124 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+)
+
+// Count-min sketch, estimating how many times each element was added
+//
+// Estimates are never lower than the true counts. With probability at
+// least 1 - delta, they exceed them by at most epsilon times the total
+// of all the counts.
+type CountMinSketch[T any] struct {
+	// depth rows of width counters each
+	counts []uint64
+	width  uint64
+	depth  uint64
+	total  uint64
+}
+
+// Creates a new count-min sketch with the given error bounds
+// Panics if epsilon or delta are not in (0, 1)
+func CountMin[T any](epsilon, delta float64) *CountMinSketch[T] {
+	if epsilon <= 0 || epsilon >= 1 {
+		panic(fmt.Sprintf("ERROR: [sketch.CountMin] epsilon must be in (0, 1), got %v", epsilon))
+	}
+	if delta <= 0 || delta >= 1 {
+		panic(fmt.Sprintf("ERROR: [sketch.CountMin] delta must be in (0, 1), got %v", delta))
+	}
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := uint64(math.Ceil(math.Log(1 / delta)))
+	return &CountMinSketch[T]{make([]uint64, width*depth), width, depth, 0}
+}
+
+func (cms *CountMinSketch[T]) String() string {
+	return fmt.Sprintf("CountMinSketch{width: %d, depth: %d, total: %d}", cms.width, cms.depth, cms.total)
+}
+
+// Adds the given amount to the count of the given element
+func (cms *CountMinSketch[T]) Add(value T, count uint64) {
+	h1, h2 := hashPair(value)
+	for row := uint64(0); row < cms.depth; row++ {
+		cms.counts[row*cms.width+(h1+row*h2)%cms.width] += count
+	}
+	cms.total += count
+}
+
+// Returns the estimated count of the given element
+func (cms *CountMinSketch[T]) Count(value T) uint64 {
+	h1, h2 := hashPair(value)
+	est := uint64(math.MaxUint64)
+	for row := uint64(0); row < cms.depth; row++ {
+		est = min(est, cms.counts[row*cms.width+(h1+row*h2)%cms.width])
+	}
+	return est
+}
+
+// Returns the sum of all the counts
+func (cms *CountMinSketch[T]) Total() uint64 {
+	return cms.total
+}
+
+// Removes all the counts
+func (cms *CountMinSketch[T]) Clear() {
+	clear(cms.counts)
+	cms.total = 0
+}
+
+// Makes a copy of the sketch
+func (cms *CountMinSketch[T]) Clone() *CountMinSketch[T] {
+	clone := *cms
+	clone.counts = make([]uint64, len(cms.counts))
+	copy(clone.counts, cms.counts)
+	return &clone
+}
+
+// Adds the counts of the other sketch to this one
+// Returns an error if the two sketches were created with different parameters
+func (cms *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if cms.width != other.width || cms.depth != other.depth {
+		return errs.Mismatch(cms, other)
+	}
+	for i, count := range other.counts {
+		cms.counts[i] += count
+	}
+	cms.total += other.total
+	return nil
+}
+
+func (cms *CountMinSketch[T]) MarshalBinary() ([]byte, error) {
+	enc := newEncoder(countMinKind)
+	enc.uint64(cms.width)
+	enc.uint64(cms.depth)
+	enc.uint64(cms.total)
+	for _, count := range cms.counts {
+		enc.uint64(count)
+	}
+	return enc.buf, nil
+}
+
+// Replaces the content of the sketch with the serialized one
+// Returns an error if the data is not a serialized count-min sketch
+func (cms *CountMinSketch[T]) UnmarshalBinary(data []byte) error {
+	dec := newDecoder(data, countMinKind)
+	width, depth, total := dec.uint64(), dec.uint64(), dec.uint64()
+	// the number of counters is checked with divisions, so that a corrupt
+	// header can't overflow it
+	words := uint64(len(dec.buf)) / 8
+	if dec.err == nil && (width == 0 || depth == 0 || len(dec.buf)%8 != 0 ||
+		words%width != 0 || words/width != depth) {
+		return errs.Mismatch(fmt.Sprintf("%d x %d counters", width, depth), len(dec.buf))
+	}
+	counts := make([]uint64, width*depth)
+	for i := range counts {
+		counts[i] = dec.uint64()
+	}
+	if err := dec.finish(); err != nil {
+		return err
+	}
+	cms.counts, cms.width, cms.depth, cms.total = counts, width, depth, total
+	return nil
+}
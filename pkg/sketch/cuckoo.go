@@ -0,0 +1,246 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+)
+
+// Cuckoo filter, answering whether an element may have been added
+// and allowing removals
+//
+// Like [BloomFilter] it has no false negatives, and false positives
+// happen with about the chosen probability. It stores a short
+// fingerprint of every element in one of two buckets, so adding fails
+// when the filter is close to its capacity. Removing an element that
+// was never added may remove another one with the same fingerprint.
+type CuckooFilter[T any] struct {
+	// bucketSize slots per bucket, 0 is an empty slot
+	slots []uint16
+	// number of buckets minus one, the number being a power of two
+	mask uint64
+	// number of bits of a fingerprint
+	fpBits uint64
+	count  int
+	// state of the generator choosing the fingerprints to kick out
+	rnd uint64
+}
+
+// Creates a new cuckoo filter able to hold about the given number of elements
+// with the given false positive rate
+// Panics if the capacity is not positive or the rate is not in (0, 1)
+func Cuckoo[T any](capacity int, fpRate float64) *CuckooFilter[T] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("ERROR: [sketch.Cuckoo] capacity must be positive, got %d", capacity))
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		panic(fmt.Sprintf("ERROR: [sketch.Cuckoo] false positive rate must be in (0, 1), got %v", fpRate))
+	}
+	// an element is looked for in 2 * bucketSize slots
+	fpBits := uint64(math.Ceil(math.Log2(2 * bucketSize / fpRate)))
+	fpBits = min(max(fpBits, 4), 16)
+	buckets := uint64(math.Ceil(float64(capacity) / (bucketSize * maxLoad)))
+	buckets = 1 << bits.Len64(buckets-1)
+	return &CuckooFilter[T]{make([]uint16, buckets*bucketSize), buckets - 1, fpBits, 0, 1}
+}
+
+func (cf *CuckooFilter[T]) String() string {
+	return fmt.Sprintf("CuckooFilter{size: %d, capacity: %d, fingerprint bits: %d}", cf.count, cf.Capacity(), cf.fpBits)
+}
+
+// Returns the number of elements
+func (cf *CuckooFilter[T]) Size() int {
+	return cf.count
+}
+
+// Returns the number of slots, which is a bound on the number of elements
+func (cf *CuckooFilter[T]) Capacity() int {
+	return len(cf.slots)
+}
+
+// Adds the given element
+// Returns an error if the filter is too full to hold it
+func (cf *CuckooFilter[T]) Add(value T) error {
+	fp, i1 := cf.locate(value)
+	if !cf.insert(fp, i1) {
+		return errs.CapacityExceeded("Add", cf, cf.Capacity())
+	}
+	return nil
+}
+
+// Removes one occurrence of the given element
+// Returns an error if the element was surely never added
+func (cf *CuckooFilter[T]) Remove(value T) error {
+	fp, i1 := cf.locate(value)
+	for _, index := range [2]uint64{i1, cf.alt(i1, fp)} {
+		for slot := index * bucketSize; slot < (index+1)*bucketSize; slot++ {
+			if cf.slots[slot] == fp {
+				cf.slots[slot] = 0
+				cf.count--
+				return nil
+			}
+		}
+	}
+	return errs.NotFound(value)
+}
+
+// Returns false if the given element was surely never added, or removed
+// as many times as it was added
+func (cf *CuckooFilter[T]) Contains(value T) bool {
+	fp, i1 := cf.locate(value)
+	return cf.bucketHas(i1, fp) || cf.bucketHas(cf.alt(i1, fp), fp)
+}
+
+// Removes all the elements
+func (cf *CuckooFilter[T]) Clear() {
+	clear(cf.slots)
+	cf.count = 0
+}
+
+// Makes a copy of the filter
+func (cf *CuckooFilter[T]) Clone() *CuckooFilter[T] {
+	clone := *cf
+	clone.slots = make([]uint16, len(cf.slots))
+	copy(clone.slots, cf.slots)
+	return &clone
+}
+
+// Adds all the elements of the other filter to this one
+// Returns an error if the two filters were created with different parameters,
+// or if this one becomes too full. In the latter case, only some of the
+// elements are added
+func (cf *CuckooFilter[T]) Merge(other *CuckooFilter[T]) error {
+	if cf.mask != other.mask || cf.fpBits != other.fpBits {
+		return errs.Mismatch(cf, other)
+	}
+	for slot, fp := range other.slots {
+		if fp != 0 && !cf.insert(fp, uint64(slot/bucketSize)) {
+			return errs.CapacityExceeded("Merge", cf, cf.Capacity())
+		}
+	}
+	return nil
+}
+
+func (cf *CuckooFilter[T]) MarshalBinary() ([]byte, error) {
+	enc := newEncoder(cuckooKind)
+	enc.uint64(cf.mask + 1)
+	enc.uint64(cf.fpBits)
+	data := make([]byte, 0, 2*len(cf.slots))
+	for _, fp := range cf.slots {
+		data = binary.LittleEndian.AppendUint16(data, fp)
+	}
+	enc.bytes(data)
+	return enc.buf, nil
+}
+
+// Replaces the content of the filter with the serialized one
+// Returns an error if the data is not a serialized cuckoo filter
+func (cf *CuckooFilter[T]) UnmarshalBinary(data []byte) error {
+	dec := newDecoder(data, cuckooKind)
+	buckets, fpBits := dec.uint64(), dec.uint64()
+	raw := dec.bytes()
+	if err := dec.finish(); err != nil {
+		return err
+	}
+	if buckets == 0 || buckets&(buckets-1) != 0 || fpBits < 4 || fpBits > 16 {
+		return errs.Mismatch("power of two buckets", buckets)
+	}
+	if buckets > uint64(len(raw)) || uint64(len(raw)) != 2*buckets*bucketSize {
+		return errs.Mismatch(2*buckets*bucketSize, len(raw))
+	}
+	slots := make([]uint16, buckets*bucketSize)
+	count := 0
+	for i := range slots {
+		slots[i] = binary.LittleEndian.Uint16(raw[2*i:])
+		if slots[i] != 0 {
+			count++
+		}
+	}
+	cf.slots, cf.mask, cf.fpBits, cf.count = slots, buckets-1, fpBits, count
+	return nil
+}
+
+// --- Private ---
+
+const (
+	bucketSize = 4
+	// fraction of the slots that can be filled, on average, before adding fails
+	maxLoad = 0.95
+	// number of fingerprints kicked out before giving up an insertion
+	maxKicks = 500
+)
+
+// fingerprint, never 0, and first bucket of a value
+func (cf *CuckooFilter[T]) locate(value T) (uint16, uint64) {
+	h := hash64(value)
+	fp := uint16((h>>32)%(1<<cf.fpBits-1) + 1)
+	return fp, h & cf.mask
+}
+
+// other bucket of a fingerprint, computable from either of the two
+func (cf *CuckooFilter[T]) alt(index uint64, fp uint16) uint64 {
+	return (index ^ (uint64(fp) * 0x5bd1e995)) & cf.mask
+}
+
+func (cf *CuckooFilter[T]) bucketHas(index uint64, fp uint16) bool {
+	for slot := index * bucketSize; slot < (index+1)*bucketSize; slot++ {
+		if cf.slots[slot] == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// puts the fingerprint in a free slot of the given bucket, if any
+func (cf *CuckooFilter[T]) place(index uint64, fp uint16) bool {
+	for slot := index * bucketSize; slot < (index+1)*bucketSize; slot++ {
+		if cf.slots[slot] == 0 {
+			cf.slots[slot] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// inserts a fingerprint in one of its buckets, kicking other fingerprints
+// to their alternate bucket when both are full
+// If that fails, the kicks are undone, leaving the filter as it was
+func (cf *CuckooFilter[T]) insert(fp uint16, index uint64) bool {
+	if cf.place(index, fp) || cf.place(cf.alt(index, fp), fp) {
+		cf.count++
+		return true
+	}
+	if cf.next()&1 == 1 {
+		index = cf.alt(index, fp)
+	}
+	kicked := make([]uint64, 0, maxKicks)
+	for n := 0; n < maxKicks; n++ {
+		slot := index*bucketSize + cf.next()%bucketSize
+		fp, cf.slots[slot] = cf.slots[slot], fp
+		kicked = append(kicked, slot)
+		index = cf.alt(index, fp)
+		if cf.place(index, fp) {
+			cf.count++
+			return true
+		}
+	}
+	for n := len(kicked) - 1; n >= 0; n-- {
+		fp, cf.slots[kicked[n]] = cf.slots[kicked[n]], fp
+	}
+	return false
+}
+
+// xorshift64 generator
+// A zero state, as in a filter decoded into a zero value, would never change
+func (cf *CuckooFilter[T]) next() uint64 {
+	if cf.rnd == 0 {
+		cf.rnd = 1
+	}
+	cf.rnd ^= cf.rnd << 13
+	cf.rnd ^= cf.rnd >> 7
+	cf.rnd ^= cf.rnd << 17
+	return cf.rnd
+}
@@ -0,0 +1,135 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+)
+
+// HyperLogLog sketch, estimating the number of distinct elements added
+//
+// The relative standard error of the estimate is about 1.04 / sqrt(m),
+// where m is the number of registers, a power of two between 16 and 65536.
+// Since [tau.Hash] gives 32-bit values, elements colliding on it are
+// counted once, so estimates above a few hundred million lose accuracy.
+type HyperLogLog[T any] struct {
+	registers []uint8
+	// number of hash bits choosing the register
+	precision uint64
+}
+
+// Creates a new HyperLogLog sketch with the given relative standard error
+// Panics if the error is not in (0, 1)
+func HLL[T any](stdError float64) *HyperLogLog[T] {
+	if stdError <= 0 || stdError >= 1 {
+		panic(fmt.Sprintf("ERROR: [sketch.HLL] standard error must be in (0, 1), got %v", stdError))
+	}
+	registers := (1.04 / stdError) * (1.04 / stdError)
+	precision := uint64(math.Ceil(math.Log2(registers)))
+	precision = min(max(precision, minPrecision), maxPrecision)
+	return &HyperLogLog[T]{make([]uint8, 1<<precision), precision}
+}
+
+func (hll *HyperLogLog[T]) String() string {
+	return fmt.Sprintf("HyperLogLog{registers: %d, estimate: %d}", len(hll.registers), hll.Count())
+}
+
+// Returns the relative standard error of the estimates
+func (hll *HyperLogLog[T]) StdError() float64 {
+	return 1.04 / math.Sqrt(float64(len(hll.registers)))
+}
+
+// Adds the given elements
+func (hll *HyperLogLog[T]) Add(values ...T) {
+	for _, value := range values {
+		h := hash64(value)
+		index := h >> (64 - hll.precision)
+		// position of the first set bit among the remaining ones
+		rank := uint8(bits.LeadingZeros64(h<<hll.precision|1<<(hll.precision-1)) + 1)
+		hll.registers[index] = max(hll.registers[index], rank)
+	}
+}
+
+// Returns the estimated number of distinct elements added
+func (hll *HyperLogLog[T]) Count() uint64 {
+	m := float64(len(hll.registers))
+	sum, zeros := 0.0, 0
+	for _, rank := range hll.registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	alpha := 0.7213 / (1 + 1.079/m)
+	switch m {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	}
+	est := alpha * m * m / sum
+	if est <= 2.5*m && zeros != 0 {
+		// linear counting is more accurate for small cardinalities
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(est))
+}
+
+// Removes all the elements
+func (hll *HyperLogLog[T]) Clear() {
+	clear(hll.registers)
+}
+
+// Makes a copy of the sketch
+func (hll *HyperLogLog[T]) Clone() *HyperLogLog[T] {
+	clone := &HyperLogLog[T]{make([]uint8, len(hll.registers)), hll.precision}
+	copy(clone.registers, hll.registers)
+	return clone
+}
+
+// Adds all the elements of the other sketch to this one
+// Returns an error if the two sketches were created with different parameters
+func (hll *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if hll.precision != other.precision {
+		return errs.Mismatch(hll, other)
+	}
+	for i, rank := range other.registers {
+		hll.registers[i] = max(hll.registers[i], rank)
+	}
+	return nil
+}
+
+func (hll *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	enc := newEncoder(hyperLogLogKind)
+	enc.uint64(hll.precision)
+	enc.bytes(hll.registers)
+	return enc.buf, nil
+}
+
+// Replaces the content of the sketch with the serialized one
+// Returns an error if the data is not a serialized HyperLogLog sketch
+func (hll *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	dec := newDecoder(data, hyperLogLogKind)
+	precision := dec.uint64()
+	registers := dec.bytes()
+	if err := dec.finish(); err != nil {
+		return err
+	}
+	if precision < minPrecision || precision > maxPrecision || len(registers) != 1<<precision {
+		return errs.Mismatch(uint64(1)<<min(precision, 63), len(registers))
+	}
+	hll.registers, hll.precision = registers, precision
+	return nil
+}
+
+// --- Private ---
+
+// bounds of the number of hash bits choosing the register
+const (
+	minPrecision = 4
+	maxPrecision = 16
+)
@@ -0,0 +1,116 @@
+// This package contains probabilistic data structures, that answer
+// membership, frequency and cardinality queries on large streams
+// using a small, fixed amount of memory
+//
+// Elements are hashed with [tau.Hash], so the sketches accept the same
+// types as [set.HshSet]. The answers are approximate, within error
+// bounds chosen when the sketch is created. Two sketches created with
+// the same parameters can be merged, and every sketch can be serialized
+// with MarshalBinary and restored with UnmarshalBinary.
+package sketch
+
+import (
+	"encoding/binary"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// --- Private functions ---
+
+// 64-bit hash of a value, spreading the bits of [tau.Hash], which is
+// the identity for small integers
+func hash64(value any) uint64 {
+	// splitmix64 finalizer
+	h := uint64(tau.Hash(value)) + 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+// the two halves of the 64-bit hash, the second one being odd, so that
+// h1 + i*h2 gives k well spread indices (Kirsch-Mitzenmacher)
+func hashPair(value any) (uint64, uint64) {
+	h := hash64(value)
+	return h & 0xffffffff, (h >> 32) | 1
+}
+
+// --- Serialization ---
+
+// first byte of the serialized sketches, telling their kind
+const (
+	bloomKind byte = iota + 1
+	countingBloomKind
+	cuckooKind
+	countMinKind
+	hyperLogLogKind
+)
+
+type encoder struct {
+	buf []byte
+}
+
+func newEncoder(kind byte) *encoder {
+	return &encoder{[]byte{kind}}
+}
+
+func (enc *encoder) uint64(v uint64) {
+	enc.buf = binary.LittleEndian.AppendUint64(enc.buf, v)
+}
+
+func (enc *encoder) bytes(data []byte) {
+	enc.uint64(uint64(len(data)))
+	enc.buf = append(enc.buf, data...)
+}
+
+type decoder struct {
+	buf []byte
+	err error
+}
+
+// reads the kind byte, failing if it's not the expected one
+func newDecoder(data []byte, kind byte) *decoder {
+	if len(data) == 0 {
+		return &decoder{nil, errs.Mismatch(kind, "no data")}
+	}
+	if data[0] != kind {
+		return &decoder{nil, errs.Mismatch(kind, data[0])}
+	}
+	return &decoder{data[1:], nil}
+}
+
+func (dec *decoder) uint64() uint64 {
+	if dec.err != nil {
+		return 0
+	}
+	if len(dec.buf) < 8 {
+		dec.err = errs.Mismatch(8, len(dec.buf))
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(dec.buf)
+	dec.buf = dec.buf[8:]
+	return v
+}
+
+func (dec *decoder) bytes() []byte {
+	n := dec.uint64()
+	if dec.err != nil {
+		return nil
+	}
+	if uint64(len(dec.buf)) < n {
+		dec.err = errs.Mismatch(n, len(dec.buf))
+		return nil
+	}
+	data := make([]byte, n)
+	copy(data, dec.buf)
+	dec.buf = dec.buf[n:]
+	return data
+}
+
+// returns the first error found, or an error if there's data left
+func (dec *decoder) finish() error {
+	if dec.err == nil && len(dec.buf) != 0 {
+		dec.err = errs.Mismatch(0, len(dec.buf))
+	}
+	return dec.err
+}
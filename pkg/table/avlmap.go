@@ -3,7 +3,7 @@ package table
 
 import (
 	"fmt"
-	"log"
+	"math/rand/v2"
 
 	"github.com/luverolla/lexgo/pkg/errs"
 	"github.com/luverolla/lexgo/pkg/tau"
@@ -35,25 +35,7 @@ func (table *AVLMap[K, V]) String() string {
 }
 
 func (table *AVLMap[K, V]) Cmp(other any) int {
-	oth, ok := other.(*AVLMap[K, V])
-	if !ok {
-		panic(fmt.Sprintf("ERROR: [AVLMap.Cmp] %v is not a *AVLMap", other))
-	}
-
-	if table.Size() != oth.Size() {
-		return table.Size() - oth.Size()
-	}
-
-	iter, otherIter := table.tree.Iter(), oth.tree.Iter()
-	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
-		otherNext, _ := otherIter.Next()
-		cmp := tau.Cmp(*next, *otherNext)
-		if cmp != 0 {
-			return cmp
-		}
-	}
-
-	return 0
+	return tau.MapCmp[K, V](table, other)
 }
 
 func (table *AVLMap[K, V]) Iter() tau.Iterator[K] {
@@ -148,6 +130,17 @@ func (table *AVLMap[K, V]) Values() tau.Iterator[V] {
 	return newAVLValueIter[K](table)
 }
 
+// Returns a key chosen at random, every one with the same probability
+// The global random source is used if r is nil. It takes O(log n) time
+// Returns an error if the map is empty
+func (table *AVLMap[K, V]) RandomElement(r *rand.Rand) (*K, error) {
+	entry, err := table.tree.RandomElement(r)
+	if err != nil {
+		return nil, err
+	}
+	return &entry.key, nil
+}
+
 // --- Iterator ---
 type avlKeyIter[K any, V any] struct {
 	inner tau.Iterator[avlEntry[K, V]]
@@ -202,7 +195,7 @@ type avlEntry[K any, V any] struct {
 func (entry avlEntry[K, V]) Cmp(other any) int {
 	oth, ok := other.(avlEntry[K, V])
 	if !ok {
-		log.Fatalf("ERROR: [table.AVLTreeMap] right hand side of comparison is not an AVL entry")
+		panic(errs.TypeMismatch("Cmp", entry, fmt.Sprintf("%T", entry), other))
 	}
 	return tau.Cmp(entry.key, oth.key)
 }
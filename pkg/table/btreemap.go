@@ -0,0 +1,209 @@
+package table
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+// Sorted map implemented with a B+ tree
+//
+// Entries are stored many per node, which makes it more cache-friendly
+// than [RBMap] for large maps, and range scans walk the linked leaves.
+type BTreeMap[K any, V any] struct {
+	tree *tree.BTree[btEntry[K, V]]
+}
+
+// Creates a new map implemented with a B+ tree with the given minimum degree
+// See [tree.B] for the meaning of the degree
+func B[K any, V any](degree int) *BTreeMap[K, V] {
+	return &BTreeMap[K, V]{tree.B[btEntry[K, V]](degree)}
+}
+
+// --- Methods from Collection[K] ---
+func (table *BTreeMap[K, V]) String() string {
+	s := "BTreeMap["
+	first := true
+	table.tree.Iter().Each(func(entry btEntry[K, V]) {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += entry.String()
+	})
+	s += "]"
+	return s
+}
+
+func (table *BTreeMap[K, V]) Cmp(other any) int {
+	return tau.MapCmp[K, V](table, other)
+}
+
+func (table *BTreeMap[K, V]) Iter() tau.Iterator[K] {
+	return table.Keys()
+}
+
+func (table *BTreeMap[K, V]) Size() int {
+	return table.tree.Size()
+}
+
+func (table *BTreeMap[K, V]) Empty() bool {
+	return table.tree.Empty()
+}
+
+func (table *BTreeMap[K, V]) Clear() {
+	table.tree.Clear()
+}
+
+func (table *BTreeMap[K, V]) Contains(key K) bool {
+	return table.tree.Contains(btEntry[K, V]{key: key})
+}
+
+func (table *BTreeMap[K, V]) ContainsAll(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !table.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (table *BTreeMap[K, V]) ContainsAny(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if table.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+func (table *BTreeMap[K, V]) Clone() tau.Collection[K] {
+	return &BTreeMap[K, V]{table.tree.Clone().(*tree.BTree[btEntry[K, V]])}
+}
+
+// --- Methods from Map[K, V] ---
+func (table *BTreeMap[K, V]) Put(key K, value V) {
+	table.tree.Insert(btEntry[K, V]{key, value})
+}
+
+func (table *BTreeMap[K, V]) Get(key K) (*V, error) {
+	entry, err := table.tree.Get(btEntry[K, V]{key: key})
+	if err != nil {
+		return nil, errs.NotFound(key)
+	}
+	return &entry.value, nil
+}
+
+func (table *BTreeMap[K, V]) Remove(key K) (*V, error) {
+	if table.Empty() {
+		return nil, errs.Empty()
+	}
+	entry, err := table.tree.Get(btEntry[K, V]{key: key})
+	if err != nil {
+		return nil, errs.NotFound(key)
+	}
+	value := entry.value
+	table.tree.Remove(*entry)
+	return &value, nil
+}
+
+func (table *BTreeMap[K, V]) HasKey(key K) bool {
+	return table.Contains(key)
+}
+
+// Iterates over the keys in ascending order
+func (table *BTreeMap[K, V]) Keys() tau.Iterator[K] {
+	return &btKeyIter[K, V]{table.tree.InOrder()}
+}
+
+// Iterates over the values in ascending order of their keys
+func (table *BTreeMap[K, V]) Values() tau.Iterator[V] {
+	return &btValueIter[K, V]{table.tree.InOrder()}
+}
+
+// --- Sorted access ---
+
+// Iterates, in ascending order, over the keys in the range [from, to)
+func (table *BTreeMap[K, V]) Range(from, to K) tau.Iterator[K] {
+	return &btKeyIter[K, V]{table.tree.Range(btEntry[K, V]{key: from}, btEntry[K, V]{key: to})}
+}
+
+// Iterates, in ascending order of their keys, over the values whose key is in the range [from, to)
+func (table *BTreeMap[K, V]) RangeValues(from, to K) tau.Iterator[V] {
+	return &btValueIter[K, V]{table.tree.Range(btEntry[K, V]{key: from}, btEntry[K, V]{key: to})}
+}
+
+// Returns the smallest key
+// Returns an error if the map is empty
+func (table *BTreeMap[K, V]) Min() (*K, error) {
+	entry, err := table.tree.Min()
+	if err != nil {
+		return nil, err
+	}
+	return &entry.key, nil
+}
+
+// Returns the greatest key
+// Returns an error if the map is empty
+func (table *BTreeMap[K, V]) Max() (*K, error) {
+	entry, err := table.tree.Max()
+	if err != nil {
+		return nil, err
+	}
+	return &entry.key, nil
+}
+
+// --- Iterators ---
+type btKeyIter[K any, V any] struct {
+	inner tau.Iterator[btEntry[K, V]]
+}
+
+func (iter *btKeyIter[K, V]) Next() (*K, bool) {
+	next, ok := iter.inner.Next()
+	if !ok {
+		return nil, false
+	}
+	return &next.key, true
+}
+
+func (iter *btKeyIter[K, V]) Each(f func(K)) {
+	iter.inner.Each(func(entry btEntry[K, V]) {
+		f(entry.key)
+	})
+}
+
+type btValueIter[K any, V any] struct {
+	inner tau.Iterator[btEntry[K, V]]
+}
+
+func (iter *btValueIter[K, V]) Next() (*V, bool) {
+	next, ok := iter.inner.Next()
+	if !ok {
+		return nil, false
+	}
+	return &next.value, true
+}
+
+func (iter *btValueIter[K, V]) Each(f func(V)) {
+	iter.inner.Each(func(entry btEntry[K, V]) {
+		f(entry.value)
+	})
+}
+
+// --- Entry ---
+type btEntry[K any, V any] struct {
+	key   K
+	value V
+}
+
+func (entry btEntry[K, V]) Cmp(other any) int {
+	return tau.Cmp(entry.key, other.(btEntry[K, V]).key)
+}
+
+func (entry btEntry[K, V]) String() string {
+	return fmt.Sprintf("(%v: %v)", entry.key, entry.value)
+}
@@ -0,0 +1,369 @@
+package table
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Sorted map implemented with a lock-free skip list
+//
+// Put, Get and Remove can be called concurrently without locks: removed
+// nodes are first marked, then unlinked by whichever operation meets
+// them, as in the skip list by Herlihy and Shavit.
+//
+// Iterators are weakly consistent: they never fail nor return a key twice,
+// and they reflect some, but not necessarily all, of the changes made
+// after their creation. For the same reason, Size is exact only when
+// there are no concurrent updates.
+type ConcSkipMap[K any, V any] struct {
+	head atomic.Pointer[cskNode[K, V]]
+	size atomic.Int64
+	// the random source is not safe for concurrent use
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+// Creates a new concurrent map implemented with a skip list, with a random seed
+func ConcSkip[K any, V any]() *ConcSkipMap[K, V] {
+	return ConcSkipSeeded[K, V](rand.Uint64())
+}
+
+// Creates a new concurrent map implemented with a skip list, whose levels
+// are drawn from a random source with the given seed
+//
+// With concurrent insertions, the levels depend on the scheduling too
+func ConcSkipSeeded[K any, V any](seed uint64) *ConcSkipMap[K, V] {
+	table := &ConcSkipMap[K, V]{rand: skipRand(seed)}
+	table.head.Store(newCskNode[K, V](*new(K), nil, skipMaxLevel))
+	return table
+}
+
+// --- Methods from Collection[K] ---
+func (table *ConcSkipMap[K, V]) String() string {
+	s := "ConcSkipMap["
+	first := true
+	iter := newCskIter(table)
+	for node := iter.next(); node != nil; node = iter.next() {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += fmt.Sprintf("(%v: %v)", node.key, *node.value.Load())
+	}
+	s += "]"
+	return s
+}
+
+func (table *ConcSkipMap[K, V]) Cmp(other any) int {
+	return tau.MapCmp[K, V](table, other)
+}
+
+func (table *ConcSkipMap[K, V]) Iter() tau.Iterator[K] {
+	return table.Keys()
+}
+
+func (table *ConcSkipMap[K, V]) Size() int {
+	return int(table.size.Load())
+}
+
+func (table *ConcSkipMap[K, V]) Empty() bool {
+	return table.Size() == 0
+}
+
+// Replaces the whole list with an empty one
+// Updates running concurrently may be lost
+func (table *ConcSkipMap[K, V]) Clear() {
+	table.head.Store(newCskNode[K, V](*new(K), nil, skipMaxLevel))
+	table.size.Store(0)
+}
+
+func (table *ConcSkipMap[K, V]) Contains(key K) bool {
+	return table.get(key) != nil
+}
+
+func (table *ConcSkipMap[K, V]) ContainsAll(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !table.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (table *ConcSkipMap[K, V]) ContainsAny(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if table.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+func (table *ConcSkipMap[K, V]) Clone() tau.Collection[K] {
+	table.lock.Lock()
+	seed := table.rand.Uint64()
+	table.lock.Unlock()
+	clone := ConcSkipSeeded[K, V](seed)
+	iter := newCskIter(table)
+	for node := iter.next(); node != nil; node = iter.next() {
+		clone.Put(node.key, *node.value.Load())
+	}
+	return clone
+}
+
+// --- Methods from Map[K, V] ---
+func (table *ConcSkipMap[K, V]) Put(key K, value V) {
+	var preds, succs [skipMaxLevel]*cskNode[K, V]
+	top := table.randomLevel()
+	for {
+		if table.find(key, &preds, &succs) {
+			succs[0].value.Store(&value)
+			return
+		}
+		node := newCskNode(key, &value, top)
+		for level := 0; level < top; level++ {
+			node.next[level].Store(&cskRef[K, V]{succs[level], false})
+		}
+		if !preds[0].next[0].cas(succs[0], false, node, false) {
+			continue
+		}
+		table.size.Add(1)
+		// the node is in the map, now it's linked at the upper levels
+		for level := 1; level < top; level++ {
+			for {
+				ref := node.next[level].Load()
+				if ref.marked {
+					// concurrently removed, stop linking
+					return
+				}
+				if ref.node != succs[level] && !node.next[level].cas(ref.node, false, succs[level], false) {
+					continue
+				}
+				if preds[level].next[level].cas(succs[level], false, node, false) {
+					break
+				}
+				table.find(key, &preds, &succs)
+			}
+		}
+		return
+	}
+}
+
+func (table *ConcSkipMap[K, V]) Get(key K) (*V, error) {
+	node := table.get(key)
+	if node == nil {
+		return nil, errs.NotFound(key)
+	}
+	value := *node.value.Load()
+	return &value, nil
+}
+
+func (table *ConcSkipMap[K, V]) Remove(key K) (*V, error) {
+	var preds, succs [skipMaxLevel]*cskNode[K, V]
+	if !table.find(key, &preds, &succs) {
+		return nil, errs.NotFound(key)
+	}
+	victim := succs[0]
+	for level := len(victim.next) - 1; level >= 1; level-- {
+		for ref := victim.next[level].Load(); !ref.marked; ref = victim.next[level].Load() {
+			victim.next[level].cas(ref.node, false, ref.node, true)
+		}
+	}
+	for {
+		ref := victim.next[0].Load()
+		if ref.marked {
+			// removed by someone else
+			return nil, errs.NotFound(key)
+		}
+		if victim.next[0].cas(ref.node, false, ref.node, true) {
+			table.size.Add(-1)
+			// unlinks the marked node
+			table.find(key, &preds, &succs)
+			value := *victim.value.Load()
+			return &value, nil
+		}
+	}
+}
+
+func (table *ConcSkipMap[K, V]) HasKey(key K) bool {
+	return table.Contains(key)
+}
+
+// Iterates over the keys in ascending order
+func (table *ConcSkipMap[K, V]) Keys() tau.Iterator[K] {
+	return &cskKeyIter[K, V]{newCskIter(table)}
+}
+
+// Iterates over the values in ascending order of their keys
+func (table *ConcSkipMap[K, V]) Values() tau.Iterator[V] {
+	return &cskValueIter[K, V]{newCskIter(table)}
+}
+
+// --- Private methods ---
+
+// fills preds and succs with the nodes around the given key at every level,
+// unlinking the marked nodes met on the way
+// Returns true if a node with the given key is found
+func (table *ConcSkipMap[K, V]) find(key K, preds, succs *[skipMaxLevel]*cskNode[K, V]) bool {
+retry:
+	for {
+		pred := table.head.Load()
+		var curr *cskNode[K, V]
+		for level := skipMaxLevel - 1; level >= 0; level-- {
+			curr = pred.next[level].Load().node
+			for curr != nil {
+				ref := curr.next[level].Load()
+				for ref.marked {
+					if !pred.next[level].cas(curr, false, ref.node, false) {
+						continue retry
+					}
+					curr = ref.node
+					if curr == nil {
+						break
+					}
+					ref = curr.next[level].Load()
+				}
+				if curr == nil || tau.Cmp(curr.key, key) >= 0 {
+					break
+				}
+				pred = curr
+				curr = ref.node
+			}
+			preds[level] = pred
+			succs[level] = curr
+		}
+		return curr != nil && tau.Cmp(curr.key, key) == 0
+	}
+}
+
+// wait-free lookup, skipping the marked nodes without unlinking them
+func (table *ConcSkipMap[K, V]) get(key K) *cskNode[K, V] {
+	pred := table.head.Load()
+	var curr *cskNode[K, V]
+	for level := skipMaxLevel - 1; level >= 0; level-- {
+		curr = pred.next[level].Load().node
+		for curr != nil {
+			ref := curr.next[level].Load()
+			for ref.marked && ref.node != nil {
+				curr = ref.node
+				ref = curr.next[level].Load()
+			}
+			if ref.marked || tau.Cmp(curr.key, key) >= 0 {
+				break
+			}
+			pred = curr
+			curr = ref.node
+		}
+	}
+	if curr == nil || tau.Cmp(curr.key, key) != 0 || curr.next[0].Load().marked {
+		return nil
+	}
+	return curr
+}
+
+func (table *ConcSkipMap[K, V]) randomLevel() int {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	return skipLevel(table.rand)
+}
+
+// --- Node ---
+
+// immutable pair of a successor and the mark of the node holding it
+type cskRef[K any, V any] struct {
+	node   *cskNode[K, V]
+	marked bool
+}
+
+// atomically replaceable reference, a new cskRef is made at every change
+type cskLink[K any, V any] struct {
+	atomic.Pointer[cskRef[K, V]]
+}
+
+func (link *cskLink[K, V]) cas(oldNode *cskNode[K, V], oldMark bool, newNode *cskNode[K, V], newMark bool) bool {
+	ref := link.Load()
+	if ref.node != oldNode || ref.marked != oldMark {
+		return false
+	}
+	return link.CompareAndSwap(ref, &cskRef[K, V]{newNode, newMark})
+}
+
+type cskNode[K any, V any] struct {
+	key   K
+	value atomic.Pointer[V]
+	next  []cskLink[K, V]
+}
+
+func newCskNode[K any, V any](key K, value *V, level int) *cskNode[K, V] {
+	node := &cskNode[K, V]{key: key, next: make([]cskLink[K, V], level)}
+	node.value.Store(value)
+	for i := range node.next {
+		node.next[i].Store(&cskRef[K, V]{})
+	}
+	return node
+}
+
+// --- Iterators ---
+
+// walks the bottom level, skipping the marked nodes
+type cskIter[K any, V any] struct {
+	node *cskNode[K, V]
+}
+
+func newCskIter[K any, V any](table *ConcSkipMap[K, V]) *cskIter[K, V] {
+	return &cskIter[K, V]{table.head.Load()}
+}
+
+func (iter *cskIter[K, V]) next() *cskNode[K, V] {
+	for iter.node != nil {
+		iter.node = iter.node.next[0].Load().node
+		if iter.node != nil && !iter.node.next[0].Load().marked {
+			return iter.node
+		}
+	}
+	return nil
+}
+
+type cskKeyIter[K any, V any] struct {
+	inner *cskIter[K, V]
+}
+
+func (iter *cskKeyIter[K, V]) Next() (*K, bool) {
+	node := iter.inner.next()
+	if node == nil {
+		return nil, false
+	}
+	return &node.key, true
+}
+
+func (iter *cskKeyIter[K, V]) Each(f func(K)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+type cskValueIter[K any, V any] struct {
+	inner *cskIter[K, V]
+}
+
+func (iter *cskValueIter[K, V]) Next() (*V, bool) {
+	node := iter.inner.next()
+	if node == nil {
+		return nil, false
+	}
+	value := *node.value.Load()
+	return &value, true
+}
+
+func (iter *cskValueIter[K, V]) Each(f func(V)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
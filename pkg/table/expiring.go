@@ -0,0 +1,360 @@
+package table
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+// Source of the current time, so that expiration can be tested
+// without waiting for real time to pass
+type Clock interface {
+	Now() time.Time
+	// Returns a channel receiving the time at every interval, used by the
+	// sweeper, and a function to stop it
+	Tick(interval time.Duration) (<-chan time.Time, func())
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Tick(interval time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// Returns the clock reading the system time
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// Unsorted map whose entries expire after a deadline
+//
+// Expired entries are invisible to all the methods. They are reclaimed
+// lazily, at the beginning of every operation, and, optionally, by a
+// background sweeper (see [Expiring.StartSweeper]). Deadlines are kept
+// in an RB tree, so reclaiming k entries costs O(k log n).
+//
+// All the methods are safe for concurrent use. Iterators work on a
+// snapshot of the map taken when they are created.
+type Expiring[K any, V any] struct {
+	entries   *HshMap[K, *expEntry[V]]
+	deadlines *tree.RBTree[expDeadline[K]]
+	ttl       time.Duration
+	clock     Clock
+	// sequence number of the last deadline, to keep them distinct
+	seq  uint64
+	lock sync.Mutex
+	// background sweeper, stop is closed to end it and done when it ended
+	sweeper sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Creates a new expiring map where entries added with [Expiring.Put]
+// live for the given time. A non-positive ttl means no expiration.
+// If clock is nil, the system clock is used.
+func Exp[K any, V any](ttl time.Duration, clock Clock) *Expiring[K, V] {
+	if clock == nil {
+		clock = SystemClock()
+	}
+	return &Expiring[K, V]{
+		entries:   Hsh[K, *expEntry[V]](),
+		deadlines: tree.RB[expDeadline[K]](),
+		ttl:       ttl,
+		clock:     clock,
+	}
+}
+
+// --- Methods from Collection[K] ---
+func (table *Expiring[K, V]) String() string {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	s := "Expiring{"
+	first := true
+	iter := newHshEntryIter(table.entries)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += fmt.Sprintf("(%v: %v)", next.key, next.value.value)
+	}
+	s += "}"
+	return s
+}
+
+func (table *Expiring[K, V]) Cmp(other any) int {
+	return tau.MapCmp[K, V](table, other)
+}
+
+func (table *Expiring[K, V]) Iter() tau.Iterator[K] {
+	return table.Keys()
+}
+
+func (table *Expiring[K, V]) Size() int {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	return table.entries.Size()
+}
+
+func (table *Expiring[K, V]) Empty() bool {
+	return table.Size() == 0
+}
+
+func (table *Expiring[K, V]) Clear() {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.entries.Clear()
+	table.deadlines.Clear()
+}
+
+func (table *Expiring[K, V]) Contains(key K) bool {
+	return table.HasKey(key)
+}
+
+func (table *Expiring[K, V]) ContainsAll(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !table.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (table *Expiring[K, V]) ContainsAny(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if table.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+// The clone keeps the deadlines of the original entries
+func (table *Expiring[K, V]) Clone() tau.Collection[K] {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	clone := Exp[K, V](table.ttl, table.clock)
+	iter := newHshEntryIter(table.entries)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		clone.put(next.key, next.value.value, next.value.deadline)
+	}
+	return clone
+}
+
+// --- Methods from Map[K, V] ---
+
+// Associates the given value with the given key, using the default ttl
+func (table *Expiring[K, V]) Put(key K, value V) {
+	table.PutWithTTL(key, value, table.ttl)
+}
+
+// Associates the given value with the given key, for the given time
+// A non-positive ttl means no expiration
+func (table *Expiring[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	var deadline time.Time
+	if ttl > 0 {
+		deadline = table.clock.Now().Add(ttl)
+	}
+	table.put(key, value, deadline)
+}
+
+func (table *Expiring[K, V]) Get(key K) (*V, error) {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	entry, err := table.entries.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	value := (*entry).value
+	return &value, nil
+}
+
+func (table *Expiring[K, V]) Remove(key K) (*V, error) {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	if table.entries.Empty() {
+		return nil, errs.Empty()
+	}
+	entry, err := table.entries.Remove(key)
+	if err != nil {
+		return nil, err
+	}
+	if !(*entry).deadline.IsZero() {
+		table.deadlines.Remove(expDeadline[K]{(*entry).deadline, (*entry).seq, key})
+	}
+	return &(*entry).value, nil
+}
+
+func (table *Expiring[K, V]) HasKey(key K) bool {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	return table.entries.HasKey(key)
+}
+
+func (table *Expiring[K, V]) Keys() tau.Iterator[K] {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	keys := list.Arr[K]()
+	table.entries.Keys().Each(func(key K) {
+		keys.Append(key)
+	})
+	return keys.Iter()
+}
+
+func (table *Expiring[K, V]) Values() tau.Iterator[V] {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	values := list.Arr[V]()
+	table.entries.Values().Each(func(entry *expEntry[V]) {
+		values.Append(entry.value)
+	})
+	return values.Iter()
+}
+
+// --- Expiration ---
+
+// Returns the remaining time to live of the given key,
+// or 0 if the key does not expire
+// Returns an error if the key is not found
+func (table *Expiring[K, V]) TTL(key K) (time.Duration, error) {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	table.reclaim()
+	entry, err := table.entries.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if (*entry).deadline.IsZero() {
+		return 0, nil
+	}
+	return (*entry).deadline.Sub(table.clock.Now()), nil
+}
+
+// Removes all the expired entries and returns how many they were
+func (table *Expiring[K, V]) Sweep() int {
+	table.lock.Lock()
+	defer table.lock.Unlock()
+	return table.reclaim()
+}
+
+// Starts a goroutine calling [Expiring.Sweep] at every tick of the clock
+// with the given interval. If a sweeper is already running, it's replaced
+func (table *Expiring[K, V]) StartSweeper(interval time.Duration) {
+	table.sweeper.Lock()
+	defer table.sweeper.Unlock()
+	table.stopSweeper()
+	tick, stopTick := table.clock.Tick(interval)
+	stop, done := make(chan struct{}), make(chan struct{})
+	table.stop, table.done = stop, done
+	go func() {
+		defer close(done)
+		defer stopTick()
+		for {
+			select {
+			case <-tick:
+				table.Sweep()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stops the background sweeper, if running, and waits for it to end
+func (table *Expiring[K, V]) StopSweeper() {
+	table.sweeper.Lock()
+	defer table.sweeper.Unlock()
+	table.stopSweeper()
+}
+
+// --- Private methods ---
+
+// the sweeper lock must be held
+func (table *Expiring[K, V]) stopSweeper() {
+	if table.stop != nil {
+		close(table.stop)
+		<-table.done
+		table.stop, table.done = nil, nil
+	}
+}
+
+// removes the expired entries, the lock must be held
+func (table *Expiring[K, V]) reclaim() int {
+	now := table.clock.Now()
+	count := 0
+	for min := table.deadlines.Min(); !tau.Nil(min); min = table.deadlines.Min() {
+		first := min.Value()
+		if first.deadline.After(now) {
+			break
+		}
+		table.deadlines.Remove(first)
+		table.entries.Remove(first.key)
+		count++
+	}
+	return count
+}
+
+// the lock must be held
+func (table *Expiring[K, V]) put(key K, value V, deadline time.Time) {
+	if old, err := table.entries.Get(key); err == nil && !(*old).deadline.IsZero() {
+		table.deadlines.Remove(expDeadline[K]{(*old).deadline, (*old).seq, key})
+	}
+	table.seq++
+	table.entries.Put(key, &expEntry[V]{value, deadline, table.seq})
+	if !deadline.IsZero() {
+		table.deadlines.Insert(expDeadline[K]{deadline, table.seq, key})
+	}
+}
+
+// --- Entry ---
+type expEntry[V any] struct {
+	value    V
+	deadline time.Time
+	seq      uint64
+}
+
+func (entry *expEntry[V]) String() string {
+	return fmt.Sprintf("%v", entry.value)
+}
+
+// key of the deadlines tree: ordered by time, then by insertion
+type expDeadline[K any] struct {
+	deadline time.Time
+	seq      uint64
+	key      K
+}
+
+func (d expDeadline[K]) Cmp(other any) int {
+	oth := other.(expDeadline[K])
+	if cmp := d.deadline.Compare(oth.deadline); cmp != 0 {
+		return cmp
+	}
+	return tau.Cmp(d.seq, oth.seq)
+}
+
+func (d expDeadline[K]) String() string {
+	return fmt.Sprintf("(%v: %v)", d.key, d.deadline)
+}
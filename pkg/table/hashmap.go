@@ -2,29 +2,41 @@ package table
 
 import (
 	"fmt"
+	"math/rand/v2"
 
+	"github.com/luverolla/lexgo/pkg/algo"
 	"github.com/luverolla/lexgo/pkg/errs"
 	"github.com/luverolla/lexgo/pkg/tau"
 )
 
 // Unsorted map implemented with a hash table
+//
+// Collisions are resolved with open addressing and linear probing.
+// Removed slots are marked as deleted and reclaimed at the next growth.
 type HshMap[K any, V any] struct {
 	inner []hshEntry[K, V]
 	size  int
+	// number of occupied slots, deleted ones included
+	used int
 }
 
 // --- Constructor ---
 func Hsh[K any, V any]() *HshMap[K, V] {
-	return &HshMap[K, V]{make([]hshEntry[K, V], 0), 0}
+	return &HshMap[K, V]{make([]hshEntry[K, V], 0), 0, 0}
 }
 
 // --- Methods from Collection[MapEntry[K, V]] ---
 func (table *HshMap[K, V]) String() string {
 	s := "HshMap{"
-	for index, value := range table.inner {
-		if index != 0 {
+	first := true
+	for _, value := range table.inner {
+		if !value.used || value.deleted {
+			continue
+		}
+		if !first {
 			s += ","
 		}
+		first = false
 		s += fmt.Sprintf("%v", value)
 	}
 	s += "}"
@@ -32,20 +44,7 @@ func (table *HshMap[K, V]) String() string {
 }
 
 func (table *HshMap[K, V]) Cmp(other any) int {
-	otherTable, ok := other.(*HshMap[K, V])
-	if !ok {
-		panic(fmt.Sprintf("ERROR: [HshMap.Cmp] %v is not a *HshMap", other))
-	}
-	if table.size != otherTable.size {
-		return table.size - otherTable.size
-	}
-	for index, value := range table.inner {
-		cmp := tau.Cmp(value, otherTable.inner[index])
-		if cmp != 0 {
-			return cmp
-		}
-	}
-	return 0
+	return tau.MapCmp[K, V](table, other)
 }
 
 func (table *HshMap[K, V]) Iter() tau.Iterator[K] {
@@ -63,15 +62,11 @@ func (table *HshMap[K, V]) Empty() bool {
 func (table *HshMap[K, V]) Clear() {
 	table.inner = make([]hshEntry[K, V], 0)
 	table.size = 0
+	table.used = 0
 }
 
 func (table *HshMap[K, V]) Contains(val K) bool {
-	for _, hshEntry := range table.inner {
-		if tau.Eq(hshEntry.key, val) {
-			return true
-		}
-	}
-	return false
+	return table.indexOf(val) != -1
 }
 
 func (table *HshMap[K, V]) ContainsAll(other tau.Collection[K]) bool {
@@ -96,10 +91,9 @@ func (table *HshMap[K, V]) ContainsAny(other tau.Collection[K]) bool {
 
 func (table *HshMap[K, V]) Clone() tau.Collection[K] {
 	clone := Hsh[K, V]()
-	iter := table.Iter()
+	iter := newHshEntryIter(table)
 	for next, hasNext := iter.Next(); hasNext; next, hasNext = iter.Next() {
-		value, _ := table.Get(*next)
-		clone.Put(*next, *value)
+		clone.Put(next.key, next.value)
 	}
 	return clone
 }
@@ -116,7 +110,12 @@ func (table *HshMap[K, V]) Get(key K) (*V, error) {
 func (table *HshMap[K, V]) Put(key K, value V) {
 	index := table.indexOf(key)
 	if index == -1 {
-		table.inner = append(table.inner, hshEntry[K, V]{key, value})
+		table.grow()
+		index = table.freeSlot(key)
+		if !table.inner[index].used {
+			table.used++
+		}
+		table.inner[index] = hshEntry[K, V]{key, value, true, false}
 		table.size++
 	} else {
 		table.inner[index].value = value
@@ -133,7 +132,10 @@ func (table *HshMap[K, V]) Remove(key K) (*V, error) {
 		return nil, errs.NotFound(key)
 	}
 	value := table.inner[index].value
-	table.inner = append(table.inner[:index], table.inner[index+1:]...)
+	var zero hshEntry[K, V]
+	table.inner[index] = zero
+	table.inner[index].used = true
+	table.inner[index].deleted = true
 	table.size--
 	return &value, nil
 }
@@ -146,83 +148,163 @@ func (table *HshMap[K, V]) Values() tau.Iterator[V] {
 	return newHshValueIter[K](table)
 }
 
+// Returns a key chosen at random, every one with the same probability
+// The global random source is used if r is nil
+// It takes O(1) expected time, unless most of the slots are free
+// Returns an error if the map is empty
+func (table *HshMap[K, V]) RandomElement(r *rand.Rand) (*K, error) {
+	if table.size == 0 {
+		return nil, errs.Empty()
+	}
+	// picks random slots until a full one is found
+	for try := 0; try < randomSlotTries; try++ {
+		entry := &table.inner[algo.RandIntN(r, len(table.inner))]
+		if entry.used && !entry.deleted {
+			return &entry.key, nil
+		}
+	}
+	// too sparse, falls back to a linear scan
+	k := algo.RandIntN(r, table.size)
+	iter := newHshEntryIter(table)
+	for entry, ok := iter.Next(); ok; entry, ok = iter.Next() {
+		if k == 0 {
+			return &entry.key, nil
+		}
+		k--
+	}
+	// unreachable, as size is the number of full slots
+	return nil, errs.Empty()
+}
+
 // --- Iterators ---
-type hshKeyIter[K any, V any] struct {
+type hshEntryIter[K any, V any] struct {
 	table *HshMap[K, V]
 	index int
 }
 
+func newHshEntryIter[K any, V any](table *HshMap[K, V]) *hshEntryIter[K, V] {
+	return &hshEntryIter[K, V]{table, 0}
+}
+
+func (iter *hshEntryIter[K, V]) Next() (*hshEntry[K, V], bool) {
+	for iter.index < len(iter.table.inner) {
+		entry := &iter.table.inner[iter.index]
+		iter.index++
+		if entry.used && !entry.deleted {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+func (iter *hshEntryIter[K, V]) Each(f func(hshEntry[K, V])) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+type hshKeyIter[K any, V any] struct {
+	inner *hshEntryIter[K, V]
+}
+
 func newHshKeyIter[K any, V any](table *HshMap[K, V]) *hshKeyIter[K, V] {
-	return &hshKeyIter[K, V]{table, 0}
+	return &hshKeyIter[K, V]{newHshEntryIter(table)}
 }
 
 func (iter *hshKeyIter[K, V]) Next() (*K, bool) {
-	if iter.index >= iter.table.size {
+	hshEntry, ok := iter.inner.Next()
+	if !ok {
 		return nil, false
 	}
-	hshEntry := iter.table.inner[iter.index]
-	iter.index++
 	return &hshEntry.key, true
 }
 
 func (iter *hshKeyIter[K, V]) Each(f func(K)) {
-	for _, value := range iter.table.inner {
-		f(value.key)
-	}
+	iter.inner.Each(func(entry hshEntry[K, V]) {
+		f(entry.key)
+	})
 }
 
 type hshValueIter[K any, V any] struct {
-	table *HshMap[K, V]
-	index int
+	inner *hshEntryIter[K, V]
 }
 
 func newHshValueIter[K any, V any](table *HshMap[K, V]) *hshValueIter[K, V] {
-	return &hshValueIter[K, V]{table, 0}
+	return &hshValueIter[K, V]{newHshEntryIter(table)}
 }
 
 func (iter *hshValueIter[K, V]) Next() (*V, bool) {
-	if iter.index >= iter.table.size {
+	hshEntry, ok := iter.inner.Next()
+	if !ok {
 		return nil, false
 	}
-	hshEntry := iter.table.inner[iter.index]
-	iter.index++
 	return &hshEntry.value, true
 }
 
 func (iter *hshValueIter[K, V]) Each(f func(V)) {
-	for _, value := range iter.table.inner {
-		f(value.value)
-	}
+	iter.inner.Each(func(entry hshEntry[K, V]) {
+		f(entry.value)
+	})
 }
 
 // --- Private methods ---
+
+// number of random slots tried by RandomElement before scanning the table
+const randomSlotTries = 32
+
 func (table *HshMap[K, V]) indexOf(key K) int {
-	for i := 0; i < table.size; i++ {
-		index := table.hash(key, i) % uint(table.size)
-		if tau.Eq(table.inner[index].key, key) {
+	if len(table.inner) == 0 {
+		return -1
+	}
+	index := table.hash(key)
+	for table.inner[index].used {
+		if !table.inner[index].deleted && tau.Eq(table.inner[index].key, key) {
 			return int(index)
 		}
+		index = (index + 1) % uint(len(table.inner))
 	}
 	return -1
 }
 
-func (table *HshMap[K, V]) hash1(key K) uint {
-	return uint(tau.Hash(key) % uint32(table.size))
+// slot for a new key, assuming it's not present and there is room
+func (table *HshMap[K, V]) freeSlot(key K) int {
+	index := table.hash(key)
+	for table.inner[index].used && !table.inner[index].deleted {
+		index = (index + 1) % uint(len(table.inner))
+	}
+	return int(index)
 }
 
-func (table *HshMap[K, V]) hash2(key K) uint {
-	PRIME := uint32(7)
-	return uint(PRIME - (tau.Hash(key) % PRIME))
+func (table *HshMap[K, V]) hash(key K) uint {
+	return uint(tau.Hash(key)) % uint(len(table.inner))
 }
 
-func (table *HshMap[K, V]) hash(key K, i int) uint {
-	return table.hash1(key) + uint(i)*table.hash2(key)
+// grows (or rebuilds) the slots array when the load factor,
+// deleted slots included, would get over 3/4
+func (table *HshMap[K, V]) grow() {
+	if 4*(table.used+1) <= 3*len(table.inner) {
+		return
+	}
+	capacity := 8
+	for 4*(table.size+1) > 3*capacity/2 {
+		capacity *= 2
+	}
+	old := table.inner
+	table.inner = make([]hshEntry[K, V], capacity)
+	table.used = table.size
+	for _, entry := range old {
+		if entry.used && !entry.deleted {
+			table.inner[table.freeSlot(entry.key)] = entry
+		}
+	}
 }
 
 // --- Entry ---
 type hshEntry[K any, V any] struct {
-	key   K
-	value V
+	key     K
+	value   V
+	used    bool
+	deleted bool
 }
 
 func (entry hshEntry[K, V]) String() string {
@@ -3,7 +3,7 @@ package table
 
 import (
 	"fmt"
-	"log"
+	"math/rand/v2"
 	"reflect"
 
 	"github.com/luverolla/lexgo/pkg/errs"
@@ -36,26 +36,7 @@ func (table *RBMap[K, V]) String() string {
 }
 
 func (table *RBMap[K, V]) Cmp(other any) int {
-	rbOther, ok := other.(*RBMap[K, V])
-	if !ok {
-		panic(fmt.Sprintf("ERROR: [RBMap.Cmp] %v is not a *RBMap", other))
-	}
-
-	if table.Size() != rbOther.Size() {
-		return table.Size() - rbOther.Size()
-	}
-
-	iter := table.Iter()
-	otherIter := rbOther.Iter()
-	for next, hasNext := iter.Next(); hasNext; next, hasNext = iter.Next() {
-		otherNext, _ := otherIter.Next()
-		cmp := tau.Cmp(*next, *otherNext)
-		if cmp != 0 {
-			return cmp
-		}
-	}
-
-	return 0
+	return tau.MapCmp[K, V](table, other)
 }
 
 func (table *RBMap[K, V]) Iter() tau.Iterator[K] {
@@ -157,6 +138,17 @@ func (table *RBMap[K, V]) Values() tau.Iterator[V] {
 	return newRBValueIter[K](table)
 }
 
+// Returns a key chosen at random, every one with the same probability
+// The global random source is used if r is nil. It takes O(log n) time
+// Returns an error if the map is empty
+func (table *RBMap[K, V]) RandomElement(r *rand.Rand) (*K, error) {
+	entry, err := table.tree.RandomElement(r)
+	if err != nil {
+		return nil, err
+	}
+	return &entry.key, nil
+}
+
 // --- Iterator ---
 type rbKeyIter[K any, V any] struct {
 	inner tau.Iterator[rbEntry[K, V]]
@@ -211,7 +203,7 @@ type rbEntry[K any, V any] struct {
 func (entry rbEntry[K, V]) Cmp(other any) int {
 	oth, ok := other.(rbEntry[K, V])
 	if !ok {
-		log.Fatalf("ERROR: [table.RBTreeMap] right hand side of comparison is not an RB entry")
+		panic(errs.TypeMismatch("Cmp", entry, fmt.Sprintf("%T", entry), other))
 	}
 	return tau.Cmp(entry.key, oth.key)
 }
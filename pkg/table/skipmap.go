@@ -0,0 +1,323 @@
+package table
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// maximum number of levels of the skip lists
+const skipMaxLevel = 32
+
+// Sorted map implemented with a skip list
+//
+// Keys are kept sorted as in [RBMap], and all the operations run in
+// O(log n) expected time. The level of every new node is drawn from a
+// geometric distribution with p = 1/4, using a seedable random source.
+type SkipMap[K any, V any] struct {
+	head  *skipNode[K, V]
+	level int
+	size  int
+	rand  *rand.Rand
+}
+
+// Creates a new map implemented with a skip list, with a random seed
+func Skip[K any, V any]() *SkipMap[K, V] {
+	return SkipSeeded[K, V](rand.Uint64())
+}
+
+// Creates a new map implemented with a skip list, whose levels
+// are drawn from a random source with the given seed
+func SkipSeeded[K any, V any](seed uint64) *SkipMap[K, V] {
+	return &SkipMap[K, V]{newSkipNode[K, V](*new(K), *new(V), skipMaxLevel), 1, 0, skipRand(seed)}
+}
+
+// --- Methods from Collection[K] ---
+func (table *SkipMap[K, V]) String() string {
+	s := "SkipMap["
+	for node := table.head.next[0]; node != nil; node = node.next[0] {
+		s += fmt.Sprintf("(%v: %v)", node.key, node.value)
+		if node.next[0] != nil {
+			s += ","
+		}
+	}
+	s += "]"
+	return s
+}
+
+func (table *SkipMap[K, V]) Cmp(other any) int {
+	return tau.MapCmp[K, V](table, other)
+}
+
+func (table *SkipMap[K, V]) Iter() tau.Iterator[K] {
+	return table.Keys()
+}
+
+func (table *SkipMap[K, V]) Size() int {
+	return table.size
+}
+
+func (table *SkipMap[K, V]) Empty() bool {
+	return table.size == 0
+}
+
+func (table *SkipMap[K, V]) Clear() {
+	for i := range table.head.next {
+		table.head.next[i] = nil
+	}
+	table.level = 1
+	table.size = 0
+}
+
+func (table *SkipMap[K, V]) Contains(key K) bool {
+	return table.find(key) != nil
+}
+
+func (table *SkipMap[K, V]) ContainsAll(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !table.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (table *SkipMap[K, V]) ContainsAny(other tau.Collection[K]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if table.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+// The clone draws its levels from a source seeded by the original one
+func (table *SkipMap[K, V]) Clone() tau.Collection[K] {
+	clone := SkipSeeded[K, V](table.rand.Uint64())
+	for node := table.head.next[0]; node != nil; node = node.next[0] {
+		clone.Put(node.key, node.value)
+	}
+	return clone
+}
+
+// --- Methods from Map[K, V] ---
+func (table *SkipMap[K, V]) Put(key K, value V) {
+	var update [skipMaxLevel]*skipNode[K, V]
+	node := table.head
+	for level := table.level - 1; level >= 0; level-- {
+		for node.next[level] != nil && tau.Cmp(node.next[level].key, key) < 0 {
+			node = node.next[level]
+		}
+		update[level] = node
+	}
+	if next := node.next[0]; next != nil && tau.Cmp(next.key, key) == 0 {
+		next.value = value
+		return
+	}
+
+	level := table.randomLevel()
+	if level > table.level {
+		for i := table.level; i < level; i++ {
+			update[i] = table.head
+		}
+		table.level = level
+	}
+	newNode := newSkipNode(key, value, level)
+	for i := 0; i < level; i++ {
+		newNode.next[i] = update[i].next[i]
+		update[i].next[i] = newNode
+	}
+	table.size++
+}
+
+func (table *SkipMap[K, V]) Get(key K) (*V, error) {
+	node := table.find(key)
+	if node == nil {
+		return nil, errs.NotFound(key)
+	}
+	return &node.value, nil
+}
+
+func (table *SkipMap[K, V]) Remove(key K) (*V, error) {
+	if table.Empty() {
+		return nil, errs.Empty()
+	}
+	var update [skipMaxLevel]*skipNode[K, V]
+	node := table.head
+	for level := table.level - 1; level >= 0; level-- {
+		for node.next[level] != nil && tau.Cmp(node.next[level].key, key) < 0 {
+			node = node.next[level]
+		}
+		update[level] = node
+	}
+	target := node.next[0]
+	if target == nil || tau.Cmp(target.key, key) != 0 {
+		return nil, errs.NotFound(key)
+	}
+	for i := 0; i < len(target.next); i++ {
+		update[i].next[i] = target.next[i]
+	}
+	for table.level > 1 && table.head.next[table.level-1] == nil {
+		table.level--
+	}
+	table.size--
+	return &target.value, nil
+}
+
+func (table *SkipMap[K, V]) HasKey(key K) bool {
+	return table.Contains(key)
+}
+
+// Iterates over the keys in ascending order
+func (table *SkipMap[K, V]) Keys() tau.Iterator[K] {
+	return &skipKeyIter[K, V]{table.head.next[0]}
+}
+
+// Iterates over the values in ascending order of their keys
+func (table *SkipMap[K, V]) Values() tau.Iterator[V] {
+	return &skipValueIter[K, V]{table.head.next[0]}
+}
+
+// --- Sorted access ---
+
+// Returns the smallest key
+// Returns an error if the map is empty
+func (table *SkipMap[K, V]) Min() (*K, error) {
+	if table.Empty() {
+		return nil, errs.Empty()
+	}
+	return &table.head.next[0].key, nil
+}
+
+// Returns the greatest key
+// Returns an error if the map is empty
+func (table *SkipMap[K, V]) Max() (*K, error) {
+	if table.Empty() {
+		return nil, errs.Empty()
+	}
+	node := table.head
+	for level := table.level - 1; level >= 0; level-- {
+		for node.next[level] != nil {
+			node = node.next[level]
+		}
+	}
+	return &node.key, nil
+}
+
+// Iterates, in ascending order, over the keys in the range [from, to)
+func (table *SkipMap[K, V]) Range(from, to K) tau.Iterator[K] {
+	node := table.head
+	for level := table.level - 1; level >= 0; level-- {
+		for node.next[level] != nil && tau.Cmp(node.next[level].key, from) < 0 {
+			node = node.next[level]
+		}
+	}
+	return &skipRangeIter[K, V]{node.next[0], to}
+}
+
+// --- Private methods ---
+func (table *SkipMap[K, V]) find(key K) *skipNode[K, V] {
+	node := table.head
+	for level := table.level - 1; level >= 0; level-- {
+		for node.next[level] != nil && tau.Cmp(node.next[level].key, key) < 0 {
+			node = node.next[level]
+		}
+	}
+	node = node.next[0]
+	if node == nil || tau.Cmp(node.key, key) != 0 {
+		return nil
+	}
+	return node
+}
+
+func (table *SkipMap[K, V]) randomLevel() int {
+	return skipLevel(table.rand)
+}
+
+// random source of the skip lists, a PCG generator with the given seed
+func skipRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, 0))
+}
+
+func skipLevel(source *rand.Rand) int {
+	level := 1
+	for level < skipMaxLevel && algo.RandIntN(source, 4) == 0 {
+		level++
+	}
+	return level
+}
+
+// --- Node ---
+type skipNode[K any, V any] struct {
+	key   K
+	value V
+	next  []*skipNode[K, V]
+}
+
+func newSkipNode[K any, V any](key K, value V, level int) *skipNode[K, V] {
+	return &skipNode[K, V]{key, value, make([]*skipNode[K, V], level)}
+}
+
+// --- Iterators ---
+type skipKeyIter[K any, V any] struct {
+	node *skipNode[K, V]
+}
+
+func (iter *skipKeyIter[K, V]) Next() (*K, bool) {
+	if iter.node == nil {
+		return nil, false
+	}
+	key := iter.node.key
+	iter.node = iter.node.next[0]
+	return &key, true
+}
+
+func (iter *skipKeyIter[K, V]) Each(f func(K)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+type skipValueIter[K any, V any] struct {
+	node *skipNode[K, V]
+}
+
+func (iter *skipValueIter[K, V]) Next() (*V, bool) {
+	if iter.node == nil {
+		return nil, false
+	}
+	value := iter.node.value
+	iter.node = iter.node.next[0]
+	return &value, true
+}
+
+func (iter *skipValueIter[K, V]) Each(f func(V)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+type skipRangeIter[K any, V any] struct {
+	node *skipNode[K, V]
+	to   K
+}
+
+func (iter *skipRangeIter[K, V]) Next() (*K, bool) {
+	if iter.node == nil || tau.Cmp(iter.node.key, iter.to) >= 0 {
+		return nil, false
+	}
+	key := iter.node.key
+	iter.node = iter.node.next[0]
+	return &key, true
+}
+
+func (iter *skipRangeIter[K, V]) Each(f func(K)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
@@ -5,7 +5,11 @@
 // of different types.
 package tau
 
-import "fmt"
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
 
 // Interface for object from which is possible to get a hash value.
 type Hashable interface {
@@ -33,6 +37,11 @@ type Base interface {
 	Comparable
 }
 
+// Numeric types, whose values can be added and subtracted
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
 // Generic container for a value
 type Box[T any] interface {
 	// Returns the value contained in the box
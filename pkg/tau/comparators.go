@@ -1,6 +1,11 @@
 package tau
 
-import "golang.org/x/exp/constraints"
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"golang.org/x/exp/constraints"
+)
 
 // ASCending order comparator. The smaller is the lesser
 func ASCmp[T constraints.Ordered](a, b T) int {
@@ -16,3 +21,88 @@ func ASCmp[T constraints.Ordered](a, b T) int {
 func DSCmp[T constraints.Ordered](a, b T) int {
 	return -ASCmp[T](a, b)
 }
+
+// --- Collections ---
+
+// Compares a collection with another value, following the contract of
+// [Collection]: the smaller collection is the lesser, and collections of
+// the same size are compared element by element, in iteration order
+// The other value can be a collection of any kind, with the same element type
+// It's meant to implement the Cmp method of the collections
+// Panics with an [errs.TypeMismatchErr] if the other value is not a [Collection]
+// of the same element type, and with an [errs.ConcurrentModificationErr] if
+// one of the collections changes size while they are compared
+func CollCmp[T any](coll Collection[T], other any) int {
+	return CollCmpBy(coll, other, func(a, b T) int { return Cmp(a, b) })
+}
+
+// Same as [CollCmp], but elements are compared with the given comparator
+func CollCmpBy[T any](coll Collection[T], other any, cmp Comparator[T]) int {
+	otherColl, ok := other.(Collection[T])
+	if !ok {
+		panic(errs.TypeMismatch("Cmp", coll, fmt.Sprintf("tau.Collection[%T]", *new(T)), other))
+	}
+	if coll.Size() != otherColl.Size() {
+		return ASCmp(coll.Size(), otherColl.Size())
+	}
+	iter, otherIter := coll.Iter(), otherColl.Iter()
+	for next, hasNext := iter.Next(); hasNext; next, hasNext = iter.Next() {
+		otherNext, hasOtherNext := otherIter.Next()
+		if !hasOtherNext {
+			panic(errs.ConcurrentModification("Cmp", other))
+		}
+		if c := cmp(*next, *otherNext); c != 0 {
+			return c
+		}
+	}
+	if _, hasOtherNext := otherIter.Next(); hasOtherNext {
+		panic(errs.ConcurrentModification("Cmp", coll))
+	}
+	return 0
+}
+
+// Checks if two collections are equal, following the contract of [Collection]
+// So, they must have the same elements in the same order, but they can
+// be of different kinds
+func CollEq[T any](a, b Collection[T]) bool {
+	return CollCmp(a, b) == 0
+}
+
+// Compares a map with another value, like [CollCmp] does with its keys
+// The values are not compared, as the contract of [Collection] involves
+// only the keys of a map. Use [MapEqual] or [MapEqualBy] for them
+func MapCmp[K any, V any](m Map[K, V], other any) int {
+	return CollCmp[K](m, other)
+}
+
+// Checks if two collections have the same elements, ignoring their order
+// It suits sets, whose iteration order depends on the implementation,
+// for example to compare a set.RBSet with a set.HshSet
+func SetEqual[T any](a, b Collection[T]) bool {
+	return a.Size() == b.Size() && a.ContainsAll(b) && b.ContainsAll(a)
+}
+
+// Checks if two maps have the same keys, associated with equal values,
+// ignoring their order
+// It suits maps of different kinds, for example a table.RBMap and a table.HshMap
+// Values are compared with [Eq], see [MapEqualBy] for the other types
+func MapEqual[K any, V any](a, b Map[K, V]) bool {
+	return MapEqualBy(a, b, func(x, y V) int { return Cmp(x, y) })
+}
+
+// Same as [MapEqual], but values are compared with the given comparator
+func MapEqualBy[K any, V any](a, b Map[K, V], cmp Comparator[V]) bool {
+	if a.Size() != b.Size() {
+		return false
+	}
+	equal := true
+	a.Keys().Each(func(key K) {
+		if !equal {
+			return
+		}
+		value, err := a.Get(key)
+		otherValue, otherErr := b.Get(key)
+		equal = err == nil && otherErr == nil && cmp(*value, *otherValue) == 0
+	})
+	return equal
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"reflect"
 
+	"github.com/luverolla/lexgo/pkg/errs"
 	"golang.org/x/exp/constraints"
 )
 
@@ -69,7 +70,7 @@ func Min(vals ...any) any {
 // or types that implement the [Comparable] interface
 // For the latter, the function [tau.Comparable.Cmp] must be implemented
 //
-// If the type of a and b are not comparable, it will panic
+// If the type of a and b are not comparable, it panics with an [errs.TypeMismatchErr]
 func Cmp(a, b any) int {
 	switch a.(type) {
 	case int:
@@ -125,12 +126,14 @@ func Cmp(a, b any) int {
 		b := b.(string)
 		return cmp(a, b)
 	default:
-		a, oka := a.(Comparable)
-		b, okb := b.(Comparable)
-		if !oka || !okb {
-			log.Fatalf("ERROR: [unified.Cmp] tau.%T and %T are not comparable\r\n", a, b)
+		ca, oka := a.(Comparable)
+		if !oka {
+			panic(errs.TypeMismatch("tau.Cmp", nil, "tau.Comparable", a))
 		}
-		return a.Cmp(b)
+		if _, okb := b.(Comparable); !okb {
+			panic(errs.TypeMismatch("tau.Cmp", nil, "tau.Comparable", b))
+		}
+		return ca.Cmp(b)
 	}
 }
 
@@ -139,7 +142,7 @@ func Cmp(a, b any) int {
 // It also accepts types that implement the [tau.Hashable] interface
 // For the latter, the function [tau.Hashable.Hash] must be implemented
 //
-// If the type of given value is not hashable, it will panic
+// If the type of given value is not hashable, it panics with an [errs.TypeMismatchErr]
 func Hash(v any) uint32 {
 	switch val := v.(type) {
 	case int, int8, int16, int32, int64:
@@ -148,23 +151,21 @@ func Hash(v any) uint32 {
 	case uint, uint8, uint16, uint32, uint64:
 		s := reflect.ValueOf(val).Uint()
 		return uint32(s)
-	case float32, float64:
-		return hashFloat(v.(float32))
+	case float32:
+		return hashFloat(val)
+	case float64:
+		return hashFloat(float32(val))
 	case string:
 		return hashString(val)
 	default:
 		conv, ok := val.(Hashable)
 		if !ok {
-			log.Fatal("ERROR: [unified.Hash] CANNOT HASH TYPE")
+			panic(errs.TypeMismatch("tau.Hash", nil, "tau.Hashable", v))
 		}
 		return conv.Hash()
 	}
 }
 
-// --- Private variables ---
-var hashgen = fnv.New32a()
-var buf [4]byte
-
 // --- Private functions ---
 func cmp[T constraints.Ordered](a, b T) int {
 	if a == b {
@@ -176,22 +177,18 @@ func cmp[T constraints.Ordered](a, b T) int {
 	}
 }
 
+// a new hash is used at every call, so that hashing is safe for concurrent use
 func hashFloat(v float32) uint32 {
-	n := math.Float32bits(v)
-	binary.NativeEndian.PutUint32(buf[:], n)
-	hashgen.Reset()
-	_, err := hashgen.Write(buf[:])
-	if err != nil {
-		log.Fatal("ERROR: [unified.Hash] HASHGEN.Write() FAILED")
-	}
+	var buf [4]byte
+	binary.NativeEndian.PutUint32(buf[:], math.Float32bits(v))
+	hashgen := fnv.New32a()
+	// writing to a hash never fails
+	hashgen.Write(buf[:])
 	return hashgen.Sum32()
 }
 
 func hashString(v string) uint32 {
-	hashgen.Reset()
-	_, err := hashgen.Write([]byte(v))
-	if err != nil {
-		log.Fatal("ERROR: [unified.Hash] HASHGEN.Write() FAILED")
-	}
+	hashgen := fnv.New32a()
+	hashgen.Write([]byte(v))
 	return hashgen.Sum32()
 }
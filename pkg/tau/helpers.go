@@ -53,6 +53,10 @@ type Iterable[T any] interface {
 // So, two collections are equal if of the same size and whose
 // elements are equal in the same order. The two collections have
 // not to be of the same kind (e.g. also a list and a set can be equal).
+//
+// All the implementations follow it by calling [CollCmp], or [MapCmp]
+// for maps, which compares their keys. To compare collections ignoring
+// the order, or maps with their values, see [SetEqual] and [MapEqual]
 type Collection[T any] interface {
 	fmt.Stringer
 	Comparable
@@ -91,6 +95,13 @@ type Collection[T any] interface {
 //	endif
 //	index = index % size
 //	OUTPUT(index)
+//
+// Insert has one more valid position, the end of the collection, so it
+// sanifies the index with size+1 instead of size. This way, Get returns the
+// new element at the same index afterwards
+//
+// Some implementations, like the lists of package list, have a strict mode,
+// where an index out of [-size, size) is an error instead of wrapping around
 type IdxedColl[T any] interface {
 	Collection[T]
 	// Returns the element at the given index
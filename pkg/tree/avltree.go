@@ -3,9 +3,11 @@ package tree
 
 import (
 	"fmt"
-	"log"
+	"math/rand/v2"
 
+	"github.com/luverolla/lexgo/pkg/algo"
 	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/errs"
 	"github.com/luverolla/lexgo/pkg/tau"
 )
 
@@ -37,26 +39,7 @@ func (t *AVLTree[T]) String() string {
 }
 
 func (t *AVLTree[T]) Cmp(other any) int {
-	otherTree, ok := other.(*AVLTree[T])
-	if !ok {
-		log.Fatal("ERROR: [tree.AVL] right hand side of comparison is not an AVL tree")
-	}
-	if t.size != otherTree.size {
-		return t.size - otherTree.size
-	}
-	iter := t.Iter()
-	otherIter := otherTree.Iter()
-	next, hasNext := iter.Next()
-	otherNext, hasOtherNext := otherIter.Next()
-	for hasNext && hasOtherNext {
-		cmp := tau.Cmp(*next, *otherNext)
-		if cmp != 0 {
-			return cmp
-		}
-		next, hasNext = iter.Next()
-		otherNext, hasOtherNext = otherIter.Next()
-	}
-	return 0
+	return tau.CollCmp[T](t, other)
 }
 
 func (t *AVLTree[T]) Size() int {
@@ -100,6 +83,10 @@ func (t *AVLTree[T]) Iter() tau.Iterator[T] {
 	return t.InOrder()
 }
 
+func (t *AVLTree[T]) Clone() tau.Collection[T] {
+	return &AVLTree[T]{cloneAVL(t.root), t.size}
+}
+
 // --- Methods from BSTree[T] ---
 func (t *AVLTree[T]) Get(val T) tau.BSTreeNode[T] {
 	node := t.getNode(t.root, val)
@@ -156,11 +143,47 @@ func (t *AVLTree[T]) PostOrder() tau.Iterator[T] {
 	return newAVLPostOrderIter(t)
 }
 
+// --- Order statistics ---
+
+// Returns the element with exactly k smaller elements
+// It takes O(log n) time, as every node knows the size of its subtree
+// Returns an error if k is not in [0, size)
+func (t *AVLTree[T]) Select(k int) (*T, error) {
+	if k < 0 || k >= t.count(t.root) {
+		return nil, errs.IndexOutOfRange("Select", t, k, t.count(t.root))
+	}
+	n := t.root
+	for {
+		left := t.count(n.left)
+		switch {
+		case k < left:
+			n = n.left
+		case k > left:
+			k -= left + 1
+			n = n.right
+		default:
+			return &n.val, nil
+		}
+	}
+}
+
+// Returns an element chosen at random, every one with the same probability
+// The global random source is used if r is nil. It takes O(log n) time
+// Returns an error if the tree is empty
+func (t *AVLTree[T]) RandomElement(r *rand.Rand) (*T, error) {
+	if tau.Nil(t.root) {
+		return nil, errs.Empty()
+	}
+	return t.Select(algo.RandIntN(r, t.count(t.root)))
+}
+
 // --- Node struct and methods ---
 type avlNode[T any] struct {
 	val   T
 	left  *avlNode[T]
 	right *avlNode[T]
+	// number of nodes in the subtree
+	count int
 }
 
 func (n *avlNode[T]) Value() T {
@@ -175,6 +198,13 @@ func (n *avlNode[T]) Right() tau.BSTreeNode[T] {
 	return n.right
 }
 
+func cloneAVL[T any](n *avlNode[T]) *avlNode[T] {
+	if n == nil {
+		return nil
+	}
+	return &avlNode[T]{n.val, cloneAVL(n.left), cloneAVL(n.right), n.count}
+}
+
 func (t *AVLTree[T]) getNode(n *avlNode[T], val T) *avlNode[T] {
 	if tau.Nil(n) {
 		return nil
@@ -190,7 +220,7 @@ func (t *AVLTree[T]) getNode(n *avlNode[T], val T) *avlNode[T] {
 
 func (t *AVLTree[T]) insert(n *avlNode[T], val T) *avlNode[T] {
 	if tau.Nil(n) {
-		return &avlNode[T]{val, nil, nil}
+		return &avlNode[T]{val, nil, nil, 1}
 	}
 	switch {
 	case tau.Cmp(val, n.val) < 0:
@@ -293,6 +323,8 @@ func (t *AVLTree[T]) rotateLeft(n *avlNode[T]) *avlNode[T] {
 	x := n.right
 	n.right = x.left
 	x.left = n
+	t.recount(n)
+	t.recount(x)
 	return x
 }
 
@@ -300,10 +332,24 @@ func (t *AVLTree[T]) rotateRight(n *avlNode[T]) *avlNode[T] {
 	x := n.left
 	n.left = x.right
 	x.right = n
+	t.recount(n)
+	t.recount(x)
 	return x
 }
 
+func (t *AVLTree[T]) count(n *avlNode[T]) int {
+	if tau.Nil(n) {
+		return 0
+	}
+	return n.count
+}
+
+func (t *AVLTree[T]) recount(n *avlNode[T]) {
+	n.count = 1 + t.count(n.left) + t.count(n.right)
+}
+
 func (t *AVLTree[T]) rebalance(n *avlNode[T]) *avlNode[T] {
+	t.recount(n)
 	bf := t.balanceFactor(n)
 	switch {
 	case bf < -1:
@@ -0,0 +1,518 @@
+package tree
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Implementation of an in-memory B+ tree.
+//
+// Elements are stored only in the leaves, many per node, while internal
+// nodes hold separators to route the searches. The leaves are linked to
+// each other, so ordered iteration and range scans walk them sequentially
+// without going back up the tree.
+//
+// The tree is configured by its minimum degree t: every node but the root
+// has between t and 2t children (leaves hold between t-1 and 2t-1
+// elements). All the operations run in O(t log_t n) time.
+//
+// Unlike the binary trees of this package, inserting an element equal
+// to an existing one replaces it.
+type BTree[T any] struct {
+	root   *bNode[T]
+	degree int
+	size   int
+}
+
+// Creates a new empty B+ tree with the given minimum degree
+// It panics if the degree is less than 2
+func B[T any](degree int) *BTree[T] {
+	if degree < 2 {
+		panic(fmt.Sprintf("ERROR: [tree.B] minimum degree must be at least 2, got %d", degree))
+	}
+	return &BTree[T]{&bNode[T]{leaf: true}, degree, 0}
+}
+
+// --- Methods from tau.Collection[T] ---
+func (bt *BTree[T]) String() string {
+	s := "BTree["
+	first := true
+	bt.Iter().Each(func(val T) {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += fmt.Sprintf("%v", val)
+	})
+	s += "]"
+	return s
+}
+
+func (bt *BTree[T]) Cmp(other any) int {
+	return tau.CollCmp[T](bt, other)
+}
+
+func (bt *BTree[T]) Iter() tau.Iterator[T] {
+	return bt.InOrder()
+}
+
+func (bt *BTree[T]) Size() int {
+	return bt.size
+}
+
+func (bt *BTree[T]) Empty() bool {
+	return bt.size == 0
+}
+
+func (bt *BTree[T]) Clear() {
+	bt.root = &bNode[T]{leaf: true}
+	bt.size = 0
+}
+
+func (bt *BTree[T]) Contains(val T) bool {
+	_, err := bt.Get(val)
+	return err == nil
+}
+
+func (bt *BTree[T]) ContainsAll(coll tau.Collection[T]) bool {
+	it := coll.Iter()
+	for next, ok := it.Next(); ok; next, ok = it.Next() {
+		if !bt.Contains(*next) {
+			return false
+		}
+	}
+	return true
+}
+
+func (bt *BTree[T]) ContainsAny(coll tau.Collection[T]) bool {
+	it := coll.Iter()
+	for next, ok := it.Next(); ok; next, ok = it.Next() {
+		if bt.Contains(*next) {
+			return true
+		}
+	}
+	return false
+}
+
+func (bt *BTree[T]) Clone() tau.Collection[T] {
+	clone := B[T](bt.degree)
+	bt.Iter().Each(func(val T) {
+		clone.Insert(val)
+	})
+	return clone
+}
+
+// --- Tree operations ---
+
+// Returns the minimum degree of the tree
+func (bt *BTree[T]) Degree() int {
+	return bt.degree
+}
+
+// Returns the stored element equal to the given one
+// Returns an error if there's no such element
+func (bt *BTree[T]) Get(val T) (*T, error) {
+	leaf, index := bt.lowerBound(val)
+	if leaf == nil || tau.Cmp(leaf.items[index], val) != 0 {
+		return nil, errs.NotFound(val)
+	}
+	return &leaf.items[index], nil
+}
+
+// Inserts the given element, replacing the equal one if present
+// Returns true if the element was not present
+func (bt *BTree[T]) Insert(val T) bool {
+	added, sep, right := bt.insert(bt.root, val)
+	if right != nil {
+		bt.root = &bNode[T]{keys: []T{sep}, children: []*bNode[T]{bt.root, right}}
+	}
+	if added {
+		bt.size++
+	}
+	return added
+}
+
+// Removes the element equal to the given one
+// Returns an error if there's no such element
+func (bt *BTree[T]) Remove(val T) error {
+	if bt.Empty() {
+		return errs.Empty()
+	}
+	if !bt.remove(bt.root, val) {
+		return errs.NotFound(val)
+	}
+	if !bt.root.leaf && len(bt.root.children) == 1 {
+		bt.root = bt.root.children[0]
+	}
+	bt.size--
+	return nil
+}
+
+// Returns the minimum element
+// Returns an error if the tree is empty
+func (bt *BTree[T]) Min() (*T, error) {
+	if bt.Empty() {
+		return nil, errs.Empty()
+	}
+	leaf := bt.firstLeaf()
+	return &leaf.items[0], nil
+}
+
+// Returns the maximum element
+// Returns an error if the tree is empty
+func (bt *BTree[T]) Max() (*T, error) {
+	if bt.Empty() {
+		return nil, errs.Empty()
+	}
+	node := bt.root
+	for !node.leaf {
+		node = node.children[len(node.children)-1]
+	}
+	return &node.items[len(node.items)-1], nil
+}
+
+// Returns the greatest element less than the given one
+// Returns an error if there's no such element
+func (bt *BTree[T]) Pred(val T) (*T, error) {
+	leaf, index := bt.lowerBound(val)
+	if leaf == nil {
+		leaf, index = bt.lastPosition()
+	} else {
+		leaf, index = leaf.prevPosition(index)
+	}
+	if leaf == nil {
+		return nil, errs.NotFound(val)
+	}
+	return &leaf.items[index], nil
+}
+
+// Returns the smallest element greater than the given one
+// Returns an error if there's no such element
+func (bt *BTree[T]) Succ(val T) (*T, error) {
+	leaf, index := bt.lowerBound(val)
+	if leaf != nil && tau.Cmp(leaf.items[index], val) == 0 {
+		leaf, index = leaf.nextPosition(index)
+	}
+	if leaf == nil {
+		return nil, errs.NotFound(val)
+	}
+	return &leaf.items[index], nil
+}
+
+// Returns an iterator over the elements in ascending order
+func (bt *BTree[T]) InOrder() tau.Iterator[T] {
+	return &bTreeIter[T]{bt.firstLeaf(), 0, nil}
+}
+
+// Returns an iterator, in ascending order, over the elements in the range [from, to)
+func (bt *BTree[T]) Range(from, to T) tau.Iterator[T] {
+	leaf, index := bt.lowerBound(from)
+	return &bTreeIter[T]{leaf, index, &to}
+}
+
+// Returns an iterator, in ascending order, over the elements greater than or equal to the given one
+func (bt *BTree[T]) From(from T) tau.Iterator[T] {
+	leaf, index := bt.lowerBound(from)
+	return &bTreeIter[T]{leaf, index, nil}
+}
+
+// Returns the height of the tree, counting the leaves
+func (bt *BTree[T]) Height() int {
+	height := 1
+	for node := bt.root; !node.leaf; node = node.children[0] {
+		height++
+	}
+	return height
+}
+
+// --- Private methods ---
+func (bt *BTree[T]) firstLeaf() *bNode[T] {
+	node := bt.root
+	for !node.leaf {
+		node = node.children[0]
+	}
+	if len(node.items) == 0 {
+		return nil
+	}
+	return node
+}
+
+func (bt *BTree[T]) lastPosition() (*bNode[T], int) {
+	if bt.Empty() {
+		return nil, 0
+	}
+	node := bt.root
+	for !node.leaf {
+		node = node.children[len(node.children)-1]
+	}
+	return node, len(node.items) - 1
+}
+
+// returns the position of the first element greater than or equal
+// to the given one, or a nil leaf if there's no such element
+func (bt *BTree[T]) lowerBound(val T) (*bNode[T], int) {
+	node := bt.root
+	for !node.leaf {
+		node = node.children[node.route(val)]
+	}
+	index := search(node.items, val)
+	if index == len(node.items) {
+		if node.next == nil {
+			return nil, 0
+		}
+		return node.next, 0
+	}
+	return node, index
+}
+
+// returns true if an element was added, plus the separator
+// and the new right sibling if the node was split
+func (bt *BTree[T]) insert(node *bNode[T], val T) (bool, T, *bNode[T]) {
+	var zero T
+	maxItems := 2*bt.degree - 1
+	if node.leaf {
+		index := search(node.items, val)
+		if index < len(node.items) && tau.Cmp(node.items[index], val) == 0 {
+			node.items[index] = val
+			return false, zero, nil
+		}
+		node.items = insertAt(node.items, index, val)
+		if len(node.items) <= maxItems {
+			return true, zero, nil
+		}
+		mid := len(node.items) / 2
+		right := &bNode[T]{leaf: true, items: append([]T{}, node.items[mid:]...)}
+		node.items = node.items[:mid:mid]
+		right.next = node.next
+		if right.next != nil {
+			right.next.prev = right
+		}
+		right.prev = node
+		node.next = right
+		return true, right.items[0], right
+	}
+
+	index := node.route(val)
+	added, sep, right := bt.insert(node.children[index], val)
+	if right == nil {
+		return added, zero, nil
+	}
+	node.keys = insertAt(node.keys, index, sep)
+	node.children = insertAt(node.children, index+1, right)
+	if len(node.children) <= 2*bt.degree {
+		return added, zero, nil
+	}
+	mid := len(node.keys) / 2
+	sep = node.keys[mid]
+	newNode := &bNode[T]{
+		keys:     append([]T{}, node.keys[mid+1:]...),
+		children: append([]*bNode[T]{}, node.children[mid+1:]...),
+	}
+	node.keys = node.keys[:mid:mid]
+	node.children = node.children[: mid+1 : mid+1]
+	return added, sep, newNode
+}
+
+// returns true if the element was found
+func (bt *BTree[T]) remove(node *bNode[T], val T) bool {
+	if node.leaf {
+		index := search(node.items, val)
+		if index == len(node.items) || tau.Cmp(node.items[index], val) != 0 {
+			return false
+		}
+		node.items = removeAt(node.items, index)
+		return true
+	}
+	index := node.route(val)
+	if !bt.remove(node.children[index], val) {
+		return false
+	}
+	bt.rebalance(node, index)
+	return true
+}
+
+// fixes the child at the given index if it has too few elements,
+// borrowing from a sibling or merging with it
+func (bt *BTree[T]) rebalance(parent *bNode[T], index int) {
+	child := parent.children[index]
+	if child.leaf && len(child.items) >= bt.degree-1 {
+		return
+	}
+	if !child.leaf && len(child.children) >= bt.degree {
+		return
+	}
+
+	var left, right *bNode[T]
+	if index > 0 {
+		left = parent.children[index-1]
+	}
+	if index < len(parent.children)-1 {
+		right = parent.children[index+1]
+	}
+
+	if child.leaf {
+		switch {
+		case left != nil && len(left.items) > bt.degree-1:
+			last := left.items[len(left.items)-1]
+			left.items = left.items[:len(left.items)-1]
+			child.items = insertAt(child.items, 0, last)
+			parent.keys[index-1] = child.items[0]
+		case right != nil && len(right.items) > bt.degree-1:
+			child.items = append(child.items, right.items[0])
+			right.items = removeAt(right.items, 0)
+			parent.keys[index] = right.items[0]
+		case right != nil:
+			bt.mergeLeaves(parent, index)
+		default:
+			bt.mergeLeaves(parent, index-1)
+		}
+		return
+	}
+
+	switch {
+	case left != nil && len(left.children) > bt.degree:
+		child.keys = insertAt(child.keys, 0, parent.keys[index-1])
+		child.children = insertAt(child.children, 0, left.children[len(left.children)-1])
+		parent.keys[index-1] = left.keys[len(left.keys)-1]
+		left.keys = left.keys[:len(left.keys)-1]
+		left.children = left.children[:len(left.children)-1]
+	case right != nil && len(right.children) > bt.degree:
+		child.keys = append(child.keys, parent.keys[index])
+		child.children = append(child.children, right.children[0])
+		parent.keys[index] = right.keys[0]
+		right.keys = removeAt(right.keys, 0)
+		right.children = removeAt(right.children, 0)
+	case right != nil:
+		bt.mergeInternals(parent, index)
+	default:
+		bt.mergeInternals(parent, index-1)
+	}
+}
+
+// merges the leaf at the given index with its right sibling
+func (bt *BTree[T]) mergeLeaves(parent *bNode[T], index int) {
+	left, right := parent.children[index], parent.children[index+1]
+	left.items = append(left.items, right.items...)
+	left.next = right.next
+	if left.next != nil {
+		left.next.prev = left
+	}
+	parent.keys = removeAt(parent.keys, index)
+	parent.children = removeAt(parent.children, index+1)
+}
+
+// merges the internal node at the given index with its right sibling
+func (bt *BTree[T]) mergeInternals(parent *bNode[T], index int) {
+	left, right := parent.children[index], parent.children[index+1]
+	left.keys = append(left.keys, parent.keys[index])
+	left.keys = append(left.keys, right.keys...)
+	left.children = append(left.children, right.children...)
+	parent.keys = removeAt(parent.keys, index)
+	parent.children = removeAt(parent.children, index+1)
+}
+
+// --- Private types ---
+type bNode[T any] struct {
+	leaf bool
+	// separators, only for internal nodes: all the elements in
+	// children[i] are less than keys[i] and the ones in children[i+1]
+	// are greater than or equal to it
+	keys     []T
+	children []*bNode[T]
+	// elements and siblings, only for leaves
+	items []T
+	prev  *bNode[T]
+	next  *bNode[T]
+}
+
+// index of the child whose range contains the given value
+func (node *bNode[T]) route(val T) int {
+	low, high := 0, len(node.keys)
+	for low < high {
+		mid := (low + high) / 2
+		if tau.Cmp(node.keys[mid], val) <= 0 {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
+
+func (node *bNode[T]) prevPosition(index int) (*bNode[T], int) {
+	if index > 0 {
+		return node, index - 1
+	}
+	if node.prev == nil {
+		return nil, 0
+	}
+	return node.prev, len(node.prev.items) - 1
+}
+
+func (node *bNode[T]) nextPosition(index int) (*bNode[T], int) {
+	if index < len(node.items)-1 {
+		return node, index + 1
+	}
+	if node.next == nil {
+		return nil, 0
+	}
+	return node.next, 0
+}
+
+// index of the first element greater than or equal to the given one
+func search[T any](items []T, val T) int {
+	low, high := 0, len(items)
+	for low < high {
+		mid := (low + high) / 2
+		if tau.Cmp(items[mid], val) < 0 {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low
+}
+
+func insertAt[E any](slice []E, index int, val E) []E {
+	slice = append(slice, val)
+	copy(slice[index+1:], slice[index:])
+	slice[index] = val
+	return slice
+}
+
+func removeAt[E any](slice []E, index int) []E {
+	copy(slice[index:], slice[index+1:])
+	var zero E
+	slice[len(slice)-1] = zero
+	return slice[:len(slice)-1]
+}
+
+// --- Iterator ---
+
+// walks the linked leaves, stopping before the upper bound, if any
+type bTreeIter[T any] struct {
+	leaf  *bNode[T]
+	index int
+	to    *T
+}
+
+func (iter *bTreeIter[T]) Next() (*T, bool) {
+	if iter.leaf == nil {
+		return nil, false
+	}
+	val := iter.leaf.items[iter.index]
+	if iter.to != nil && tau.Cmp(val, *iter.to) >= 0 {
+		iter.leaf = nil
+		return nil, false
+	}
+	iter.leaf, iter.index = iter.leaf.nextPosition(iter.index)
+	return &val, true
+}
+
+func (iter *bTreeIter[T]) Each(f func(T)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
@@ -0,0 +1,227 @@
+package tree
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Fenwick tree (binary indexed tree) over an array of numbers
+//
+// Both updating an element and summing a range take O(log n) time.
+// Unlike [tau.IdxedColl], indices are not sanified: they must be in [0, size).
+type FenwickTree[T tau.Number] struct {
+	// 1-based, node i holds the sum of the elements in (i - lowbit(i), i]
+	tree []T
+}
+
+// Creates a new Fenwick tree holding the elements of the given collection
+// It takes O(n) time
+func Fenwick[T tau.Number](coll tau.IdxedColl[T]) *FenwickTree[T] {
+	ft := &FenwickTree[T]{make([]T, coll.Size()+1)}
+	index := 1
+	coll.Iter().Each(func(value T) {
+		ft.tree[index] = value
+		index++
+	})
+	for i := 1; i < len(ft.tree); i++ {
+		if parent := i + i&-i; parent < len(ft.tree) {
+			ft.tree[parent] += ft.tree[i]
+		}
+	}
+	return ft
+}
+
+func (ft *FenwickTree[T]) String() string {
+	s := "FenwickTree["
+	for i := 0; i < ft.Size(); i++ {
+		if i != 0 {
+			s += ","
+		}
+		value, _ := ft.Get(i)
+		s += fmt.Sprintf("%v", value)
+	}
+	s += "]"
+	return s
+}
+
+// Returns the number of elements
+func (ft *FenwickTree[T]) Size() int {
+	return len(ft.tree) - 1
+}
+
+// Adds the given amount to the element at the given index
+// Returns an error if the index is out of range
+func (ft *FenwickTree[T]) Add(index int, delta T) error {
+	if index < 0 || index >= ft.Size() {
+		return errs.IndexOutOfRange("Add", ft, index, ft.Size())
+	}
+	for i := index + 1; i < len(ft.tree); i += i & -i {
+		ft.tree[i] += delta
+	}
+	return nil
+}
+
+// Returns the element at the given index
+// Returns an error if the index is out of range
+func (ft *FenwickTree[T]) Get(index int) (T, error) {
+	return ft.RangeSum(index, index+1)
+}
+
+// Replaces the element at the given index
+// Returns an error if the index is out of range
+func (ft *FenwickTree[T]) Set(index int, value T) error {
+	old, err := ft.Get(index)
+	if err != nil {
+		return err
+	}
+	return ft.Add(index, value-old)
+}
+
+// Returns the sum of the first n elements
+// Returns an error if n is not in [0, size]
+func (ft *FenwickTree[T]) PrefixSum(n int) (T, error) {
+	var sum T
+	if n < 0 || n > ft.Size() {
+		return sum, errs.IndexOutOfRange("PrefixSum", ft, n, ft.Size()+1)
+	}
+	for i := n; i > 0; i -= i & -i {
+		sum += ft.tree[i]
+	}
+	return sum, nil
+}
+
+// Returns the sum of the elements in the range [from, to)
+// Returns an error if the range is not within [0, size]
+func (ft *FenwickTree[T]) RangeSum(from, to int) (T, error) {
+	if from < 0 || to > ft.Size() || from > to {
+		var zero T
+		return zero, rangeErr("RangeSum", ft, from, to, ft.Size())
+	}
+	high, err := ft.PrefixSum(to)
+	if err != nil {
+		return high, err
+	}
+	low, err := ft.PrefixSum(from)
+	if err != nil {
+		return low, err
+	}
+	return high - low, nil
+}
+
+// Returns the smallest n such that the sum of the first n elements is
+// at least the given value, or size+1 if there's no such n
+// The elements must not be negative
+func (ft *FenwickTree[T]) Search(value T) int {
+	var sum T
+	if value <= sum {
+		return 0
+	}
+	pos := 0
+	step := 1
+	for step*2 < len(ft.tree) {
+		step *= 2
+	}
+	for ; step > 0; step /= 2 {
+		if next := pos + step; next < len(ft.tree) && sum+ft.tree[next] < value {
+			pos = next
+			sum += ft.tree[next]
+		}
+	}
+	return pos + 1
+}
+
+// Fenwick tree supporting additions to whole ranges
+//
+// Both adding to a range and summing a range take O(log n) time.
+// Indices must be in [0, size).
+type RangeFenwickTree[T tau.Number] struct {
+	// the sum of the first n elements is n * prefix(base, n) - prefix(scaled, n)
+	base   *FenwickTree[T]
+	scaled *FenwickTree[T]
+}
+
+// Creates a new range Fenwick tree holding the elements of the given collection
+func RangeFenwick[T tau.Number](coll tau.IdxedColl[T]) *RangeFenwickTree[T] {
+	n := coll.Size()
+	rft := &RangeFenwickTree[T]{&FenwickTree[T]{make([]T, n+1)}, &FenwickTree[T]{make([]T, n+1)}}
+	index := 0
+	coll.Iter().Each(func(value T) {
+		rft.AddRange(index, index+1, value)
+		index++
+	})
+	return rft
+}
+
+func (rft *RangeFenwickTree[T]) String() string {
+	s := "RangeFenwickTree["
+	for i := 0; i < rft.Size(); i++ {
+		if i != 0 {
+			s += ","
+		}
+		value, _ := rft.Get(i)
+		s += fmt.Sprintf("%v", value)
+	}
+	s += "]"
+	return s
+}
+
+// Returns the number of elements
+func (rft *RangeFenwickTree[T]) Size() int {
+	return rft.base.Size()
+}
+
+// Adds the given amount to all the elements in the range [from, to)
+// Returns an error if the range is not within [0, size]
+func (rft *RangeFenwickTree[T]) AddRange(from, to int, delta T) error {
+	if from < 0 || to > rft.Size() || from > to {
+		return rangeErr("AddRange", rft, from, to, rft.Size())
+	}
+	if from == to {
+		return nil
+	}
+	rft.base.Add(from, delta)
+	rft.scaled.Add(from, delta*T(from))
+	if to < rft.Size() {
+		rft.base.Add(to, -delta)
+		rft.scaled.Add(to, -delta*T(to))
+	}
+	return nil
+}
+
+// Returns the element at the given index
+// Returns an error if the index is out of range
+func (rft *RangeFenwickTree[T]) Get(index int) (T, error) {
+	return rft.RangeSum(index, index+1)
+}
+
+// Returns the sum of the first n elements
+// Returns an error if n is not in [0, size]
+func (rft *RangeFenwickTree[T]) PrefixSum(n int) (T, error) {
+	if n < 0 || n > rft.Size() {
+		var zero T
+		return zero, errs.IndexOutOfRange("PrefixSum", rft, n, rft.Size()+1)
+	}
+	base, _ := rft.base.PrefixSum(n)
+	scaled, _ := rft.scaled.PrefixSum(n)
+	return base*T(n) - scaled, nil
+}
+
+// Returns the sum of the elements in the range [from, to)
+// Returns an error if the range is not within [0, size]
+func (rft *RangeFenwickTree[T]) RangeSum(from, to int) (T, error) {
+	if from < 0 || to > rft.Size() || from > to {
+		var zero T
+		return zero, rangeErr("RangeSum", rft, from, to, rft.Size())
+	}
+	high, err := rft.PrefixSum(to)
+	if err != nil {
+		return high, err
+	}
+	low, err := rft.PrefixSum(from)
+	if err != nil {
+		return low, err
+	}
+	return high - low, nil
+}
@@ -0,0 +1,269 @@
+package tree
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Half-open interval [Lo, Hi) of values comparable with [tau.Cmp]
+//
+// Intervals with Lo >= Hi are empty.
+type Interval[E any] struct {
+	Lo E
+	Hi E
+}
+
+// Creates a new interval [lo, hi)
+func Ival[E any](lo, hi E) Interval[E] {
+	return Interval[E]{lo, hi}
+}
+
+// Intervals are ordered by their lower endpoint, then by the upper one
+func (iv Interval[E]) Cmp(other any) int {
+	otherIv := other.(Interval[E])
+	if cmp := tau.Cmp(iv.Lo, otherIv.Lo); cmp != 0 {
+		return cmp
+	}
+	return tau.Cmp(iv.Hi, otherIv.Hi)
+}
+
+func (iv Interval[E]) String() string {
+	return fmt.Sprintf("[%v, %v)", iv.Lo, iv.Hi)
+}
+
+func (iv Interval[E]) Empty() bool {
+	return tau.Cmp(iv.Lo, iv.Hi) >= 0
+}
+
+// Returns true if the given point is in the interval
+func (iv Interval[E]) Contains(point E) bool {
+	return tau.Cmp(iv.Lo, point) <= 0 && tau.Cmp(point, iv.Hi) < 0
+}
+
+// Returns true if the two intervals have at least one point in common
+func (iv Interval[E]) Overlaps(other Interval[E]) bool {
+	return tau.Cmp(iv.Lo, other.Hi) < 0 && tau.Cmp(other.Lo, iv.Hi) < 0 && !iv.Empty() && !other.Empty()
+}
+
+// Interval tree, implemented with a Red-Black Tree augmented with the
+// greatest upper endpoint of every subtree
+//
+// Queries for the intervals overlapping a range or containing a point
+// take O(log n + k) time, where k is the number of intervals returned.
+// Equal intervals are stored once, and empty ones are never stored.
+type IntervalTree[E any] struct {
+	tree *RBTree[*ivItem[E]]
+}
+
+// Creates a new empty interval tree
+func Itv[E any]() *IntervalTree[E] {
+	rb := RB[*ivItem[E]]()
+	rb.update = updateIvMax[E]
+	return &IntervalTree[E]{rb}
+}
+
+// --- Methods from tau.Collection[Interval[E]] ---
+func (it *IntervalTree[E]) String() string {
+	s := "IntervalTree["
+	first := true
+	it.Iter().Each(func(iv Interval[E]) {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += iv.String()
+	})
+	s += "]"
+	return s
+}
+
+func (it *IntervalTree[E]) Cmp(other any) int {
+	return tau.CollCmp[Interval[E]](it, other)
+}
+
+// Iterates over the intervals in ascending order
+func (it *IntervalTree[E]) Iter() tau.Iterator[Interval[E]] {
+	return &ivIter[E]{it.tree.InOrder()}
+}
+
+func (it *IntervalTree[E]) Size() int {
+	return it.tree.Size()
+}
+
+func (it *IntervalTree[E]) Empty() bool {
+	return it.tree.Empty()
+}
+
+func (it *IntervalTree[E]) Clear() {
+	it.tree.Clear()
+}
+
+func (it *IntervalTree[E]) Contains(iv Interval[E]) bool {
+	return it.tree.Contains(&ivItem[E]{iv: iv})
+}
+
+func (it *IntervalTree[E]) ContainsAll(coll tau.Collection[Interval[E]]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !it.Contains(*next) {
+			return false
+		}
+	}
+	return true
+}
+
+func (it *IntervalTree[E]) ContainsAny(coll tau.Collection[Interval[E]]) bool {
+	iter := coll.Iter()
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if it.Contains(*next) {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *IntervalTree[E]) Clone() tau.Collection[Interval[E]] {
+	clone := Itv[E]()
+	it.Iter().Each(func(iv Interval[E]) {
+		clone.Insert(iv)
+	})
+	return clone
+}
+
+// --- Tree operations ---
+
+// Inserts the given interval
+// Returns false if the interval is empty or already present
+func (it *IntervalTree[E]) Insert(iv Interval[E]) bool {
+	if iv.Empty() || it.Contains(iv) {
+		return false
+	}
+	it.tree.Insert(&ivItem[E]{iv, iv.Hi})
+	return true
+}
+
+// Removes the given interval
+// Returns false if the interval is not present
+func (it *IntervalTree[E]) Remove(iv Interval[E]) bool {
+	if !it.Contains(iv) {
+		return false
+	}
+	it.tree.Remove(&ivItem[E]{iv: iv})
+	return true
+}
+
+// Iterates, in ascending order, over the intervals overlapping [lo, hi)
+// Nothing overlaps an empty range
+func (it *IntervalTree[E]) Overlapping(lo, hi E) tau.Iterator[Interval[E]] {
+	query := Interval[E]{lo, hi}
+	if query.Empty() {
+		return newIvQueryIter(Itv[E](), query, false)
+	}
+	return newIvQueryIter(it, query, false)
+}
+
+// Iterates, in ascending order, over the intervals containing the given point
+func (it *IntervalTree[E]) Stabbing(point E) tau.Iterator[Interval[E]] {
+	return newIvQueryIter(it, Interval[E]{point, point}, true)
+}
+
+// --- Private types ---
+
+// interval, along with the greatest upper endpoint in its subtree
+type ivItem[E any] struct {
+	iv  Interval[E]
+	max E
+}
+
+func (item *ivItem[E]) Cmp(other any) int {
+	return item.iv.Cmp(other.(*ivItem[E]).iv)
+}
+
+func updateIvMax[E any](node *rbNode[*ivItem[E]]) {
+	max := node.val.iv.Hi
+	if node.left != nil && tau.Cmp(node.left.val.max, max) > 0 {
+		max = node.left.val.max
+	}
+	if node.right != nil && tau.Cmp(node.right.val.max, max) > 0 {
+		max = node.right.val.max
+	}
+	node.val.max = max
+}
+
+// --- Iterators ---
+type ivIter[E any] struct {
+	inner tau.Iterator[*ivItem[E]]
+}
+
+func (iter *ivIter[E]) Next() (*Interval[E], bool) {
+	next, ok := iter.inner.Next()
+	if !ok {
+		return nil, false
+	}
+	return &(*next).iv, true
+}
+
+func (iter *ivIter[E]) Each(f func(Interval[E])) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+// in-order visit skipping the subtrees that cannot hold a result
+type ivQueryIter[E any] struct {
+	query Interval[E]
+	// true if the query is a single point, Lo
+	point bool
+	stack *deque.ArrDeque[*rbNode[*ivItem[E]]]
+}
+
+func newIvQueryIter[E any](it *IntervalTree[E], query Interval[E], point bool) *ivQueryIter[E] {
+	iter := &ivQueryIter[E]{query, point, deque.Arr[*rbNode[*ivItem[E]]]()}
+	iter.pushLeft(it.tree.root)
+	return iter
+}
+
+// pushes the left spine of the given subtree, stopping at the first
+// subtree whose intervals all end before the query
+func (iter *ivQueryIter[E]) pushLeft(node *rbNode[*ivItem[E]]) {
+	for node != nil && tau.Cmp(node.val.max, iter.query.Lo) > 0 {
+		iter.stack.PushBack(node)
+		node = node.left
+	}
+}
+
+// true if the intervals starting at the given point or later are all after the query
+func (iter *ivQueryIter[E]) after(lo E) bool {
+	if iter.point {
+		return tau.Cmp(lo, iter.query.Lo) > 0
+	}
+	return tau.Cmp(lo, iter.query.Hi) >= 0
+}
+
+func (iter *ivQueryIter[E]) Next() (*Interval[E], bool) {
+	for {
+		top, err := iter.stack.PopBack()
+		if err != nil {
+			return nil, false
+		}
+		node := *top
+		iv := node.val.iv
+		if iter.after(iv.Lo) {
+			// so is everything in the right subtree and above
+			iter.stack.Clear()
+			return nil, false
+		}
+		iter.pushLeft(node.right)
+		if tau.Cmp(iv.Hi, iter.query.Lo) > 0 {
+			return &iv, true
+		}
+	}
+}
+
+func (iter *ivQueryIter[E]) Each(f func(Interval[E])) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
@@ -2,9 +2,12 @@ package tree
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"reflect"
 
+	"github.com/luverolla/lexgo/pkg/algo"
 	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/errs"
 	"github.com/luverolla/lexgo/pkg/tau"
 )
 
@@ -27,14 +30,33 @@ func Black[T any](node *rbNode[T]) bool {
 type RBTree[T any] struct {
 	root *rbNode[T]
 	size int
+	// called on the nodes whose subtree changed, children first,
+	// to keep augmented data up to date; nil for plain trees
+	update func(*rbNode[T])
 }
 
 // Creates a new empty Red-Black Tree.
 func RB[T any]() *RBTree[T] {
-	return &RBTree[T]{nil, 0}
+	return &RBTree[T]{nil, 0, nil}
 }
 
 // --- Methods from tau.Collection[T] ---
+func (rb *RBTree[T]) String() string {
+	s := "RBTree["
+	iter := rb.Iter()
+	for next, hasNext := iter.Next(); hasNext; {
+		s += fmt.Sprintf("%v", *next)
+		if next, hasNext = iter.Next(); hasNext {
+			s += ","
+		}
+	}
+	return s + "]"
+}
+
+func (rb *RBTree[T]) Cmp(other any) int {
+	return tau.CollCmp[T](rb, other)
+}
+
 func (rb *RBTree[T]) Size() int {
 	return rb.size
 }
@@ -76,6 +98,10 @@ func (rb *RBTree[T]) Iter() tau.Iterator[T] {
 	return rb.InOrder()
 }
 
+func (rb *RBTree[T]) Clone() tau.Collection[T] {
+	return &RBTree[T]{cloneRB(rb.root, nil), rb.size, rb.update}
+}
+
 // --- Methods from tau.BSTree[T] ---
 func (rb *RBTree[T]) Get(val T) tau.BSTreeNode[T] {
 	return rb.get(rb.root, val)
@@ -123,6 +149,40 @@ func (rb *RBTree[T]) PostOrder() tau.Iterator[T] {
 	return newRBPostOrderIter[T](rb)
 }
 
+// --- Order statistics ---
+
+// Returns the element with exactly k smaller elements
+// It takes O(log n) time, as every node knows the size of its subtree
+// Returns an error if k is not in [0, size)
+func (rb *RBTree[T]) Select(k int) (*T, error) {
+	if k < 0 || k >= rbCount(rb.root) {
+		return nil, errs.IndexOutOfRange("Select", rb, k, rbCount(rb.root))
+	}
+	node := rb.root
+	for {
+		left := rbCount(node.left)
+		switch {
+		case k < left:
+			node = node.left
+		case k > left:
+			k -= left + 1
+			node = node.right
+		default:
+			return &node.val, nil
+		}
+	}
+}
+
+// Returns an element chosen at random, every one with the same probability
+// The global random source is used if r is nil. It takes O(log n) time
+// Returns an error if the tree is empty
+func (rb *RBTree[T]) RandomElement(r *rand.Rand) (*T, error) {
+	if tau.Nil(rb.root) {
+		return nil, errs.Empty()
+	}
+	return rb.Select(algo.RandIntN(r, rbCount(rb.root)))
+}
+
 // --- Private methods ---
 func (rb *RBTree[T]) get(root *rbNode[T], val T) *rbNode[T] {
 	if tau.Nil(root) {
@@ -140,6 +200,7 @@ func (rb *RBTree[T]) insert(root *rbNode[T], val T) {
 	if tau.Nil(root) {
 		rb.size++
 		rb.root = newRBNode[T](val, BLACK)
+		rb.updateUp(rb.root)
 		return
 	}
 	if tau.Cmp(val, root.val) < 0 {
@@ -147,6 +208,7 @@ func (rb *RBTree[T]) insert(root *rbNode[T], val T) {
 			rb.size++
 			root.left = newRBNode[T](val, RED)
 			root.left.parent = root
+			rb.updateUp(root.left)
 			rb.fixInsert(root.left)
 		} else {
 			rb.insert(root.left, val)
@@ -156,6 +218,7 @@ func (rb *RBTree[T]) insert(root *rbNode[T], val T) {
 			rb.size++
 			root.right = newRBNode[T](val, RED)
 			root.right.parent = root
+			rb.updateUp(root.right)
 			rb.fixInsert(root.right)
 		} else {
 			rb.insert(root.right, val)
@@ -176,30 +239,30 @@ func (rb *RBTree[T]) remove(root *rbNode[T], val T) {
 		nodeToRemove = pred
 	}
 
+	// the node has at most one child, which takes its place
 	var child *rbNode[T]
 	if nodeToRemove.left != nil {
 		child = nodeToRemove.left
 	} else {
 		child = nodeToRemove.right
 	}
+	parent := nodeToRemove.parent
 
-	if nodeToRemove.color == BLACK {
-		if child != nil {
-			nodeToRemove.color = child.color
-		}
-		rb.fixRemove(nodeToRemove)
-	}
-
-	if tau.Nil(nodeToRemove.parent) {
+	if tau.Nil(parent) {
 		rb.root = child
-	} else if nodeToRemove == nodeToRemove.parent.left {
-		nodeToRemove.parent.left = child
+	} else if nodeToRemove == parent.left {
+		parent.left = child
 	} else {
-		nodeToRemove.parent.right = child
+		parent.right = child
 	}
-
 	if child != nil {
-		child.parent = nodeToRemove.parent
+		child.parent = parent
+	}
+	// the node whose value was replaced, if any, is an ancestor
+	rb.updateUp(parent)
+
+	if nodeToRemove.color == BLACK {
+		rb.fixRemove(child, parent)
 	}
 
 	nodeToRemove.parent = nil
@@ -248,59 +311,65 @@ func (rb *RBTree[T]) fixInsert(node *rbNode[T]) {
 }
 
 // fix violations of the red-black tree properties after removal
-func (rb *RBTree[T]) fixRemove(node *rbNode[T]) {
+// The node, possibly nil, has an extra black; its parent is given
+// since it can't be reached from a nil node
+func (rb *RBTree[T]) fixRemove(node *rbNode[T], parent *rbNode[T]) {
 	for node != rb.root && Black(node) {
-		if node == node.parent.left {
-			sibling := node.parent.right
+		if node == parent.left {
+			sibling := parent.right
 			if !Black(sibling) {
 				sibling.color = BLACK
-				node.parent.color = RED
-				rb.rotateLeft(node.parent)
-				sibling = node.parent.right
+				parent.color = RED
+				rb.rotateLeft(parent)
+				sibling = parent.right
 			}
 			if Black(sibling.left) && Black(sibling.right) {
 				sibling.color = RED
-				node = node.parent
+				node = parent
+				parent = node.parent
 			} else {
 				if Black(sibling.right) {
 					sibling.left.color = BLACK
 					sibling.color = RED
 					rb.rotateRight(sibling)
-					sibling = node.parent.right
+					sibling = parent.right
 				}
-				sibling.color = node.parent.color
-				node.parent.color = BLACK
+				sibling.color = parent.color
+				parent.color = BLACK
 				sibling.right.color = BLACK
-				rb.rotateLeft(node.parent)
+				rb.rotateLeft(parent)
 				node = rb.root
 			}
 		} else {
-			sibling := node.parent.left
+			sibling := parent.left
 			if !Black(sibling) {
 				sibling.color = BLACK
-				node.parent.color = RED
-				rb.rotateRight(node.parent)
-				sibling = node.parent.left
+				parent.color = RED
+				rb.rotateRight(parent)
+				sibling = parent.left
 			}
 			if Black(sibling.left) && Black(sibling.right) {
 				sibling.color = RED
-				node = node.parent
+				node = parent
+				parent = node.parent
 			} else {
 				if Black(sibling.left) {
 					sibling.right.color = BLACK
 					sibling.color = RED
 					rb.rotateLeft(sibling)
-					sibling = node.parent.left
+					sibling = parent.left
 				}
-				sibling.color = node.parent.color
-				node.parent.color = BLACK
+				sibling.color = parent.color
+				parent.color = BLACK
 				sibling.left.color = BLACK
-				rb.rotateRight(node.parent)
+				rb.rotateRight(parent)
 				node = rb.root
 			}
 		}
 	}
-	node.color = BLACK
+	if node != nil {
+		node.color = BLACK
+	}
 }
 
 func (tree *RBTree[T]) rotateLeft(root *rbNode[T]) {
@@ -319,6 +388,8 @@ func (tree *RBTree[T]) rotateLeft(root *rbNode[T]) {
 	}
 	right.left = root
 	root.parent = right
+	tree.refresh(root)
+	tree.refresh(right)
 }
 
 func (tree *RBTree[T]) rotateRight(root *rbNode[T]) {
@@ -337,6 +408,30 @@ func (tree *RBTree[T]) rotateRight(root *rbNode[T]) {
 	}
 	left.right = root
 	root.parent = left
+	tree.refresh(root)
+	tree.refresh(left)
+}
+
+// refreshes the given node and all its ancestors
+func (rb *RBTree[T]) updateUp(node *rbNode[T]) {
+	for ; node != nil; node = node.parent {
+		rb.refresh(node)
+	}
+}
+
+// recomputes the subtree count of the node, and calls the update function
+func (rb *RBTree[T]) refresh(node *rbNode[T]) {
+	node.count = 1 + rbCount(node.left) + rbCount(node.right)
+	if rb.update != nil {
+		rb.update(node)
+	}
+}
+
+func rbCount[T any](node *rbNode[T]) int {
+	if node == nil {
+		return 0
+	}
+	return node.count
 }
 
 func (rb *RBTree[T]) min(root *rbNode[T]) *rbNode[T] {
@@ -419,10 +514,22 @@ type rbNode[T any] struct {
 	right  *rbNode[T]
 	parent *rbNode[T]
 	color  rbColor
+	// number of nodes in the subtree
+	count int
+}
+
+// copies the subtree, whose root gets the given parent
+func cloneRB[T any](node, parent *rbNode[T]) *rbNode[T] {
+	if node == nil {
+		return nil
+	}
+	clone := &rbNode[T]{node.val, nil, nil, parent, node.color, node.count}
+	clone.left, clone.right = cloneRB(node.left, clone), cloneRB(node.right, clone)
+	return clone
 }
 
 func newRBNode[T any](val T, color rbColor) *rbNode[T] {
-	return &rbNode[T]{val, nil, nil, nil, color}
+	return &rbNode[T]{val, nil, nil, nil, color, 1}
 }
 
 func (node *rbNode[T]) Value() T {
@@ -519,18 +626,9 @@ func (iter *rbInOrderIter[T]) Next() (*T, bool) {
 		return nil, false
 	}
 	node, _ := iter.stack.PopFront()
-	if node.right != nil {
-		node = node.right
-		for node != nil {
-			iter.stack.PushFront(*node)
-			node = node.left
-		}
-	}
-
-	if tau.Nil(node) {
-		return nil, false
+	for next := node.right; next != nil; next = next.left {
+		iter.stack.PushFront(*next)
 	}
-
 	return &node.val, true
 }
 
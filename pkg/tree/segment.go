@@ -0,0 +1,407 @@
+package tree
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Associative operation with an identity element
+//
+// Combine must be associative, and combining any value with the
+// identity, on either side, must give back the value itself.
+type Monoid[T any] struct {
+	Identity T
+	Combine  func(T, T) T
+}
+
+// Monoid of the sum of numbers
+func SumMonoid[T tau.Number]() Monoid[T] {
+	return Monoid[T]{0, func(a, b T) T { return a + b }}
+}
+
+// Monoid of the minimum of numbers, whose identity is the greatest value of T
+func MinMonoid[T tau.Number]() Monoid[T] {
+	return Monoid[T]{maxValue[T](), func(a, b T) T {
+		if b < a {
+			return b
+		}
+		return a
+	}}
+}
+
+// Monoid of the maximum of numbers, whose identity is the least value of T
+func MaxMonoid[T tau.Number]() Monoid[T] {
+	return Monoid[T]{minValue[T](), func(a, b T) T {
+		if b > a {
+			return b
+		}
+		return a
+	}}
+}
+
+// Segment tree over an array, aggregating ranges with a monoid
+//
+// Replacing an element and aggregating a range take O(log n) time.
+// Elements are combined in index order, so the monoid doesn't need to be
+// commutative. Indices must be in [0, size).
+type SegmentTree[T any] struct {
+	monoid Monoid[T]
+	n      int
+	// node i has children 2i and 2i+1, the leaves start at n
+	tree []T
+}
+
+// Creates a new segment tree holding the elements of the given collection
+// It takes O(n) time
+func Segment[T any](coll tau.IdxedColl[T], monoid Monoid[T]) *SegmentTree[T] {
+	n := coll.Size()
+	st := &SegmentTree[T]{monoid, n, make([]T, 2*n)}
+	index := n
+	coll.Iter().Each(func(value T) {
+		st.tree[index] = value
+		index++
+	})
+	for i := n - 1; i > 0; i-- {
+		st.tree[i] = monoid.Combine(st.tree[2*i], st.tree[2*i+1])
+	}
+	return st
+}
+
+func (st *SegmentTree[T]) String() string {
+	s := "SegmentTree["
+	for i := 0; i < st.n; i++ {
+		if i != 0 {
+			s += ","
+		}
+		s += fmt.Sprintf("%v", st.tree[st.n+i])
+	}
+	s += "]"
+	return s
+}
+
+// Returns the number of elements
+func (st *SegmentTree[T]) Size() int {
+	return st.n
+}
+
+// Returns the element at the given index
+// Returns an error if the index is out of range
+func (st *SegmentTree[T]) Get(index int) (T, error) {
+	if index < 0 || index >= st.n {
+		return st.monoid.Identity, errs.IndexOutOfRange("Get", st, index, st.n)
+	}
+	return st.tree[st.n+index], nil
+}
+
+// Replaces the element at the given index
+// Returns an error if the index is out of range
+func (st *SegmentTree[T]) Set(index int, value T) error {
+	if index < 0 || index >= st.n {
+		return errs.IndexOutOfRange("Set", st, index, st.n)
+	}
+	i := st.n + index
+	st.tree[i] = value
+	for i /= 2; i > 0; i /= 2 {
+		st.tree[i] = st.monoid.Combine(st.tree[2*i], st.tree[2*i+1])
+	}
+	return nil
+}
+
+// Returns the combination of the elements in the range [from, to)
+// The identity is returned for an empty range
+// Returns an error if the range is not within [0, size]
+func (st *SegmentTree[T]) Query(from, to int) (T, error) {
+	if from < 0 || to > st.n || from > to {
+		return st.monoid.Identity, rangeErr("Query", st, from, to, st.n)
+	}
+	left, right := st.monoid.Identity, st.monoid.Identity
+	for lo, hi := from+st.n, to+st.n; lo < hi; lo, hi = lo/2, hi/2 {
+		if lo&1 == 1 {
+			left = st.monoid.Combine(left, st.tree[lo])
+			lo++
+		}
+		if hi&1 == 1 {
+			hi--
+			right = st.monoid.Combine(st.tree[hi], right)
+		}
+	}
+	return st.monoid.Combine(left, right), nil
+}
+
+// Way of updating whole ranges of a [LazySegmentTree]
+//
+// Apply gives the aggregate of a range of the given length after the
+// update. Compose merges two updates in a single one, the first being
+// the newer.
+type Updater[T any, U any] struct {
+	Apply   func(update U, aggregate T, length int) T
+	Compose func(newer U, older U) U
+}
+
+// Updater adding a value to every element of a range, for sums
+func AddToSum[T tau.Number]() Updater[T, T] {
+	return Updater[T, T]{
+		func(delta, sum T, length int) T { return sum + delta*T(length) },
+		func(newer, older T) T { return newer + older },
+	}
+}
+
+// Updater adding a value to every element of a range, for minimums and maximums
+func AddToExtreme[T tau.Number]() Updater[T, T] {
+	return Updater[T, T]{
+		func(delta, extreme T, length int) T { return extreme + delta },
+		func(newer, older T) T { return newer + older },
+	}
+}
+
+// Segment tree with lazy propagation, which also updates whole ranges
+//
+// Updating a range, replacing an element and aggregating a range all
+// take O(log n) time. Updates are kept in the nodes covering the updated
+// range, and pushed down to the children only when needed.
+type LazySegmentTree[T any, U any] struct {
+	monoid  Monoid[T]
+	updater Updater[T, U]
+	n       int
+	// node 1 is the root, node i has children 2i and 2i+1
+	tree    []T
+	lazy    []U
+	pending []bool
+}
+
+// Creates a new lazy segment tree holding the elements of the given collection
+// It takes O(n) time
+func LazySegment[T any, U any](coll tau.IdxedColl[T], monoid Monoid[T], updater Updater[T, U]) *LazySegmentTree[T, U] {
+	n := coll.Size()
+	st := &LazySegmentTree[T, U]{monoid, updater, n, make([]T, 4*n), make([]U, 4*n), make([]bool, 4*n)}
+	values := make([]T, 0, n)
+	coll.Iter().Each(func(value T) {
+		values = append(values, value)
+	})
+	if n > 0 {
+		st.build(1, 0, n, values)
+	}
+	return st
+}
+
+func (st *LazySegmentTree[T, U]) String() string {
+	s := "LazySegmentTree["
+	for i := 0; i < st.n; i++ {
+		if i != 0 {
+			s += ","
+		}
+		value, _ := st.Get(i)
+		s += fmt.Sprintf("%v", value)
+	}
+	s += "]"
+	return s
+}
+
+// Returns the number of elements
+func (st *LazySegmentTree[T, U]) Size() int {
+	return st.n
+}
+
+// Returns the element at the given index
+// Returns an error if the index is out of range
+func (st *LazySegmentTree[T, U]) Get(index int) (T, error) {
+	if index < 0 || index >= st.n {
+		return st.monoid.Identity, errs.IndexOutOfRange("Get", st, index, st.n)
+	}
+	return st.Query(index, index+1)
+}
+
+// Replaces the element at the given index
+// Returns an error if the index is out of range
+func (st *LazySegmentTree[T, U]) Set(index int, value T) error {
+	if index < 0 || index >= st.n {
+		return errs.IndexOutOfRange("Set", st, index, st.n)
+	}
+	st.set(1, 0, st.n, index, value)
+	return nil
+}
+
+// Applies the given update to all the elements in the range [from, to)
+// Returns an error if the range is not within [0, size]
+func (st *LazySegmentTree[T, U]) Update(from, to int, update U) error {
+	if from < 0 || to > st.n || from > to {
+		return rangeErr("Update", st, from, to, st.n)
+	}
+	if from < to {
+		st.update(1, 0, st.n, from, to, update)
+	}
+	return nil
+}
+
+// Returns the combination of the elements in the range [from, to)
+// The identity is returned for an empty range
+// Returns an error if the range is not within [0, size]
+func (st *LazySegmentTree[T, U]) Query(from, to int) (T, error) {
+	if from < 0 || to > st.n || from > to {
+		return st.monoid.Identity, rangeErr("Query", st, from, to, st.n)
+	}
+	if from == to {
+		return st.monoid.Identity, nil
+	}
+	return st.query(1, 0, st.n, from, to), nil
+}
+
+// --- Private methods ---
+
+// the node covers the elements in [lo, hi)
+func (st *LazySegmentTree[T, U]) build(node, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		st.tree[node] = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	st.build(2*node, lo, mid, values)
+	st.build(2*node+1, mid, hi, values)
+	st.tree[node] = st.monoid.Combine(st.tree[2*node], st.tree[2*node+1])
+}
+
+func (st *LazySegmentTree[T, U]) apply(node, length int, update U) {
+	st.tree[node] = st.updater.Apply(update, st.tree[node], length)
+	if st.pending[node] {
+		st.lazy[node] = st.updater.Compose(update, st.lazy[node])
+	} else {
+		st.lazy[node] = update
+		st.pending[node] = true
+	}
+}
+
+func (st *LazySegmentTree[T, U]) push(node, lo, hi int) {
+	if !st.pending[node] {
+		return
+	}
+	mid := (lo + hi) / 2
+	st.apply(2*node, mid-lo, st.lazy[node])
+	st.apply(2*node+1, hi-mid, st.lazy[node])
+	st.pending[node] = false
+}
+
+func (st *LazySegmentTree[T, U]) update(node, lo, hi, from, to int, update U) {
+	if from <= lo && hi <= to {
+		st.apply(node, hi-lo, update)
+		return
+	}
+	st.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	if from < mid {
+		st.update(2*node, lo, mid, from, to, update)
+	}
+	if to > mid {
+		st.update(2*node+1, mid, hi, from, to, update)
+	}
+	st.tree[node] = st.monoid.Combine(st.tree[2*node], st.tree[2*node+1])
+}
+
+func (st *LazySegmentTree[T, U]) set(node, lo, hi, index int, value T) {
+	if hi-lo == 1 {
+		st.tree[node] = value
+		st.pending[node] = false
+		return
+	}
+	st.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	if index < mid {
+		st.set(2*node, lo, mid, index, value)
+	} else {
+		st.set(2*node+1, mid, hi, index, value)
+	}
+	st.tree[node] = st.monoid.Combine(st.tree[2*node], st.tree[2*node+1])
+}
+
+func (st *LazySegmentTree[T, U]) query(node, lo, hi, from, to int) T {
+	if from <= lo && hi <= to {
+		return st.tree[node]
+	}
+	st.push(node, lo, hi)
+	mid := (lo + hi) / 2
+	result := st.monoid.Identity
+	if from < mid {
+		result = st.monoid.Combine(result, st.query(2*node, lo, mid, from, to))
+	}
+	if to > mid {
+		result = st.monoid.Combine(result, st.query(2*node+1, mid, hi, from, to))
+	}
+	return result
+}
+
+// --- Private functions ---
+
+// error for a range [from, to) that is not within [0, size]
+func rangeErr(op string, coll any, from, to, size int) error {
+	switch {
+	case from < 0:
+		return errs.IndexOutOfRange(op, coll, from, size+1)
+	case to > size:
+		return errs.IndexOutOfRange(op, coll, to, size+1)
+	}
+	// from must not be past to
+	return errs.IndexOutOfRange(op, coll, from, to+1)
+}
+
+func maxValue[T tau.Number]() T {
+	var zero T
+	switch any(zero).(type) {
+	case int:
+		return any(int(math.MaxInt)).(T)
+	case int8:
+		return any(int8(math.MaxInt8)).(T)
+	case int16:
+		return any(int16(math.MaxInt16)).(T)
+	case int32:
+		return any(int32(math.MaxInt32)).(T)
+	case int64:
+		return any(int64(math.MaxInt64)).(T)
+	case uint:
+		return any(uint(math.MaxUint)).(T)
+	case uint8:
+		return any(uint8(math.MaxUint8)).(T)
+	case uint16:
+		return any(uint16(math.MaxUint16)).(T)
+	case uint32:
+		return any(uint32(math.MaxUint32)).(T)
+	case uint64:
+		return any(uint64(math.MaxUint64)).(T)
+	case uintptr:
+		return any(^uintptr(0)).(T)
+	case float32:
+		return any(float32(math.Inf(1))).(T)
+	case float64:
+		return any(math.Inf(1)).(T)
+	}
+	// named types: all bits set but the sign one, or +Inf
+	return maxOfNamed[T]()
+}
+
+func minValue[T tau.Number]() T {
+	max := maxValue[T]()
+	if max < 0 || max+1 < max {
+		// signed integer, the minimum is right after the maximum
+		return max + 1
+	}
+	if max+1 == max {
+		// floating point infinity
+		return -max
+	}
+	return 0
+}
+
+// maximum of a type defined on top of a number, found by doubling
+func maxOfNamed[T tau.Number]() T {
+	var one T = 1
+	if one/2 != 0 {
+		// floating point
+		return T(math.Inf(1))
+	}
+	max := one
+	for max*2+1 > max {
+		max = max*2 + 1
+	}
+	return max
+}
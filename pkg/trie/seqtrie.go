@@ -0,0 +1,488 @@
+// This package contains prefix trees implementing the interface [tau.Map]
+//
+// Both the implementations are compressed (radix) trees: chains of nodes
+// with a single child and no value are merged into one node, whose edge
+// is labeled by the whole chain.
+package trie
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Radix tree keyed by sequences of elements of any comparable type
+//
+// Sequences are ordered lexicographically, comparing the elements with
+// [tau.Cmp]; a sequence comes before all the ones it's a prefix of.
+type SeqTrie[T any, V any] struct {
+	root *node[T, V]
+	size int
+}
+
+// Creates a new radix tree keyed by sequences
+func Seq[T any, V any]() *SeqTrie[T, V] {
+	return &SeqTrie[T, V]{&node[T, V]{}, 0}
+}
+
+// --- Methods from Collection[[]T] ---
+func (trie *SeqTrie[T, V]) String() string {
+	s := "SeqTrie{"
+	first := true
+	iter := newEntryIter(trie.root, nil)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += fmt.Sprintf("(%v: %v)", next.key, next.value)
+	}
+	s += "}"
+	return s
+}
+
+func (trie *SeqTrie[T, V]) Cmp(other any) int {
+	return tau.CollCmpBy[[]T](trie, other, cmpSeq[T])
+}
+
+func (trie *SeqTrie[T, V]) Iter() tau.Iterator[[]T] {
+	return trie.Keys()
+}
+
+func (trie *SeqTrie[T, V]) Size() int {
+	return trie.size
+}
+
+func (trie *SeqTrie[T, V]) Empty() bool {
+	return trie.size == 0
+}
+
+func (trie *SeqTrie[T, V]) Clear() {
+	trie.root = &node[T, V]{}
+	trie.size = 0
+}
+
+func (trie *SeqTrie[T, V]) Contains(key []T) bool {
+	return trie.HasKey(key)
+}
+
+func (trie *SeqTrie[T, V]) ContainsAll(other tau.Collection[[]T]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !trie.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (trie *SeqTrie[T, V]) ContainsAny(other tau.Collection[[]T]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if trie.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+func (trie *SeqTrie[T, V]) Clone() tau.Collection[[]T] {
+	return &SeqTrie[T, V]{trie.root.clone(), trie.size}
+}
+
+// --- Methods from Map[[]T, V] ---
+func (trie *SeqTrie[T, V]) Put(key []T, value V) {
+	if trie.root.put(key, value) {
+		trie.size++
+	}
+}
+
+func (trie *SeqTrie[T, V]) Get(key []T) (*V, error) {
+	n := trie.root.find(key)
+	if n == nil || !n.full {
+		return nil, errs.NotFound(key)
+	}
+	return &n.value, nil
+}
+
+func (trie *SeqTrie[T, V]) Remove(key []T) (*V, error) {
+	if trie.Empty() {
+		return nil, errs.Empty()
+	}
+	value, ok := trie.root.remove(key)
+	if !ok {
+		return nil, errs.NotFound(key)
+	}
+	trie.size--
+	return &value, nil
+}
+
+func (trie *SeqTrie[T, V]) HasKey(key []T) bool {
+	n := trie.root.find(key)
+	return n != nil && n.full
+}
+
+// Iterates over the keys in lexicographic order
+func (trie *SeqTrie[T, V]) Keys() tau.Iterator[[]T] {
+	return &keyIter[T, V]{newEntryIter(trie.root, nil)}
+}
+
+// Iterates over the values in the lexicographic order of their keys
+func (trie *SeqTrie[T, V]) Values() tau.Iterator[V] {
+	return &valueIter[T, V]{newEntryIter(trie.root, nil)}
+}
+
+// --- Prefix queries ---
+
+// Returns the longest key that is a prefix of the given one,
+// along with its value
+// Returns an error if no key is a prefix of the given one
+func (trie *SeqTrie[T, V]) LongestPrefixOf(key []T) ([]T, *V, error) {
+	n, length := trie.root.longestPrefix(key)
+	if n == nil {
+		return nil, nil, errs.NotFound(key)
+	}
+	prefix := make([]T, length)
+	copy(prefix, key)
+	return prefix, &n.value, nil
+}
+
+// Iterates, in lexicographic order, over the keys starting with the given prefix
+func (trie *SeqTrie[T, V]) KeysWithPrefix(prefix []T) tau.Iterator[[]T] {
+	n, path := trie.root.findPrefix(prefix)
+	return &keyIter[T, V]{newEntryIter(n, path)}
+}
+
+// Calls the given function on all the entries whose key starts with the
+// given prefix, in lexicographic order, until the function returns false
+func (trie *SeqTrie[T, V]) WalkPrefix(prefix []T, f func([]T, V) bool) {
+	n, path := trie.root.findPrefix(prefix)
+	iter := newEntryIter(n, path)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !f(next.key, next.value) {
+			return
+		}
+	}
+}
+
+// Removes all the keys starting with the given prefix
+// and returns how many they were
+func (trie *SeqTrie[T, V]) DeletePrefix(prefix []T) int {
+	count := trie.root.removePrefix(prefix)
+	trie.size -= count
+	return count
+}
+
+// --- Node ---
+type node[T any, V any] struct {
+	// label of the edge from the parent
+	label []T
+	// true if the node holds a value, i.e. it's the end of a key
+	full  bool
+	value V
+	// ordered by the first element of their labels
+	children []*node[T, V]
+}
+
+// returns the node whose path is exactly the given key, if any
+func (n *node[T, V]) find(key []T) *node[T, V] {
+	for len(key) > 0 {
+		_, child := n.child(key[0])
+		if child == nil || !hasPrefix(key, child.label) {
+			return nil
+		}
+		key = key[len(child.label):]
+		n = child
+	}
+	return n
+}
+
+// returns the highest node whose path starts with the given prefix,
+// along with its path, or nil if there's no such node
+func (n *node[T, V]) findPrefix(prefix []T) (*node[T, V], []T) {
+	path := make([]T, 0, len(prefix))
+	for len(prefix) > 0 {
+		_, child := n.child(prefix[0])
+		if child == nil {
+			return nil, nil
+		}
+		common := commonPrefix(prefix, child.label)
+		if common < len(prefix) && common < len(child.label) {
+			return nil, nil
+		}
+		path = append(path, child.label...)
+		prefix = prefix[min(common, len(prefix)):]
+		n = child
+	}
+	return n, path
+}
+
+// returns the deepest full node whose path is a prefix of the given key,
+// along with the length of the path
+func (n *node[T, V]) longestPrefix(key []T) (*node[T, V], int) {
+	var found *node[T, V]
+	length, depth := 0, 0
+	if n.full {
+		found = n
+	}
+	for depth < len(key) {
+		_, child := n.child(key[depth])
+		if child == nil || !hasPrefix(key[depth:], child.label) {
+			break
+		}
+		depth += len(child.label)
+		n = child
+		if n.full {
+			found = n
+			length = depth
+		}
+	}
+	return found, length
+}
+
+// returns true if a new key was added
+func (n *node[T, V]) put(key []T, value V) bool {
+	for len(key) > 0 {
+		index, child := n.child(key[0])
+		if child == nil {
+			label := make([]T, len(key))
+			copy(label, key)
+			n.insertChild(index, &node[T, V]{label: label, full: true, value: value})
+			return true
+		}
+		common := commonPrefix(key, child.label)
+		if common < len(child.label) {
+			// split the edge
+			mid := &node[T, V]{label: child.label[:common:common]}
+			child.label = child.label[common:]
+			mid.children = []*node[T, V]{child}
+			n.children[index] = mid
+			child = mid
+		}
+		key = key[common:]
+		n = child
+	}
+	added := !n.full
+	n.full = true
+	n.value = value
+	return added
+}
+
+// returns the removed value and true if the key was found
+func (n *node[T, V]) remove(key []T) (V, bool) {
+	var zero V
+	if len(key) == 0 {
+		if !n.full {
+			return zero, false
+		}
+		value := n.value
+		n.full = false
+		n.value = zero
+		return value, true
+	}
+	index, child := n.child(key[0])
+	if child == nil || !hasPrefix(key, child.label) {
+		return zero, false
+	}
+	value, ok := child.remove(key[len(child.label):])
+	if ok {
+		n.compact(index)
+	}
+	return value, ok
+}
+
+// returns the number of removed keys
+func (n *node[T, V]) removePrefix(prefix []T) int {
+	if len(prefix) == 0 {
+		count := n.count()
+		var zero V
+		n.full = false
+		n.value = zero
+		n.children = nil
+		return count
+	}
+	index, child := n.child(prefix[0])
+	if child == nil {
+		return 0
+	}
+	common := commonPrefix(prefix, child.label)
+	if common == len(prefix) {
+		// the whole subtree starts with the prefix
+		count := child.count()
+		n.children = append(n.children[:index], n.children[index+1:]...)
+		return count
+	}
+	if common < len(child.label) {
+		return 0
+	}
+	count := child.removePrefix(prefix[common:])
+	if count > 0 {
+		n.compact(index)
+	}
+	return count
+}
+
+// drops or merges the child at the given index if it's no longer needed
+func (n *node[T, V]) compact(index int) {
+	child := n.children[index]
+	if child.full {
+		return
+	}
+	switch len(child.children) {
+	case 0:
+		n.children = append(n.children[:index], n.children[index+1:]...)
+	case 1:
+		grandchild := child.children[0]
+		label := make([]T, 0, len(child.label)+len(grandchild.label))
+		label = append(label, child.label...)
+		grandchild.label = append(label, grandchild.label...)
+		n.children[index] = grandchild
+	}
+}
+
+// binary search of the child whose label starts with the given element
+// if not found, the index is where such a child should be inserted
+func (n *node[T, V]) child(first T) (int, *node[T, V]) {
+	low, high := 0, len(n.children)
+	for low < high {
+		mid := (low + high) / 2
+		cmp := tau.Cmp(n.children[mid].label[0], first)
+		if cmp == 0 {
+			return mid, n.children[mid]
+		} else if cmp < 0 {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+func (n *node[T, V]) insertChild(index int, child *node[T, V]) {
+	n.children = append(n.children, nil)
+	copy(n.children[index+1:], n.children[index:])
+	n.children[index] = child
+}
+
+func (n *node[T, V]) count() int {
+	count := 0
+	if n.full {
+		count++
+	}
+	for _, child := range n.children {
+		count += child.count()
+	}
+	return count
+}
+
+func (n *node[T, V]) clone() *node[T, V] {
+	clone := &node[T, V]{label: n.label, full: n.full, value: n.value}
+	clone.children = make([]*node[T, V], len(n.children))
+	for i, child := range n.children {
+		clone.children[i] = child.clone()
+	}
+	return clone
+}
+
+// --- Iterators ---
+type entry[T any, V any] struct {
+	key   []T
+	value V
+}
+
+type frame[T any, V any] struct {
+	node *node[T, V]
+	path []T
+}
+
+// pre-order traversal, visiting the children in order,
+// yields the keys in lexicographic order
+type entryIter[T any, V any] struct {
+	stack []frame[T, V]
+}
+
+func newEntryIter[T any, V any](root *node[T, V], path []T) *entryIter[T, V] {
+	iter := &entryIter[T, V]{make([]frame[T, V], 0)}
+	if root != nil {
+		iter.stack = append(iter.stack, frame[T, V]{root, path})
+	}
+	return iter
+}
+
+func (iter *entryIter[T, V]) Next() (*entry[T, V], bool) {
+	for len(iter.stack) > 0 {
+		top := iter.stack[len(iter.stack)-1]
+		iter.stack = iter.stack[:len(iter.stack)-1]
+		for i := len(top.node.children) - 1; i >= 0; i-- {
+			child := top.node.children[i]
+			path := make([]T, 0, len(top.path)+len(child.label))
+			path = append(path, top.path...)
+			path = append(path, child.label...)
+			iter.stack = append(iter.stack, frame[T, V]{child, path})
+		}
+		if top.node.full {
+			return &entry[T, V]{top.path, top.node.value}, true
+		}
+	}
+	return nil, false
+}
+
+type keyIter[T any, V any] struct {
+	inner *entryIter[T, V]
+}
+
+func (iter *keyIter[T, V]) Next() (*[]T, bool) {
+	next, ok := iter.inner.Next()
+	if !ok {
+		return nil, false
+	}
+	return &next.key, true
+}
+
+func (iter *keyIter[T, V]) Each(f func([]T)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+type valueIter[T any, V any] struct {
+	inner *entryIter[T, V]
+}
+
+func (iter *valueIter[T, V]) Next() (*V, bool) {
+	next, ok := iter.inner.Next()
+	if !ok {
+		return nil, false
+	}
+	return &next.value, true
+}
+
+func (iter *valueIter[T, V]) Each(f func(V)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
+
+// --- Private helpers ---
+func commonPrefix[T any](a, b []T) int {
+	i := 0
+	for i < len(a) && i < len(b) && tau.Eq(a[i], b[i]) {
+		i++
+	}
+	return i
+}
+
+func hasPrefix[T any](seq, prefix []T) bool {
+	return len(seq) >= len(prefix) && commonPrefix(seq, prefix) == len(prefix)
+}
+
+// lexicographic comparison
+func cmpSeq[T any](a, b []T) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if cmp := tau.Cmp(a[i], b[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return tau.Cmp(len(a), len(b))
+}
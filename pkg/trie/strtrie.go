@@ -0,0 +1,173 @@
+package trie
+
+import (
+	"fmt"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// Radix tree keyed by strings
+//
+// Strings are split into bytes, so keys are iterated in the same
+// order given by [tau.ASCmp] on strings.
+type StrTrie[V any] struct {
+	inner *SeqTrie[byte, V]
+}
+
+// Creates a new radix tree keyed by strings
+func Str[V any]() *StrTrie[V] {
+	return &StrTrie[V]{Seq[byte, V]()}
+}
+
+// --- Methods from Collection[string] ---
+func (trie *StrTrie[V]) String() string {
+	s := "StrTrie{"
+	first := true
+	iter := newEntryIter(trie.inner.root, nil)
+	for next, ok := iter.Next(); ok; next, ok = iter.Next() {
+		if !first {
+			s += ","
+		}
+		first = false
+		s += fmt.Sprintf("(%s: %v)", next.key, next.value)
+	}
+	s += "}"
+	return s
+}
+
+func (trie *StrTrie[V]) Cmp(other any) int {
+	return tau.CollCmp[string](trie, other)
+}
+
+func (trie *StrTrie[V]) Iter() tau.Iterator[string] {
+	return trie.Keys()
+}
+
+func (trie *StrTrie[V]) Size() int {
+	return trie.inner.Size()
+}
+
+func (trie *StrTrie[V]) Empty() bool {
+	return trie.inner.Empty()
+}
+
+func (trie *StrTrie[V]) Clear() {
+	trie.inner.Clear()
+}
+
+func (trie *StrTrie[V]) Contains(key string) bool {
+	return trie.inner.HasKey([]byte(key))
+}
+
+func (trie *StrTrie[V]) ContainsAll(other tau.Collection[string]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if !trie.Contains(*data) {
+			return false
+		}
+	}
+	return true
+}
+
+func (trie *StrTrie[V]) ContainsAny(other tau.Collection[string]) bool {
+	iter := other.Iter()
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		if trie.Contains(*data) {
+			return true
+		}
+	}
+	return false
+}
+
+func (trie *StrTrie[V]) Clone() tau.Collection[string] {
+	return &StrTrie[V]{trie.inner.Clone().(*SeqTrie[byte, V])}
+}
+
+// --- Methods from Map[string, V] ---
+func (trie *StrTrie[V]) Put(key string, value V) {
+	trie.inner.Put([]byte(key), value)
+}
+
+func (trie *StrTrie[V]) Get(key string) (*V, error) {
+	value, err := trie.inner.Get([]byte(key))
+	if err != nil {
+		return nil, errs.NotFound(key)
+	}
+	return value, nil
+}
+
+func (trie *StrTrie[V]) Remove(key string) (*V, error) {
+	value, err := trie.inner.Remove([]byte(key))
+	if _, ok := err.(errs.NotFoundErr); ok {
+		return nil, errs.NotFound(key)
+	}
+	return value, err
+}
+
+func (trie *StrTrie[V]) HasKey(key string) bool {
+	return trie.Contains(key)
+}
+
+// Iterates over the keys in lexicographic order
+func (trie *StrTrie[V]) Keys() tau.Iterator[string] {
+	return &strKeyIter[V]{newEntryIter(trie.inner.root, nil)}
+}
+
+// Iterates over the values in the lexicographic order of their keys
+func (trie *StrTrie[V]) Values() tau.Iterator[V] {
+	return trie.inner.Values()
+}
+
+// --- Prefix queries ---
+
+// Returns the longest key that is a prefix of the given one,
+// along with its value
+// Returns an error if no key is a prefix of the given one
+func (trie *StrTrie[V]) LongestPrefixOf(key string) (string, *V, error) {
+	n, length := trie.inner.root.longestPrefix([]byte(key))
+	if n == nil {
+		return "", nil, errs.NotFound(key)
+	}
+	return key[:length], &n.value, nil
+}
+
+// Iterates, in lexicographic order, over the keys starting with the given prefix
+func (trie *StrTrie[V]) KeysWithPrefix(prefix string) tau.Iterator[string] {
+	n, path := trie.inner.root.findPrefix([]byte(prefix))
+	return &strKeyIter[V]{newEntryIter(n, path)}
+}
+
+// Calls the given function on all the entries whose key starts with the
+// given prefix, in lexicographic order, until the function returns false
+func (trie *StrTrie[V]) WalkPrefix(prefix string, f func(string, V) bool) {
+	trie.inner.WalkPrefix([]byte(prefix), func(key []byte, value V) bool {
+		return f(string(key), value)
+	})
+}
+
+// Removes all the keys starting with the given prefix
+// and returns how many they were
+func (trie *StrTrie[V]) DeletePrefix(prefix string) int {
+	return trie.inner.DeletePrefix([]byte(prefix))
+}
+
+// --- Iterator ---
+type strKeyIter[V any] struct {
+	inner *entryIter[byte, V]
+}
+
+func (iter *strKeyIter[V]) Next() (*string, bool) {
+	next, ok := iter.inner.Next()
+	if !ok {
+		return nil, false
+	}
+	key := string(next.key)
+	return &key, true
+}
+
+func (iter *strKeyIter[V]) Each(f func(string)) {
+	for data, ok := iter.Next(); ok; data, ok = iter.Next() {
+		f(*data)
+	}
+}
@@ -0,0 +1,109 @@
+package algo_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// collects the values of the generator as strings
+func generated[T any](iter tau.Iterator[tau.List[T]]) []string {
+	result := make([]string, 0)
+	iter.Each(func(l tau.List[T]) {
+		result = append(result, fmt.Sprint(toSlice[T](l)))
+	})
+	return result
+}
+
+func TestPermutations(t *testing.T) {
+	perms := generated(algo.Permutations[int](list.Arr(1, 2, 3, 4)))
+	if len(perms) != 24 {
+		t.Fatalf("Permutations() generated %d values, want 24", len(perms))
+	}
+	if perms[0] != "[1 2 3 4]" {
+		t.Errorf("Permutations() starts with %s, want the list itself", perms[0])
+	}
+	sorted := slices.Clone(perms)
+	slices.Sort(sorted)
+	if len(slices.Compact(sorted)) != 24 {
+		t.Errorf("Permutations() generated duplicates")
+	}
+	if empty := generated(algo.Permutations[int](list.Arr[int]())); !slices.Equal(empty, []string{"[]"}) {
+		t.Errorf("Permutations() of empty list = %v, want [[]]", empty)
+	}
+
+	// every value is a fresh list
+	iter := algo.Permutations[int](list.Lkd(1, 2))
+	first, _ := iter.Next()
+	second, _ := iter.Next()
+	(*first).Append(9)
+	if (*second).Size() != 2 {
+		t.Errorf("Permutations() values share their elements")
+	}
+}
+
+func TestNextPermutation(t *testing.T) {
+	coll := list.Arr(1, 1, 2)
+	perms := []string{fmt.Sprint(toSlice[int](coll))}
+	for algo.NextPermutation[int](coll, tau.ASCmp) {
+		perms = append(perms, fmt.Sprint(toSlice[int](coll)))
+	}
+	expected := []string{"[1 1 2]", "[1 2 1]", "[2 1 1]"}
+	if !slices.Equal(perms, expected) {
+		t.Errorf("NextPermutation() goes through %v, want %v", perms, expected)
+	}
+	if !tau.Eq(coll, list.Arr(1, 1, 2)) {
+		t.Errorf("NextPermutation() after the last one = %v, want [1 1 2]", coll)
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	letters := list.Arr("a", "b", "c", "d")
+	cases := []struct {
+		name     string
+		iter     tau.Iterator[tau.List[string]]
+		expected []string
+	}{
+		{"Combinations(2)", algo.Combinations[string](letters, 2),
+			[]string{"[a b]", "[a c]", "[a d]", "[b c]", "[b d]", "[c d]"}},
+		{"Combinations(0)", algo.Combinations[string](letters, 0), []string{"[]"}},
+		{"Combinations(5)", algo.Combinations[string](letters, 5), []string{}},
+		{"CombinationsWithReplacement(2)", algo.CombinationsWithReplacement[string](list.Arr("a", "b", "c"), 2),
+			[]string{"[a a]", "[a b]", "[a c]", "[b b]", "[b c]", "[c c]"}},
+		{"CombinationsWithReplacement(1) of empty list", algo.CombinationsWithReplacement[string](list.Arr[string](), 1),
+			[]string{}},
+		{"PowerSet", algo.PowerSet[string](list.Arr("a", "b", "c")),
+			[]string{"[]", "[a]", "[b]", "[c]", "[a b]", "[a c]", "[b c]", "[a b c]"}},
+		{"CartesianProduct", algo.CartesianProduct[string](list.Arr("a", "b"), list.Lkd("x"), list.Arr("1", "2")),
+			[]string{"[a x 1]", "[a x 2]", "[b x 1]", "[b x 2]"}},
+		{"CartesianProduct with empty list", algo.CartesianProduct[string](letters, list.Arr[string]()), []string{}},
+	}
+	for _, c := range cases {
+		if values := generated(c.iter); !slices.Equal(values, c.expected) {
+			t.Errorf("%s = %v, want %v", c.name, values, c.expected)
+		}
+	}
+	if n := len(generated(algo.Combinations[int](list.Arr(0, 1, 2, 3, 4, 5, 6, 7, 8, 9), 4))); n != 210 {
+		t.Errorf("Combinations(4) of 10 elements generated %d values, want 210", n)
+	}
+}
+
+func TestPartitions(t *testing.T) {
+	expected := []string{"[5]", "[4 1]", "[3 2]", "[3 1 1]", "[2 2 1]", "[2 1 1 1]", "[1 1 1 1 1]"}
+	if parts := generated(algo.Partitions(5, list.Arr[int]())); !slices.Equal(parts, expected) {
+		t.Errorf("Partitions(5) = %v, want %v", parts, expected)
+	}
+	// number of partitions, from OEIS A000041
+	for n, count := range []int{1, 1, 2, 3, 5, 7, 11, 15, 22, 30, 42, 56, 77, 101, 135} {
+		if parts := generated(algo.Partitions(n, list.Lkd[int]())); len(parts) != count {
+			t.Errorf("Partitions(%d) generated %d values, want %d", n, len(parts), count)
+		}
+	}
+	if parts := generated(algo.Partitions(-1, list.Arr[int]())); len(parts) != 0 {
+		t.Errorf("Partitions(-1) = %v, want none", parts)
+	}
+}
@@ -0,0 +1,163 @@
+package algo_test
+
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// number of inserted and deleted elements of the patch
+func patchSize[T any](patch algo.Patch[T]) int {
+	size := 0
+	for _, hunk := range patch.Hunks {
+		if hunk.Op != algo.EditKeep {
+			size += len(hunk.Values)
+		}
+	}
+	return size
+}
+
+func checkPatch(t *testing.T, a, b []string, patch algo.Patch[string]) {
+	patched, err := algo.Apply(patch, list.Arr(a...))
+	if err != nil {
+		t.Fatalf("Apply() returned error %v", err)
+	}
+	if result := toSlice[string](patched); !slices.Equal(result, b) {
+		t.Fatalf("Apply() = %v, want %v", result, b)
+	}
+	restored, err := algo.Apply(algo.Invert(patch), list.Arr(b...))
+	if err != nil {
+		t.Fatalf("Apply() of inverted patch returned error %v", err)
+	}
+	if result := toSlice[string](restored); !slices.Equal(result, a) {
+		t.Fatalf("Apply() of inverted patch = %v, want %v", result, a)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := strings.Split("A B C A B B A", " ")
+	b := strings.Split("C B A B A C", " ")
+	patch := algo.Diff[string](list.Arr(a...), list.Arr(b...))
+	if size := patchSize(patch); size != 5 {
+		t.Errorf("Diff() has %d edits, want the minimum 5", size)
+	}
+	checkPatch(t, a, b, patch)
+
+	same := algo.Diff[string](list.Arr(a...), list.Lkd(a...))
+	if !same.Empty() || same.String() != "" {
+		t.Errorf("Diff() of equal collections = %q", same.String())
+	}
+
+	rnd := rand.New(rand.NewSource(45))
+	for k := 0; k < 200; k++ {
+		x, y := make([]string, rnd.Intn(30)), make([]string, rnd.Intn(30))
+		for i := range x {
+			x[i] = string("abcd"[rnd.Intn(4)])
+		}
+		for i := range y {
+			y[i] = string("abcd"[rnd.Intn(4)])
+		}
+		for _, patience := range []bool{false, true} {
+			patch := algo.DiffWith[string](list.Arr(x...), list.Arr(y...), algo.DiffOptions[string]{Patience: patience})
+			checkPatch(t, x, y, patch)
+			if !patience {
+				common, _ := algo.LongestCommonSubsequence[string](list.Arr(x...), list.Arr(y...))
+				if size := patchSize(patch); size != len(x)+len(y)-2*len(common) {
+					t.Fatalf("Diff(%v, %v) has %d edits, want %d", x, y, size, len(x)+len(y)-2*len(common))
+				}
+			}
+		}
+	}
+}
+
+func TestDiffLongInputs(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	x := make([]string, 50000)
+	for i := range x {
+		x[i] = strconv.Itoa(i)
+	}
+	y := slices.Clone(x)
+	for i := 0; i < 100; i++ {
+		j := rnd.Intn(len(y))
+		y = slices.Delete(y, j, j+1)
+		j = rnd.Intn(len(y))
+		y = slices.Insert(y, j, "new")
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	patch := algo.Diff[string](list.Arr(x...), list.Arr(y...))
+	runtime.ReadMemStats(&after)
+	// a full copy of the diagonals at every step would take hundreds of MB
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 64<<20 {
+		t.Errorf("Diff() of %d elements with %d edits allocated %d bytes", len(x), patchSize(patch), alloc)
+	}
+	if size := patchSize(patch); size > 400 {
+		t.Errorf("Diff() has %d edits, want at most 400", size)
+	}
+	checkPatch(t, x, y, patch)
+}
+
+func TestDiffComparatorAndPatience(t *testing.T) {
+	a := list.Arr("Alpha", "beta", "gamma")
+	b := list.Arr("alpha", "BETA", "delta")
+	ignoreCase := func(x, y string) int { return tau.ASCmp(strings.ToLower(x), strings.ToLower(y)) }
+	patch := algo.DiffWith[string](a, b, algo.DiffOptions[string]{Cmp: ignoreCase})
+	if size := patchSize(patch); size != 2 {
+		t.Errorf("DiffWith() ignoring case has %d edits, want 2", size)
+	}
+
+	// patience matches the unique lines, instead of the frequent braces
+	x := strings.Split("f() { | a | } | g() { | b | }", " | ")
+	y := strings.Split("g() { | b | } | f() { | a | }", " | ")
+	patience := algo.DiffWith[string](list.Arr(x...), list.Arr(y...), algo.DiffOptions[string]{Patience: true})
+	checkPatch(t, x, y, patience)
+	if patience.Hunks[0].Op != algo.EditDelete || len(patience.Hunks[0].Values) != 3 {
+		t.Errorf("patience diff starts with %v, want the deletion of f()", patience.Hunks[0])
+	}
+}
+
+func TestPatchString(t *testing.T) {
+	a := strings.Split("1 2 3 4 5 6 7 8 9 10 11 12 13 14 15", " ")
+	b := slices.Clone(a)
+	b[1] = "two"
+	b = slices.Delete(b, 12, 13)
+	expected := "@@ -1,5 +1,5 @@\n" +
+		" 1\n-2\n+two\n 3\n 4\n 5\n" +
+		"@@ -10,6 +10,5 @@\n" +
+		" 10\n 11\n 12\n-13\n 14\n 15\n"
+	patch := algo.Diff[string](list.Arr(a...), list.Arr(b...))
+	if s := patch.String(); s != expected {
+		t.Errorf("Patch.String() =\n%s\nwant\n%s", s, expected)
+	}
+	if _, err := algo.Apply(patch, list.Arr(b...)); err == nil {
+		t.Errorf("Apply() to the wrong list returned no error")
+	}
+}
+
+// record, from the sorting tests, is a plain struct and not tau.Comparable
+func TestApplyWithComparator(t *testing.T) {
+	byID := func(a, b record) int { return tau.ASCmp(a.key, b.key) }
+	a := list.Arr(record{1, 10}, record{2, 20}, record{3, 30})
+	b := list.Arr(record{1, 10}, record{3, 30}, record{4, 40})
+	patch := algo.DiffWith[record](a, b, algo.DiffOptions[record]{Cmp: byID})
+	patched, err := algo.Apply(patch, a)
+	if err != nil || patched.Size() != 3 || tau.CollCmpBy[record](patched, b, byID) != 0 {
+		t.Fatalf("Apply() of a patch of records = %v, %v, want %v", patched, err, b)
+	}
+	restored, err := algo.Apply(algo.Invert(patch), b)
+	if err != nil || tau.CollCmpBy[record](restored, a, byID) != 0 {
+		t.Errorf("Apply() of an inverted patch of records = %v, %v, want %v", restored, err, a)
+	}
+	// the comparator can be given explicitly too, e.g. for a patch built by hand
+	patch.Cmp = nil
+	if _, err := algo.ApplyWith(patch, b, byID); err == nil {
+		t.Errorf("ApplyWith() to the wrong list returned no error")
+	}
+}
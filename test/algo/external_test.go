@@ -0,0 +1,129 @@
+package algo_test
+
+import (
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+// exported fields, so that it can be encoded with gob
+type entry struct {
+	Key int
+	Seq int
+}
+
+func collect[T any](t *testing.T, iter *algo.ExternalIterator[T]) []T {
+	result := make([]T, 0)
+	iter.Each(func(value T) { result = append(result, value) })
+	if err := iter.Err(); err != nil {
+		t.Fatalf("ExternalSort iteration failed: %v", err)
+	}
+	return result
+}
+
+func tempFiles(t *testing.T, dir string) int {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestExternalSortMatchesMergeSort(t *testing.T) {
+	rnd := rand.New(rand.NewSource(43))
+	data := make([]entry, 10000)
+	for i := range data {
+		data[i] = entry{rnd.Intn(300), i}
+	}
+	byKey := func(a, b entry) int { return a.Key - b.Key }
+	expected := toSlice(algo.MergeSort[entry](list.Arr(data...), byKey))
+
+	for _, opts := range []algo.ExternalOptions[entry]{
+		{RunSize: 100000},
+		{RunSize: 1000},
+		{RunSize: 97, FanIn: 4},
+		{RunSize: 1, FanIn: 2},
+	} {
+		dir := t.TempDir()
+		opts.TempDir = dir
+		iter, err := algo.ExternalSort[entry](list.Arr(data...).Iter(), byKey, opts)
+		if err != nil {
+			t.Fatalf("ExternalSort(RunSize %d) returned error %v", opts.RunSize, err)
+		}
+		if sorted := collect(t, iter); !slices.Equal(sorted, expected) {
+			t.Errorf("ExternalSort(RunSize %d, FanIn %d) differs from MergeSort", opts.RunSize, opts.FanIn)
+		}
+		if n := tempFiles(t, dir); n != 0 {
+			t.Errorf("ExternalSort(RunSize %d) left %d temporary files", opts.RunSize, n)
+		}
+	}
+}
+
+func TestExternalSortLineCodec(t *testing.T) {
+	words := []string{"pear", "apple", "", "fig", "banana", "apple", "kiwi", "cherry"}
+	opts := algo.ExternalOptions[string]{Codec: algo.LineCodec[string](), RunSize: 3, TempDir: t.TempDir()}
+	iter, err := algo.ExternalSort[string](list.Lkd(words...).Iter(), tau.DSCmp, opts)
+	if err != nil {
+		t.Fatalf("ExternalSort() returned error %v", err)
+	}
+	expected := slices.Clone(words)
+	slices.Sort(expected)
+	slices.Reverse(expected)
+	if sorted := collect(t, iter); !slices.Equal(sorted, expected) {
+		t.Errorf("ExternalSort() = %q, want %q", sorted, expected)
+	}
+}
+
+func TestExternalSortClose(t *testing.T) {
+	dir := t.TempDir()
+	data := make([]int, 500)
+	for i := range data {
+		data[i] = len(data) - i
+	}
+	opts := algo.ExternalOptions[int]{RunSize: 50, TempDir: dir}
+	iter, err := algo.ExternalSort[int](list.Arr(data...).Iter(), tau.ASCmp, opts)
+	if err != nil {
+		t.Fatalf("ExternalSort() returned error %v", err)
+	}
+	if tempFiles(t, dir) == 0 {
+		t.Fatalf("ExternalSort() wrote no temporary files")
+	}
+	for i := 1; i <= 10; i++ {
+		if next, ok := iter.Next(); !ok || *next != i {
+			t.Fatalf("ExternalSort() value %d = %v, want %d", i, next, i)
+		}
+	}
+	if err := iter.Close(); err != nil {
+		t.Fatalf("Close() returned error %v", err)
+	}
+	if err := iter.Close(); err != nil {
+		t.Errorf("second Close() returned error %v", err)
+	}
+	if n := tempFiles(t, dir); n != 0 {
+		t.Errorf("Close() left %d temporary files", n)
+	}
+	if _, ok := iter.Next(); ok {
+		t.Errorf("Next() after Close() returned a value")
+	}
+
+	empty, err := algo.ExternalSort[int](list.Arr[int]().Iter(), tau.ASCmp, opts)
+	if err != nil {
+		t.Fatalf("ExternalSort() of empty iterator returned error %v", err)
+	}
+	if _, ok := empty.Next(); ok {
+		t.Errorf("ExternalSort() of empty iterator returned a value")
+	}
+}
+
+func TestExternalSortBadTempDir(t *testing.T) {
+	opts := algo.ExternalOptions[int]{RunSize: 2, TempDir: t.TempDir() + "/missing"}
+	_, err := algo.ExternalSort[int](list.Arr(3, 2, 1).Iter(), tau.ASCmp, opts)
+	if err == nil {
+		t.Errorf("ExternalSort() with missing temporary directory returned no error")
+	}
+}
@@ -0,0 +1,92 @@
+package algo_test
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func TestParallelSort(t *testing.T) {
+	rnd := rand.New(rand.NewSource(40))
+	data := make([]record, 100000)
+	for i := range data {
+		data[i] = record{rnd.Intn(1000), i}
+	}
+	byKey := func(a, b record) int { return a.key - b.key }
+	expected := slices.Clone(data)
+	slices.SortStableFunc(expected, byKey)
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		sorted, err := algo.ParallelSort[record](context.Background(), list.Arr(data...), byKey, workers)
+		if err != nil {
+			t.Fatalf("ParallelSort() returned error %v", err)
+		}
+		if !slices.Equal(toRecords(sorted), expected) {
+			t.Errorf("ParallelSort() with %d workers differs from the stable sort", workers)
+		}
+	}
+
+	lkd := list.Lkd(data[:20000]...)
+	if err := algo.ParallelSortInPlace[record](context.Background(), lkd, byKey, 4); err != nil {
+		t.Fatalf("ParallelSortInPlace() returned error %v", err)
+	}
+	expectedPrefix := slices.Clone(data[:20000])
+	slices.SortStableFunc(expectedPrefix, byKey)
+	if !slices.Equal(toRecords(lkd), expectedPrefix) {
+		t.Errorf("ParallelSortInPlace() on LkdList differs from the stable sort")
+	}
+}
+
+func TestParallelCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data := list.Arr(5, 3, 1, 4, 2)
+	if err := algo.ParallelSortInPlace[int](ctx, data, tau.ASCmp, 2); err != context.Canceled {
+		t.Errorf("ParallelSortInPlace() with a cancelled context returned %v", err)
+	}
+	if _, err := algo.Reduce[int, int](ctx, data, 0, func(a, b int) int { return a + b }, func(a, b int) int { return a + b }, 2); err != context.Canceled {
+		t.Errorf("Reduce() with a cancelled context returned %v", err)
+	}
+	if err := algo.MapInPlace[int](ctx, data, func(v int) int { return -v }, 2); err != context.Canceled {
+		t.Errorf("MapInPlace() with a cancelled context returned %v", err)
+	}
+	if !tau.Eq(data, list.Arr(5, 3, 1, 4, 2)) {
+		t.Errorf("cancelled algorithms modified the collection: %v", data)
+	}
+}
+
+func TestReduceAndMap(t *testing.T) {
+	data := make([]int, 50000)
+	for i := range data {
+		data[i] = i
+	}
+	coll := list.Arr(data...)
+	sum, err := algo.Reduce[int, int](context.Background(), coll, 0,
+		func(acc, v int) int { return acc + v }, func(a, b int) int { return a + b }, 8)
+	if err != nil || sum != 50000*49999/2 {
+		t.Errorf("Reduce() = %d, %v, want %d", sum, err, 50000*49999/2)
+	}
+	// concatenation is not commutative, so the chunks must be combined in order
+	expected := ""
+	for _, v := range data[:30000] {
+		expected += string(rune('0' + v%10))
+	}
+	digits, _ := algo.Reduce[int, string](context.Background(), list.Arr(data[:30000]...), "",
+		func(acc string, v int) string { return acc + string(rune('0'+v%10)) },
+		func(a, b string) string { return a + b }, 3)
+	if digits != expected {
+		t.Errorf("Reduce() does not combine the chunks in order")
+	}
+
+	if err := algo.MapInPlace[int](context.Background(), coll, func(v int) int { return v * 2 }, 8); err != nil {
+		t.Fatalf("MapInPlace() returned error %v", err)
+	}
+	if v, _ := coll.Get(12345); *v != 24690 {
+		t.Errorf("MapInPlace() gives %d at 12345, want 24690", *v)
+	}
+}
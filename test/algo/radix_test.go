@@ -0,0 +1,129 @@
+package algo_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func TestRadixSort(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	ints := make([]int64, 5000)
+	for i := range ints {
+		ints[i] = rnd.Int63() - rnd.Int63()
+	}
+	ints[0], ints[1] = math.MinInt64, math.MaxInt64
+	expected := slices.Clone(ints)
+	slices.Sort(expected)
+	if sorted := algo.RadixSort[int64](list.Arr(ints...)); !slices.Equal(toSlice(sorted), expected) {
+		t.Errorf("RadixSort() of int64 is not sorted")
+	}
+
+	small := list.Lkd[int8](3, -1, 127, -128, 0, -1)
+	algo.RadixSortInPlace[int8](small)
+	if !tau.Eq(small, list.Lkd[int8](-128, -1, -1, 0, 3, 127)) {
+		t.Errorf("RadixSortInPlace() of int8 = %v", small)
+	}
+	unsigned := algo.RadixSort[uint16](list.Arr[uint16](65535, 256, 1, 255, 0))
+	if !tau.Eq(unsigned, list.Arr[uint16](0, 1, 255, 256, 65535)) {
+		t.Errorf("RadixSort() of uint16 = %v", unsigned)
+	}
+}
+
+func TestRadixSortBy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	data := make([]record, 3000)
+	for i := range data {
+		data[i] = record{rnd.Intn(600) - 300, i}
+	}
+	expected := slices.Clone(data)
+	slices.SortStableFunc(expected, func(a, b record) int { return a.key - b.key })
+	sorted := algo.RadixSortBy[record](list.Arr(data...), func(r record) int { return r.key })
+	if !slices.Equal(toRecords(sorted), expected) {
+		t.Errorf("RadixSortBy() is not a stable sort by key")
+	}
+}
+
+func TestRadixSortStrings(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	words := make([]string, 2000)
+	for i := range words {
+		b := make([]byte, rnd.Intn(6))
+		for j := range b {
+			b[j] = "ab\xff"[rnd.Intn(3)]
+		}
+		words[i] = string(b)
+	}
+	expected := slices.Clone(words)
+	slices.Sort(expected)
+	if sorted := algo.RadixSortStrings[string](list.Arr(words...)); !slices.Equal(toSlice(sorted), expected) {
+		t.Errorf("RadixSortStrings() is not sorted")
+	}
+}
+
+func TestCountingAndBucketSort(t *testing.T) {
+	data := list.Arr(5, -3, 5, 0, 2, -3, 9)
+	if sorted := algo.CountingSort[int](data); !tau.Eq(sorted, list.Arr(-3, -3, 0, 2, 5, 5, 9)) {
+		t.Errorf("CountingSort() = %v", sorted)
+	}
+	rnd := rand.New(rand.NewSource(42))
+	floats := make([]float64, 2000)
+	for i := range floats {
+		floats[i] = rnd.NormFloat64() * 100
+	}
+	floats[7] = math.NaN()
+	sorted := toSlice(algo.BucketSort[float64](list.Arr(floats...)))
+	if !math.IsNaN(sorted[0]) || !slices.IsSorted(sorted[1:]) {
+		t.Errorf("BucketSort() is not sorted")
+	}
+	same := list.Arr(2.5, 2.5, 2.5)
+	algo.BucketSortInPlace[float64](same)
+	if !tau.Eq(same, list.Arr(2.5, 2.5, 2.5)) {
+		t.Errorf("BucketSortInPlace() of equal values = %v", same)
+	}
+}
+
+func TestSortsWithExtremeValues(t *testing.T) {
+	inf := list.Arr(3.0, math.Inf(1), 1.0, math.NaN(), math.Inf(-1), 2.0)
+	sorted := toSlice(algo.BucketSort[float64](inf))
+	if !math.IsNaN(sorted[0]) || !slices.Equal(sorted[1:], []float64{math.Inf(-1), 1, 2, 3, math.Inf(1)}) {
+		t.Errorf("BucketSort() with infinities = %v", sorted)
+	}
+	wide := list.Arr(math.MaxFloat64, 0.5, -math.MaxFloat64, 0.25)
+	if sorted := algo.BucketSort[float64](wide); !tau.Eq(sorted, list.Arr(-math.MaxFloat64, 0.25, 0.5, math.MaxFloat64)) {
+		t.Errorf("BucketSort() of an overflowing range = %v", sorted)
+	}
+	ints := list.Arr(math.MaxInt64, 3, math.MinInt64, -1, 0)
+	if sorted := algo.CountingSort[int](ints); !tau.Eq(sorted, list.Arr(math.MinInt64, -1, 0, 3, math.MaxInt64)) {
+		t.Errorf("CountingSort() of a huge range = %v", sorted)
+	}
+}
+
+func toSlice[T any](coll tau.Collection[T]) []T {
+	res := make([]T, 0, coll.Size())
+	coll.Iter().Each(func(v T) {
+		res = append(res, v)
+	})
+	return res
+}
+
+func BenchmarkRadixSort(b *testing.B) {
+	benchSort(b, func(coll tau.IdxedColl[int], _ tau.Comparator[int]) { algo.RadixSortInPlace(coll) })
+}
+
+func BenchmarkCountingSort(b *testing.B) {
+	benchSort(b, func(coll tau.IdxedColl[int], _ tau.Comparator[int]) {
+		algo.MapInPlace(context.Background(), coll, func(v int) int { return v % 100000 }, 1)
+		algo.CountingSortInPlace(coll)
+	})
+}
+
+func BenchmarkBucketSort(b *testing.B) {
+	benchSort(b, func(coll tau.IdxedColl[int], _ tau.Comparator[int]) { algo.BucketSortInPlace(coll) })
+}
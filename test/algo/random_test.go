@@ -0,0 +1,134 @@
+package algo_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+)
+
+func TestShuffle(t *testing.T) {
+	shuffled := func(seed uint64) []int {
+		coll := list.Arr(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+		algo.Shuffle[int](coll, rand.New(rand.NewPCG(seed, 47)))
+		return toSlice[int](coll)
+	}
+	first := shuffled(1)
+	if !slices.Equal(first, shuffled(1)) {
+		t.Errorf("Shuffle() with the same seed gave different results")
+	}
+	if slices.Equal(first, shuffled(2)) {
+		t.Errorf("Shuffle() with different seeds gave the same result")
+	}
+	sorted := slices.Clone(first)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("Shuffle() = %v, not a permutation", first)
+	}
+
+	// every element ends up in every position with about the same frequency
+	r := rand.New(rand.NewPCG(3, 47))
+	counts := [3][3]int{}
+	for i := 0; i < 9000; i++ {
+		coll := list.Lkd(0, 1, 2)
+		algo.Shuffle[int](coll, r)
+		for pos, v := range toSlice[int](coll) {
+			counts[v][pos]++
+		}
+	}
+	for v := range counts {
+		for pos, count := range counts[v] {
+			if count < 2700 || count > 3300 {
+				t.Errorf("Shuffle() put %d at %d %d times out of 9000", v, pos, count)
+			}
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	r := rand.New(rand.NewPCG(4, 47))
+	coll := list.Arr(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	counts := make([]int, coll.Size())
+	for i := 0; i < 2000; i++ {
+		sample, err := algo.Sample[int](coll, 3, r)
+		if err != nil {
+			t.Fatalf("Sample() returned error %v", err)
+		}
+		if len(sample) != 3 || sample[0] == sample[1] || sample[0] == sample[2] || sample[1] == sample[2] {
+			t.Fatalf("Sample() = %v, want 3 distinct elements", sample)
+		}
+		for _, v := range sample {
+			counts[v]++
+		}
+	}
+	for v, count := range counts {
+		if count < 500 || count > 700 {
+			t.Errorf("Sample() picked %d %d times out of 6000, want about 600", v, count)
+		}
+	}
+	if !hasValues(coll, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9) {
+		t.Errorf("Sample() modified the collection")
+	}
+	if _, err := algo.Sample[int](coll, 11, r); err == nil {
+		t.Errorf("Sample() of more elements than the size returned no error")
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 47))
+	counts := make([]int, 20)
+	for i := 0; i < 4000; i++ {
+		for _, v := range algo.Reservoir[int](list.Arr(seq(20)...).Iter(), 5, r) {
+			counts[v]++
+		}
+	}
+	for v, count := range counts {
+		if count < 850 || count > 1150 {
+			t.Errorf("Reservoir() picked %d %d times out of 20000, want about 1000", v, count)
+		}
+	}
+	if short := algo.Reservoir[int](list.Arr(1, 2).Iter(), 5, r); len(short) != 2 {
+		t.Errorf("Reservoir() of a short iterator = %v, want all of it", short)
+	}
+}
+
+func TestAliasSampler(t *testing.T) {
+	weights := []float64{1, 0, 3, 6}
+	sampler := algo.Alias[string](list.Arr("a", "b", "c", "d"), weights)
+	r := rand.New(rand.NewPCG(6, 47))
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[sampler.Sample(r)]++
+	}
+	for i, name := range []string{"a", "b", "c", "d"} {
+		expected := int(weights[i] * 1000)
+		if counts[name] < expected*9/10 || counts[name] > expected*11/10 {
+			t.Errorf("AliasSampler picked %s %d times out of 10000, want about %d", name, counts[name], expected)
+		}
+	}
+
+	for _, weights := range [][]float64{{1, 2}, {1, -1, 1}, {0, 0, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Alias() with weights %v did not panic", weights)
+				}
+			}()
+			algo.Alias[int](list.Arr(1, 2, 3), weights)
+		}()
+	}
+}
+
+func seq(n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i
+	}
+	return values
+}
+
+func hasValues(coll *list.ArrList[int], values ...int) bool {
+	return slices.Equal(toSlice[int](coll), values)
+}
@@ -0,0 +1,82 @@
+package algo_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func TestBinarySearch(t *testing.T) {
+	for _, coll := range []tau.IdxedColl[int]{sortedData, list.Arr(2, 25, 30, 47, 76, 90, 99, 145, 750)} {
+		if i := algo.BinarySearch(coll, 99, tau.ASCmp); i != 6 {
+			t.Errorf("BinarySearch(99) = %d, want 6", i)
+		}
+		if i := algo.BinarySearch(coll, 100, tau.ASCmp); i != -1 {
+			t.Errorf("BinarySearch(100) = %d, want -1", i)
+		}
+		if i := algo.ExponentialSearch(coll, 2, tau.ASCmp); i != 0 {
+			t.Errorf("ExponentialSearch(2) = %d, want 0", i)
+		}
+		if i := algo.InterpolationSearch(coll, 750); i != 8 {
+			t.Errorf("InterpolationSearch(750) = %d, want 8", i)
+		}
+	}
+	empty := list.Arr[int]()
+	if algo.BinarySearch(empty, 1, tau.ASCmp) != -1 || algo.LowerBound(empty, 1, tau.ASCmp) != 0 {
+		t.Errorf("searching an empty list found something")
+	}
+	if algo.ExponentialSearch(empty, 1, tau.ASCmp) != -1 || algo.InterpolationSearch(empty, 1) != -1 {
+		t.Errorf("searching an empty list found something")
+	}
+}
+
+func TestBoundsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(38))
+	data := make([]int, 300)
+	for i := range data {
+		data[i] = rnd.Intn(100)
+	}
+	slices.Sort(data)
+	arr, lkd := list.Arr(data...), list.Lkd(data...)
+	for value := -1; value <= 100; value++ {
+		lower, _ := slices.BinarySearch(data, value)
+		upper, _ := slices.BinarySearch(data, value+1)
+		for _, coll := range []tau.IdxedColl[int]{arr, lkd} {
+			if l := algo.LowerBound(coll, value, tau.ASCmp); l != lower {
+				t.Fatalf("LowerBound(%d) = %d, want %d", value, l, lower)
+			}
+			if u := algo.UpperBound(coll, value, tau.ASCmp); u != upper {
+				t.Fatalf("UpperBound(%d) = %d, want %d", value, u, upper)
+			}
+			if from, to := algo.EqualRange(coll, value, tau.ASCmp); from != lower || to != upper {
+				t.Fatalf("EqualRange(%d) = [%d, %d), want [%d, %d)", value, from, to, lower, upper)
+			}
+			found := lower < upper
+			if i := algo.ExponentialSearch(coll, value, tau.ASCmp); (i == lower) != found || (!found && i != -1) {
+				t.Fatalf("ExponentialSearch(%d) = %d, want %d", value, i, lower)
+			}
+			if i := algo.BinarySearch(coll, value, tau.ASCmp); found != (i >= lower && i < upper) || (!found && i != -1) {
+				t.Fatalf("BinarySearch(%d) = %d, want in [%d, %d)", value, i, lower, upper)
+			}
+			if i := algo.InterpolationSearch(coll, value); found != (i >= lower && i < upper) || (!found && i != -1) {
+				t.Fatalf("InterpolationSearch(%d) = %d, want in [%d, %d)", value, i, lower, upper)
+			}
+		}
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	if !algo.IsSorted[int](sortedData) || algo.IsSorted[int](startData) {
+		t.Errorf("IsSorted is wrong")
+	}
+	if !algo.IsSortedBy[int](list.Arr(3, 2, 2, 1), tau.DSCmp) || algo.IsSortedBy[int](sortedData, tau.DSCmp) {
+		t.Errorf("IsSortedBy is wrong")
+	}
+	if !algo.IsSorted[string](list.Arr[string]()) {
+		t.Errorf("IsSorted of empty list is false")
+	}
+}
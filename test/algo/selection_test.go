@@ -0,0 +1,80 @@
+package algo_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func TestNthElement(t *testing.T) {
+	rnd := rand.New(rand.NewSource(41))
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = rnd.Intn(200)
+	}
+	sorted := slices.Clone(data)
+	slices.Sort(sorted)
+	for _, n := range []int{0, 1, 17, 500, 950, 999} {
+		coll := list.Arr(data...)
+		if err := algo.NthElement[int](coll, n, tau.ASCmp); err != nil {
+			t.Fatalf("NthElement(%d) returned error %v", n, err)
+		}
+		nth, _ := coll.Get(n)
+		if *nth != sorted[n] {
+			t.Fatalf("NthElement(%d) puts %d, want %d", n, *nth, sorted[n])
+		}
+		for i := 0; i < coll.Size(); i++ {
+			v, _ := coll.Get(i)
+			if (i < n && *v > *nth) || (i > n && *v < *nth) {
+				t.Fatalf("NthElement(%d) leaves %d at index %d", n, *v, i)
+			}
+		}
+	}
+	if err := algo.NthElement[int](list.Arr(1, 2), 2, tau.ASCmp); err == nil {
+		t.Errorf("NthElement() out of range returned no error")
+	}
+}
+
+func TestPartialSortAndMedian(t *testing.T) {
+	coll := list.Lkd(9, 4, 7, 1, 8, 2, 6, 3, 5)
+	if err := algo.PartialSort[int](coll, 4, tau.ASCmp); err != nil {
+		t.Fatalf("PartialSort() returned error %v", err)
+	}
+	if prefix := coll.Slice(0, 4); !tau.Eq(prefix, list.Lkd(1, 2, 3, 4)) {
+		t.Errorf("PartialSort() prefix = %v, want [1 2 3 4]", prefix)
+	}
+	if m, err := algo.Median[int](list.Arr(5, 1, 4, 2, 3), tau.ASCmp); err != nil || m != 3 {
+		t.Errorf("Median() = %d, want 3", m)
+	}
+	if m, _ := algo.Median[int](list.Arr(4, 1, 3, 2), tau.ASCmp); m != 2 {
+		t.Errorf("Median() of even size = %d, want the lower middle 2", m)
+	}
+	if _, err := algo.Median[int](list.Arr[int](), tau.ASCmp); err == nil {
+		t.Errorf("Median() of empty collection returned no error")
+	}
+}
+
+func TestTopK(t *testing.T) {
+	m := table.RB[string, int]()
+	for i, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		m.Put(name, (i*7)%6)
+	}
+	// values are 0, 1, 2, 3, 4, 5 in some order
+	if top := algo.TopK(m.Values(), 3, tau.ASCmp); !slices.Equal(top, []int{5, 4, 3}) {
+		t.Errorf("TopK() = %v, want [5 4 3]", top)
+	}
+	if bottom := algo.BottomK(m.Values(), 2, tau.ASCmp); !slices.Equal(bottom, []int{0, 1}) {
+		t.Errorf("BottomK() = %v, want [0 1]", bottom)
+	}
+	if all := algo.TopK(list.Arr(2, 9).Iter(), 5, tau.ASCmp); !slices.Equal(all, []int{9, 2}) {
+		t.Errorf("TopK() with k over the size = %v, want [9 2]", all)
+	}
+	if none := algo.BottomK(list.Arr(2, 9).Iter(), 0, tau.ASCmp); len(none) != 0 {
+		t.Errorf("BottomK() with k = 0 = %v", none)
+	}
+}
@@ -0,0 +1,196 @@
+package algo_test
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/algo"
+	"github.com/luverolla/lexgo/pkg/list"
+)
+
+func chars(s string) *list.ArrList[string] {
+	return list.Arr(strings.Split(s, "")...)
+}
+
+// naive search, to check the others against
+func occurrences(text, pattern []string) []int {
+	result := make([]int, 0)
+	for i := 0; i+len(pattern) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(pattern)], pattern) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// applies the script to a, checking its steps, and returns its cost
+func applyScript(t *testing.T, a, b []string, script []algo.Edit, costs algo.EditCosts) int {
+	result, cost, i, j := make([]string, 0), 0, 0, 0
+	for _, e := range script {
+		if e.A != i || e.B != j {
+			t.Fatalf("edit %v at wrong position, want (%d, %d)", e, i, j)
+		}
+		switch e.Op {
+		case algo.EditKeep:
+			if a[i] != b[j] {
+				t.Fatalf("edit %v keeps different elements", e)
+			}
+			result = append(result, a[i])
+			i, j = i+1, j+1
+		case algo.EditSubstitute:
+			result, cost = append(result, b[j]), cost+costs.Substitute
+			i, j = i+1, j+1
+		case algo.EditTranspose:
+			if a[i] != b[j+1] || a[i+1] != b[j] {
+				t.Fatalf("edit %v swaps non matching elements", e)
+			}
+			result, cost = append(result, a[i+1], a[i]), cost+costs.Transpose
+			i, j = i+2, j+2
+		case algo.EditDelete:
+			cost += costs.Delete
+			i++
+		case algo.EditInsert:
+			result, cost = append(result, b[j]), cost+costs.Insert
+			j++
+		}
+	}
+	if !slices.Equal(result, b) {
+		t.Fatalf("script turns %v into %v, want %v", a, result, b)
+	}
+	return cost
+}
+
+func TestSubsequenceSearch(t *testing.T) {
+	searches := map[string]func(text, pattern *list.ArrList[string]) []int{
+		"KMPSearch": func(text, pattern *list.ArrList[string]) []int {
+			return algo.KMPSearch[string](text, pattern)
+		},
+		"HorspoolSearch": func(text, pattern *list.ArrList[string]) []int {
+			return algo.HorspoolSearch[string](text, pattern)
+		},
+		"RabinKarpSearch": func(text, pattern *list.ArrList[string]) []int {
+			return algo.RabinKarpSearch[string](text, pattern)
+		},
+	}
+	cases := [][2]string{
+		{"abracadabra", "abra"},
+		{"aaaaa", "aa"},
+		{"abcabcabd", "abcabd"},
+		{"abc", "abcd"},
+		{"abc", "x"},
+		{"", "a"},
+		{"abc", ""},
+	}
+	rnd := rand.New(rand.NewSource(44))
+	for k := 0; k < 50; k++ {
+		text, pattern := make([]byte, 300), make([]byte, 1+rnd.Intn(4))
+		for i := range text {
+			text[i] = "ab"[rnd.Intn(2)]
+		}
+		for i := range pattern {
+			pattern[i] = "ab"[rnd.Intn(2)]
+		}
+		cases = append(cases, [2]string{string(text), string(pattern)})
+	}
+	for name, search := range searches {
+		for _, c := range cases {
+			text, pattern := chars(c[0]), chars(c[1])
+			if c[0] == "" {
+				text = list.Arr[string]()
+			}
+			if c[1] == "" {
+				pattern = list.Arr[string]()
+			}
+			expected := occurrences(toSlice[string](text), toSlice[string](pattern))
+			if found := search(text, pattern); !slices.Equal(found, expected) {
+				t.Errorf("%s(%q, %q) = %v, want %v", name, c[0], c[1], found, expected)
+			}
+		}
+	}
+}
+
+func TestLongestCommonSubsequence(t *testing.T) {
+	a, b := chars("ABCBDAB"), chars("BDCABA")
+	common, script := algo.LongestCommonSubsequence[string](a, b)
+	if len(common) != 4 {
+		t.Errorf("LongestCommonSubsequence() = %v, want length 4", common)
+	}
+	cost := applyScript(t, toSlice[string](a), toSlice[string](b), script, algo.UnitCosts())
+	if cost != a.Size()+b.Size()-2*len(common) {
+		t.Errorf("LongestCommonSubsequence() script has %d edits, want %d", cost, a.Size()+b.Size()-8)
+	}
+	kept := make([]string, 0)
+	for _, e := range script {
+		if e.Op == algo.EditKeep {
+			v, _ := a.Get(e.A)
+			kept = append(kept, *v)
+		}
+	}
+	if !slices.Equal(kept, common) {
+		t.Errorf("LongestCommonSubsequence() keeps %v, want %v", kept, common)
+	}
+}
+
+func TestLongestCommonSubstring(t *testing.T) {
+	i, j, n := algo.LongestCommonSubstring[string](chars("xabcdey"), chars("zzbcdez"))
+	if i != 2 || j != 2 || n != 4 {
+		t.Errorf("LongestCommonSubstring() = (%d, %d, %d), want (2, 2, 4)", i, j, n)
+	}
+	if _, _, n := algo.LongestCommonSubstring[string](chars("abc"), chars("xyz")); n != 0 {
+		t.Errorf("LongestCommonSubstring() of disjoint collections has length %d", n)
+	}
+}
+
+func TestEditDistances(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		lev, osa int
+	}{
+		{"kitten", "sitting", 3, 3},
+		{"ca", "ac", 2, 1},
+		{"abcdef", "badcfe", 4, 3},
+		{"flaw", "lawn", 2, 2},
+		{"", "abc", 3, 3},
+		{"abc", "abc", 0, 0},
+	}
+	unit := algo.UnitCosts()
+	for _, c := range cases {
+		a, b := chars(c.a), chars(c.b)
+		if c.a == "" {
+			a = list.Arr[string]()
+		}
+		as, bs := toSlice[string](a), toSlice[string](b)
+		lev, script := algo.Levenshtein[string](a, b, unit)
+		if lev != c.lev {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", c.a, c.b, lev, c.lev)
+		}
+		if cost := applyScript(t, as, bs, script, unit); cost != lev {
+			t.Errorf("Levenshtein(%q, %q) script costs %d, want %d", c.a, c.b, cost, lev)
+		}
+		osa, script := algo.DamerauLevenshtein[string](a, b, unit)
+		if osa != c.osa {
+			t.Errorf("DamerauLevenshtein(%q, %q) = %d, want %d", c.a, c.b, osa, c.osa)
+		}
+		if cost := applyScript(t, as, bs, script, unit); cost != osa {
+			t.Errorf("DamerauLevenshtein(%q, %q) script costs %d, want %d", c.a, c.b, cost, osa)
+		}
+	}
+
+	// substituting costs more than deleting and inserting
+	costs := algo.EditCosts{Insert: 1, Delete: 1, Substitute: 5, Transpose: 1}
+	a, b := chars("abc"), chars("axc")
+	if d, script := algo.Levenshtein[string](a, b, costs); d != 2 {
+		t.Errorf("Levenshtein() with custom costs = %d, want 2", d)
+	} else if cost := applyScript(t, toSlice[string](a), toSlice[string](b), script, costs); cost != 2 {
+		t.Errorf("Levenshtein() with custom costs script costs %d, want 2", cost)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Levenshtein() with negative cost did not panic")
+		}
+	}()
+	algo.Levenshtein[string](a, b, algo.EditCosts{Insert: -1})
+}
@@ -2,6 +2,8 @@ package algo_test
 
 import (
 	"fmt"
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/luverolla/lexgo/pkg/algo"
@@ -85,6 +87,129 @@ func TestHeapSort(t *testing.T) {
 	}
 }
 
+func TestIntroSort(t *testing.T) {
+	sorted := algo.IntroSort(startData, tau.ASCmp)
+
+	if sorted.Size() != startData.Size() {
+		t.Errorf("IntroSort() = %v, want %v", sorted.Size(), startData.Size())
+	}
+
+	if !tau.Eq(sorted, sortedData) {
+		t.Errorf("IntroSort() = %v, want %v", sorted, sortedData)
+	}
+}
+
+func TestTimSort(t *testing.T) {
+	sorted := algo.TimSort(startData, tau.ASCmp)
+
+	if sorted.Size() != startData.Size() {
+		t.Errorf("TimSort() = %v, want %v", sorted.Size(), startData.Size())
+	}
+
+	if !tau.Eq(sorted, sortedData) {
+		t.Errorf("TimSort() = %v, want %v", sorted, sortedData)
+	}
+}
+
+func TestSortInPlace(t *testing.T) {
+	sorters := map[string]func(tau.IdxedColl[int], tau.Comparator[int]){
+		"QuickSortInPlace":     algo.QuickSortInPlace[int],
+		"MergeSortInPlace":     algo.MergeSortInPlace[int],
+		"BubbleSortInPlace":    algo.BubbleSortInPlace[int],
+		"InsertionSortInPlace": algo.InsertionSortInPlace[int],
+		"SelectionSortInPlace": algo.SelectionSortInPlace[int],
+		"HeapSortInPlace":      algo.HeapSortInPlace[int],
+		"IntroSortInPlace":     algo.IntroSortInPlace[int],
+		"TimSortInPlace":       algo.TimSortInPlace[int],
+	}
+	for name, sorter := range sorters {
+		data := startData.Clone().(tau.IdxedColl[int])
+		sorter(data, tau.ASCmp)
+		if !tau.Eq(data, sortedData) {
+			t.Errorf("%s() = %v, want %v", name, data, sortedData)
+		}
+	}
+	if tau.Eq(startData, sortedData) {
+		t.Errorf("copying sorts modified the original collection")
+	}
+}
+
+// record sorted by key only, to check stability
+type record struct {
+	key int
+	seq int
+}
+
+func TestHybridSortsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(39))
+	inputs := map[string][]record{}
+	for _, n := range []int{0, 1, 31, 100, 5000} {
+		random, sorted, reversed, sawtooth := make([]record, n), make([]record, n), make([]record, n), make([]record, n)
+		for i := 0; i < n; i++ {
+			random[i] = record{rnd.Intn(50), i}
+			sorted[i] = record{i / 3, i}
+			reversed[i] = record{n - i/3, i}
+			sawtooth[i] = record{i % 97, i}
+		}
+		inputs[fmt.Sprint("random ", n)] = random
+		inputs[fmt.Sprint("sorted ", n)] = sorted
+		inputs[fmt.Sprint("reversed ", n)] = reversed
+		inputs[fmt.Sprint("sawtooth ", n)] = sawtooth
+	}
+	byKey := func(a, b record) int { return a.key - b.key }
+	for name, input := range inputs {
+		expected := slices.Clone(input)
+		slices.SortStableFunc(expected, byKey)
+
+		timSorted := slices.Clone(input)
+		algo.TimSortSlice(timSorted, byKey)
+		if !slices.Equal(timSorted, expected) {
+			t.Errorf("TimSortSlice is not a stable sort on %s input", name)
+		}
+		merged := algo.MergeSort[record](list.Arr(input...), byKey)
+		if !slices.Equal(toRecords(merged), expected) {
+			t.Errorf("MergeSort is not a stable sort on %s input", name)
+		}
+
+		for sortName, sorter := range map[string]func([]record, tau.Comparator[record]){
+			"IntroSortSlice": algo.IntroSortSlice[record],
+			"QuickSort": func(data []record, cmp tau.Comparator[record]) {
+				copy(data, toRecords(algo.QuickSort[record](list.Arr(data...), cmp)))
+			},
+			"HeapSort": func(data []record, cmp tau.Comparator[record]) {
+				copy(data, toRecords(algo.HeapSort[record](list.Lkd(data...), cmp)))
+			},
+		} {
+			data := slices.Clone(input)
+			sorter(data, byKey)
+			if !slices.IsSortedFunc(data, byKey) || len(data) != len(input) {
+				t.Errorf("%s does not sort %s input", sortName, name)
+			}
+		}
+	}
+}
+
+func TestListSortIsStable(t *testing.T) {
+	byKey := func(a, b record) int { return a.key - b.key }
+	data := list.Lkd(record{2, 0}, record{1, 1}, record{2, 2}, record{1, 3})
+	sorted := data.Sort(byKey)
+	expected := []record{{1, 1}, {1, 3}, {2, 0}, {2, 2}}
+	if !slices.Equal(toRecords(sorted), expected) {
+		t.Errorf("LkdList.Sort() = %v, want %v", sorted, expected)
+	}
+	if !slices.Equal(toRecords(list.Arr(toRecords(data)...).Sort(byKey)), expected) {
+		t.Errorf("ArrList.Sort() is not stable")
+	}
+}
+
+func toRecords(coll tau.Collection[record]) []record {
+	res := make([]record, 0, coll.Size())
+	coll.Iter().Each(func(r record) {
+		res = append(res, r)
+	})
+	return res
+}
+
 // --- Examples ---
 func ExampleQuickSort() {
 	// Sorts a [tau.List] of integers using the QuickSort algorithm
@@ -93,3 +218,23 @@ func ExampleQuickSort() {
 	sorted := algo.QuickSort(list, tau.ASCmp)
 	fmt.Printf("%v", sorted)
 }
+
+func benchSort(b *testing.B, sorter func(tau.IdxedColl[int], tau.Comparator[int])) {
+	rnd := rand.New(rand.NewSource(39))
+	data := make([]int, 100000)
+	for i := range data {
+		data[i] = rnd.Int()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sorter(list.Arr(data...), tau.ASCmp)
+	}
+}
+
+func BenchmarkIntroSort(b *testing.B) {
+	benchSort(b, algo.IntroSortInPlace[int])
+}
+
+func BenchmarkTimSort(b *testing.B) {
+	benchSort(b, algo.TimSortInPlace[int])
+}
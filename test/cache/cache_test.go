@@ -0,0 +1,181 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/cache"
+)
+
+func keys[K any, V any](c cache.Cache[K, V]) []K {
+	res := make([]K, 0)
+	c.Keys().Each(func(k K) {
+		res = append(res, k)
+	})
+	return res
+}
+
+func TestLRUEviction(t *testing.T) {
+	c := cache.LRU[string, int](3)
+	evicted := make([]string, 0)
+	c.OnEvict(func(k string, v int) {
+		evicted = append(evicted, k)
+	})
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+	c.Put("d", 4)
+
+	if c.HasKey("b") {
+		t.Errorf("LRUCache still contains key %s", "b")
+	}
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Errorf("LRUCache evicted %v, expected %v", evicted, []string{"b"})
+	}
+	if c.Size() != 3 {
+		t.Errorf("LRUCache size is %d, expected %d", c.Size(), 3)
+	}
+	if c.String() != "LRUCache[c,a,d]" {
+		t.Errorf("LRUCache is %s, expected %s", c, "LRUCache[c,a,d]")
+	}
+}
+
+func TestLRUPeekAndRemove(t *testing.T) {
+	c := cache.LRU[string, int](2)
+	c.Put("a", 1)
+	c.Put("b", 2)
+
+	val, err := c.Peek("a")
+	if err != nil || *val != 1 {
+		t.Errorf("LRUCache Peek(%s) failed", "a")
+	}
+	c.Put("c", 3)
+	if c.HasKey("a") {
+		t.Errorf("LRUCache Peek(%s) changed the eviction order", "a")
+	}
+
+	val, err = c.Remove("b")
+	if err != nil || *val != 2 {
+		t.Errorf("LRUCache Remove(%s) failed", "b")
+	}
+	if _, err := c.Remove("b"); err == nil {
+		t.Errorf("LRUCache Remove(%s) of missing key did not fail", "b")
+	}
+	if c.Stats().Evictions != 1 {
+		t.Errorf("LRUCache evictions are %d, expected %d", c.Stats().Evictions, 1)
+	}
+}
+
+func TestLRUWeigher(t *testing.T) {
+	c := cache.LRU[string, string](10)
+	c.SetWeigher(func(k string, v string) int {
+		return len(v)
+	})
+
+	c.Put("a", "xxxx")
+	c.Put("b", "xxxx")
+	c.Put("c", "xxxx")
+
+	if c.HasKey("a") {
+		t.Errorf("LRUCache still contains key %s", "a")
+	}
+	if c.Weight() != 8 {
+		t.Errorf("LRUCache weight is %d, expected %d", c.Weight(), 8)
+	}
+
+	c.Put("b", "x")
+	if c.Weight() != 5 {
+		t.Errorf("LRUCache weight is %d, expected %d", c.Weight(), 5)
+	}
+}
+
+func TestLFUEviction(t *testing.T) {
+	c := cache.LFU[int, int](3)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	c.Get(1)
+	c.Get(1)
+	c.Get(3)
+	c.Put(4, 4)
+
+	if c.HasKey(2) {
+		t.Errorf("LFUCache still contains key %d", 2)
+	}
+	if c.Frequency(1) != 3 {
+		t.Errorf("LFUCache Frequency(%d) is %d, expected %d", 1, c.Frequency(1), 3)
+	}
+
+	c.Put(5, 5)
+	if c.HasKey(4) {
+		t.Errorf("LFUCache still contains key %d", 4)
+	}
+
+	got := keys[int, int](c)
+	want := []int{5, 3, 1}
+	for i := range want {
+		if i >= len(got) || got[i] != want[i] {
+			t.Errorf("LFUCache keys are %v, expected %v", got, want)
+			break
+		}
+	}
+}
+
+func TestLFURemove(t *testing.T) {
+	c := cache.LFU[int, int](2)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Get(2)
+	c.Remove(1)
+	c.Put(3, 3)
+	c.Put(4, 4)
+
+	if !c.HasKey(2) || c.HasKey(3) || !c.HasKey(4) {
+		t.Errorf("LFUCache is %s, expected keys %v", c, []int{4, 2})
+	}
+}
+
+func TestARCAdaptation(t *testing.T) {
+	c := cache.ARC[int, int](4)
+	for i := 0; i < 4; i++ {
+		c.Put(i, i)
+	}
+	// promote 0 and 1 to the frequency list
+	c.Get(0)
+	c.Get(1)
+	// scan: must not flush the frequently used entries
+	for i := 10; i < 20; i++ {
+		c.Put(i, i)
+	}
+	if !c.HasKey(0) || !c.HasKey(1) {
+		t.Errorf("ARCCache lost frequent keys after a scan: %s", c)
+	}
+	if c.Size() != 4 {
+		t.Errorf("ARCCache size is %d, expected %d", c.Size(), 4)
+	}
+
+	// hits on ghosts of the recency list grow its target
+	before := c.Target()
+	c.Put(17, 17)
+	if c.Target() <= before {
+		t.Errorf("ARCCache target is %d, expected more than %d", c.Target(), before)
+	}
+}
+
+func TestStats(t *testing.T) {
+	caches := []cache.Cache[int, int]{cache.LRU[int, int](2), cache.LFU[int, int](2), cache.ARC[int, int](2)}
+	for _, c := range caches {
+		c.Put(1, 1)
+		c.Get(1)
+		c.Get(1)
+		c.Get(2)
+		stats := c.Stats()
+		if stats.Hits != 2 || stats.Misses != 1 {
+			t.Errorf("%s stats are %v, expected 2 hits and 1 miss", c, stats)
+		}
+		if stats.HitRate() < 0.66 || stats.HitRate() > 0.67 {
+			t.Errorf("%s hit rate is %f, expected %f", c, stats.HitRate(), 2.0/3.0)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tau"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func TestSentinels(t *testing.T) {
+	cases := []struct {
+		err      error
+		sentinel error
+	}{
+		{errs.NotFound(1), errs.ErrNotFound},
+		{errs.Empty(), errs.ErrEmpty},
+		{errs.Cycle([]int{1, 1}), errs.ErrCycle},
+		{errs.NegativeWeight(1), errs.ErrNegativeWeight},
+		{errs.Mismatch(1, 2), errs.ErrMismatch},
+		{errs.IndexOutOfRange("Get", nil, 5, 3), errs.ErrIndexOutOfRange},
+		{errs.TypeMismatch("Cmp", nil, "int", "a"), errs.ErrTypeMismatch},
+		{errs.CapacityExceeded("Put", nil, 8), errs.ErrCapacityExceeded},
+		{errs.UnsupportedOp("Remove", nil), errs.ErrUnsupportedOp},
+		{errs.ConcurrentModification("Each", nil), errs.ErrConcurrentModification},
+	}
+	for _, c := range cases {
+		if !errors.Is(c.err, c.sentinel) {
+			t.Errorf("%v does not match %v", c.err, c.sentinel)
+		}
+		// also when wrapped
+		if !errors.Is(fmt.Errorf("wrapped: %w", c.err), c.sentinel) {
+			t.Errorf("wrapped %v does not match %v", c.err, c.sentinel)
+		}
+	}
+	if errors.Is(errs.NotFound(1), errs.ErrEmpty) || errors.Is(errs.CapacityExceeded("Put", nil, 8), errs.ErrIndexOutOfRange) {
+		t.Errorf("errors match the sentinel of another kind")
+	}
+}
+
+func TestContext(t *testing.T) {
+	l := list.Arr(1, 2, 3)
+	err := errs.IndexOutOfRange("Get", l, 5, l.Size())
+	if err.Op != "Get" || err.Coll != "*list.ArrList[int]" || err.Index != 5 || err.Size != 3 {
+		t.Errorf("unexpected fields in %+v", err)
+	}
+	if want := "[*list.ArrList[int].Get] index 5 out of range for size 3"; err.Error() != want {
+		t.Errorf("Error() = %q, expected %q", err.Error(), want)
+	}
+	if want := "[tau.Hash] expected tau.Hashable, found []int"; errs.TypeMismatch("tau.Hash", nil, "tau.Hashable", []int{}).Error() != want {
+		t.Errorf("Error() of a function error is not %q", want)
+	}
+}
+
+func TestIndexErrors(t *testing.T) {
+	ft := tree.Fenwick[int](list.Arr(1, 2, 3))
+	_, err := ft.RangeSum(2, 5)
+	var idxErr errs.IndexOutOfRangeErr
+	if !errors.As(err, &idxErr) || idxErr.Op != "RangeSum" || idxErr.Index != 5 || idxErr.Size != 4 {
+		t.Errorf("RangeSum(2, 5) returned %v, expected index 5 out of range for size 4", err)
+	}
+	if _, err := ft.RangeSum(2, 1); !errors.As(err, &idxErr) || idxErr.Index != 2 || idxErr.Size != 2 {
+		t.Errorf("RangeSum(2, 1) returned %v, expected index 2 out of range for size 2", err)
+	}
+}
+
+func TestPanicsWithTypeMismatch(t *testing.T) {
+	defer func() {
+		err, ok := recover().(error)
+		var mismatch errs.TypeMismatchErr
+		if !ok || !errors.As(err, &mismatch) || mismatch.Found != "[]int" {
+			t.Errorf("Cmp() of incomparable values did not panic with a TypeMismatchErr")
+		}
+	}()
+	tau.Cmp([]int{1}, []int{2})
+}
+
+func TestCmpPanicsOnConcurrentModification(t *testing.T) {
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, errs.ErrConcurrentModification) {
+			t.Errorf("Cmp() with a shrinking collection did not panic with a ConcurrentModificationErr")
+		}
+	}()
+	tau.CollCmp[int](list.Arr(1, 2, 3), &shrinking{list.Arr(1, 2, 3)})
+}
+
+// list whose iterator stops early, as if it was changed while iterating
+type shrinking struct {
+	*list.ArrList[int]
+}
+
+func (s *shrinking) Iter() tau.Iterator[int] {
+	return list.Arr(1, 2).Iter()
+}
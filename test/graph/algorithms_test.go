@@ -0,0 +1,186 @@
+package graph_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/graph"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+type cell struct {
+	x, y int
+}
+
+func (c cell) Cmp(other any) int {
+	o := other.(cell)
+	if c.x != o.x {
+		return c.x - o.x
+	}
+	return c.y - o.y
+}
+
+func (c cell) Hash() uint32 {
+	return uint32(c.x*31 + c.y)
+}
+
+func TestSpanningTrees(t *testing.T) {
+	g := graph.Undirected[string]()
+	g.AddWeightedEdge("a", "b", 4)
+	g.AddWeightedEdge("a", "h", 8)
+	g.AddWeightedEdge("b", "c", 8)
+	g.AddWeightedEdge("b", "h", 11)
+	g.AddWeightedEdge("c", "d", 7)
+	g.AddWeightedEdge("c", "f", 4)
+	g.AddWeightedEdge("c", "i", 2)
+	g.AddWeightedEdge("d", "e", 9)
+	g.AddWeightedEdge("d", "f", 14)
+	g.AddWeightedEdge("e", "f", 10)
+	g.AddWeightedEdge("f", "g", 2)
+	g.AddWeightedEdge("g", "h", 1)
+	g.AddWeightedEdge("g", "i", 6)
+	g.AddWeightedEdge("h", "i", 7)
+	g.AddWeightedEdge("x", "y", 3)
+
+	for name, algo := range map[string]func(graph.Graph[string]) (tau.List[graph.Edge[string]], float64){
+		"Kruskal": graph.Kruskal[string],
+		"Prim":    graph.Prim[string],
+	} {
+		edges, total := algo(g)
+		if total != 40 {
+			t.Errorf("%s total weight is %v, expected 40", name, total)
+		}
+		// 11 vertices in 2 components
+		if edges.Size() != 9 {
+			t.Errorf("%s returned %d edges, expected 9", name, edges.Size())
+		}
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	g := graph.Directed[string]()
+	g.AddWeightedEdge("s", "v1", 16)
+	g.AddWeightedEdge("s", "v2", 13)
+	g.AddWeightedEdge("v2", "v1", 4)
+	g.AddWeightedEdge("v1", "v3", 12)
+	g.AddWeightedEdge("v3", "v2", 9)
+	g.AddWeightedEdge("v2", "v4", 14)
+	g.AddWeightedEdge("v4", "v3", 7)
+	g.AddWeightedEdge("v3", "t", 20)
+	g.AddWeightedEdge("v4", "t", 4)
+
+	flow, err := graph.MaxFlow[string](g, "s", "t")
+	if err != nil {
+		t.Fatalf("MaxFlow returned error %v", err)
+	}
+	if flow.Value() != 23 {
+		t.Errorf("MaxFlow value is %v, expected 23", flow.Value())
+	}
+	cut := 0.0
+	flow.CutEdges().Iter().Each(func(e graph.Edge[string]) {
+		cut += e.Weight
+	})
+	if cut != flow.Value() {
+		t.Errorf("MaxFlow cut capacity is %v, expected %v", cut, flow.Value())
+	}
+	side := flow.SourceSide()
+	if !side.Contains("s") || side.Contains("t") {
+		t.Errorf("MaxFlow source side is %v", side)
+	}
+	// conservation at every inner vertex
+	g.Vertices().Each(func(v string) {
+		if v == "s" || v == "t" {
+			return
+		}
+		in, out := 0.0, 0.0
+		g.Vertices().Each(func(u string) {
+			in += flow.FlowOn(u, v)
+			out += flow.FlowOn(v, u)
+		})
+		if in != out {
+			t.Errorf("MaxFlow breaks conservation at %s: %v in, %v out", v, in, out)
+		}
+	})
+
+	if _, err := graph.MaxFlow[string](g, "s", "z"); err == nil {
+		t.Errorf("MaxFlow to missing vertex returned no error")
+	}
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	g := graph.Undirected[string]()
+	g.AddEdge("alice", "java")
+	g.AddEdge("alice", "go")
+	g.AddEdge("bob", "go")
+	g.AddEdge("carol", "go")
+	g.AddEdge("carol", "rust")
+	g.AddEdge("dave", "rust")
+	g.AddEdge("dave", "c")
+
+	matching, err := graph.HopcroftKarp[string](g)
+	if err != nil {
+		t.Fatalf("HopcroftKarp returned error %v", err)
+	}
+	if matching.Size() != 4 {
+		t.Errorf("HopcroftKarp matched %d pairs, expected 4", matching.Size())
+	}
+	used := map[string]bool{}
+	matching.Keys().Each(func(u string) {
+		v, _ := matching.Get(u)
+		if !g.HasEdge(u, *v) || used[*v] {
+			t.Errorf("HopcroftKarp pair %s-%s is not valid", u, *v)
+		}
+		used[*v] = true
+	})
+
+	g.AddEdge("java", "go")
+	_, err = graph.HopcroftKarp[string](g)
+	var cycleErr errs.CycleErr
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("HopcroftKarp on non bipartite graph returned %v", err)
+	}
+	cycle := collect(cycleErr.Cycle.(tau.List[string]).Iter())
+	if len(cycle)%2 != 0 || cycle[0] != cycle[len(cycle)-1] {
+		t.Errorf("Bipartition reported cycle %v, expected an odd one", cycle)
+	}
+}
+
+func TestAStar(t *testing.T) {
+	// 10x10 grid with a wall at x = 5, open at y = 9
+	g := graph.Undirected[cell]()
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			if x == 5 && y != 9 {
+				continue
+			}
+			if x+1 < 10 && !(x+1 == 5 && y != 9) {
+				g.AddEdge(cell{x, y}, cell{x + 1, y})
+			}
+			if y+1 < 10 && x != 5 {
+				g.AddEdge(cell{x, y}, cell{x, y + 1})
+			}
+		}
+	}
+	goal := cell{9, 0}
+	manhattan := func(c cell) float64 {
+		return math.Abs(float64(goal.x-c.x)) + math.Abs(float64(goal.y-c.y))
+	}
+	path, dist, err := graph.AStar[cell](g, cell{0, 0}, goal, manhattan)
+	if err != nil {
+		t.Fatalf("AStar returned error %v", err)
+	}
+	if dist != 27 || path.Size() != 28 {
+		t.Errorf("AStar found a path of length %v with %d vertices, expected 27 and 28", dist, path.Size())
+	}
+	paths, _ := graph.Dijkstra[cell](g, cell{0, 0})
+	if d, _ := paths.DistTo(goal); d != dist {
+		t.Errorf("AStar distance is %v, Dijkstra found %v", dist, d)
+	}
+
+	g.AddVertex(cell{20, 20})
+	if _, _, err := graph.AStar[cell](g, cell{0, 0}, cell{20, 20}, manhattan); err == nil {
+		t.Errorf("AStar to unreachable vertex returned no error")
+	}
+}
@@ -0,0 +1,218 @@
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/graph"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func collect[T any](iter tau.Iterator[T]) []T {
+	res := make([]T, 0)
+	iter.Each(func(v T) {
+		res = append(res, v)
+	})
+	return res
+}
+
+func position(list tau.List[string], v string) int {
+	return list.IndexOf(v)
+}
+
+func TestAdjGraphEdges(t *testing.T) {
+	g := graph.Undirected[int]()
+	g.AddEdge(1, 2)
+	g.AddWeightedEdge(2, 3, 5)
+	g.AddVertex(4)
+	if g.Order() != 4 || g.Size() != 2 {
+		t.Errorf("AdjGraph has %d vertices and %d edges, expected 4 and 2", g.Order(), g.Size())
+	}
+	if w, err := g.Weight(3, 2); err != nil || w != 5 {
+		t.Errorf("AdjGraph undirected weight is %v, expected 5", w)
+	}
+	if len(collect(g.Edges())) != 2 {
+		t.Errorf("AdjGraph Edges returned duplicates")
+	}
+	g.RemoveVertex(2)
+	if g.Size() != 0 || g.HasEdge(1, 2) || g.OutDegree(3) != 0 {
+		t.Errorf("AdjGraph RemoveVertex left edges behind")
+	}
+
+	d := graph.Directed[string]()
+	d.AddEdge("a", "b")
+	if !d.HasEdge("a", "b") || d.HasEdge("b", "a") {
+		t.Errorf("AdjGraph directed edge has the wrong direction")
+	}
+	if d.InDegree("b") != 1 || d.OutDegree("b") != 0 {
+		t.Errorf("AdjGraph directed degrees are wrong")
+	}
+	if err := d.RemoveEdge("b", "a"); err == nil {
+		t.Errorf("AdjGraph RemoveEdge of missing edge returned no error")
+	}
+}
+
+func TestTraversals(t *testing.T) {
+	g := graph.Directed[int]()
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(6, 1)
+
+	bfs := collect(graph.BFS[int](g, 1))
+	if len(bfs) != 5 || bfs[0] != 1 || bfs[3] != 4 || bfs[4] != 5 {
+		t.Errorf("BFS order is %v", bfs)
+	}
+	dfs := collect(graph.DFS[int](g, 1))
+	if len(dfs) != 5 || dfs[0] != 1 {
+		t.Errorf("DFS order is %v", dfs)
+	}
+	// 4 comes right after the first child of 1, and 5 right after 4
+	if dfs[2] != 4 || dfs[3] != 5 {
+		t.Errorf("DFS order is not depth-first: %v", dfs)
+	}
+}
+
+func TestTopoSort(t *testing.T) {
+	g := graph.Directed[string]()
+	g.AddEdge("shirt", "tie")
+	g.AddEdge("tie", "jacket")
+	g.AddEdge("trousers", "shoes")
+	g.AddEdge("trousers", "belt")
+	g.AddEdge("belt", "jacket")
+	g.AddEdge("shirt", "belt")
+	g.AddVertex("watch")
+
+	order, err := graph.TopoSort[string](g)
+	if err != nil {
+		t.Fatalf("TopoSort returned error %v", err)
+	}
+	if order.Size() != g.Order() {
+		t.Errorf("TopoSort returned %d vertices, expected %d", order.Size(), g.Order())
+	}
+	g.Edges().Each(func(e graph.Edge[string]) {
+		if position(order, e.From) > position(order, e.To) {
+			t.Errorf("TopoSort puts %s after %s", e.From, e.To)
+		}
+	})
+
+	g.AddEdge("jacket", "shirt")
+	_, err = graph.TopoSort[string](g)
+	var cycleErr errs.CycleErr
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("TopoSort on cyclic graph returned %v", err)
+	}
+	cycle := collect(cycleErr.Cycle.(tau.List[string]).Iter())
+	if len(cycle) < 3 || cycle[0] != cycle[len(cycle)-1] {
+		t.Fatalf("TopoSort reported cycle %v", cycle)
+	}
+	for i := 0; i+1 < len(cycle); i++ {
+		if !g.HasEdge(cycle[i], cycle[i+1]) {
+			t.Errorf("TopoSort cycle %v has no edge %s -> %s", cycle, cycle[i], cycle[i+1])
+		}
+	}
+}
+
+func TestTopoSortUndirected(t *testing.T) {
+	g := graph.Undirected[string]()
+	g.AddEdge("a", "b")
+	if _, err := graph.TopoSort[string](g); !errors.Is(err, errs.ErrUnsupportedOp) {
+		t.Errorf("TopoSort on undirected graph returned %v", err)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	g := graph.Directed[int]()
+	// strongly connected: {1,2,3}, {4,5}, {6}
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 1)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(5, 4)
+	g.AddEdge(5, 6)
+	g.AddEdge(7, 8)
+
+	weak := graph.ConnectedComponents[int](g)
+	if weak.Size() != 2 {
+		t.Errorf("ConnectedComponents found %d components, expected 2", weak.Size())
+	}
+
+	strong := graph.StronglyConnectedComponents[int](g)
+	if strong.Size() != 5 {
+		t.Fatalf("StronglyConnectedComponents found %d components, expected 5", strong.Size())
+	}
+	sizes := map[int]int{}
+	strong.Iter().Each(func(c tau.Set[int]) {
+		sizes[c.Size()]++
+	})
+	if sizes[3] != 1 || sizes[2] != 1 || sizes[1] != 3 {
+		t.Errorf("StronglyConnectedComponents sizes are %v", sizes)
+	}
+	// sinks come first
+	first, _ := strong.Get(0)
+	if !(*first).Contains(6) && !(*first).Contains(8) {
+		t.Errorf("StronglyConnectedComponents first component is %v", *first)
+	}
+}
+
+func TestShortestPaths(t *testing.T) {
+	g := graph.Directed[string]()
+	g.AddWeightedEdge("s", "a", 4)
+	g.AddWeightedEdge("s", "b", 1)
+	g.AddWeightedEdge("b", "a", 2)
+	g.AddWeightedEdge("a", "t", 1)
+	g.AddWeightedEdge("b", "t", 5)
+	g.AddVertex("x")
+
+	for name, algo := range map[string]func(graph.Graph[string], string) (*graph.ShortestPaths[string], error){
+		"Dijkstra":    graph.Dijkstra[string],
+		"BellmanFord": graph.BellmanFord[string],
+	} {
+		paths, err := algo(g, "s")
+		if err != nil {
+			t.Fatalf("%s returned error %v", name, err)
+		}
+		if d, _ := paths.DistTo("t"); d != 4 {
+			t.Errorf("%s distance to t is %v, expected 4", name, d)
+		}
+		path, _ := paths.PathTo("t")
+		if got := collect(path.Iter()); len(got) != 4 || got[1] != "b" || got[2] != "a" {
+			t.Errorf("%s path to t is %v", name, got)
+		}
+		if _, err := paths.PathTo("x"); err == nil {
+			t.Errorf("%s found a path to unreachable vertex", name)
+		}
+	}
+
+	g.AddWeightedEdge("b", "a", -1)
+	if _, err := graph.Dijkstra[string](g, "s"); !errors.As(err, new(errs.NegativeWeightErr)) {
+		t.Errorf("Dijkstra with negative weight returned %v", err)
+	}
+	path, dist, err := graph.ShortestPath[string](g, "s", "t")
+	if err != nil || dist != 1 || path.Size() != 4 {
+		t.Errorf("ShortestPath returned %v, %v, %v", path, dist, err)
+	}
+
+	g.AddWeightedEdge("t", "b", -1)
+	_, err = graph.BellmanFord[string](g, "s")
+	var cycleErr errs.CycleErr
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("BellmanFord with negative cycle returned %v", err)
+	}
+	cycle := collect(cycleErr.Cycle.(tau.List[string]).Iter())
+	total := 0.0
+	for i := 0; i+1 < len(cycle); i++ {
+		w, err := g.Weight(cycle[i], cycle[i+1])
+		if err != nil {
+			t.Fatalf("BellmanFord cycle %v has no edge %s -> %s", cycle, cycle[i], cycle[i+1])
+		}
+		total += w
+	}
+	if len(cycle) < 3 || cycle[0] != cycle[len(cycle)-1] || total >= 0 {
+		t.Errorf("BellmanFord reported cycle %v of weight %v", cycle, total)
+	}
+}
@@ -1,8 +1,10 @@
 package list_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/luverolla/lexgo/pkg/errs"
 	"github.com/luverolla/lexgo/pkg/list"
 	"github.com/luverolla/lexgo/pkg/tau"
 )
@@ -296,3 +298,108 @@ func TestArrayListSublist(t *testing.T) {
 		}
 	}
 }
+
+func TestArrayListInsert(t *testing.T) {
+	l := list.Arr[int](1, 2, 3)
+	for _, c := range []struct{ index, value, at int }{{0, 0, 0}, {4, 4, 4}, {-1, 5, 5}, {2, 9, 2}, {13, 7, 5}} {
+		l.Insert(c.index, c.value)
+		if val, _ := l.Get(c.at); *val != c.value {
+			t.Errorf("ArrayList[int] Insert(%d, %d) put %d at %d, expected %d", c.index, c.value, *val, c.at, c.value)
+		}
+	}
+	if l.Size() != 8 {
+		t.Errorf("ArrayList[int] size is %d after 5 inserts, expected 8", l.Size())
+	}
+	empty := list.Arr[int]()
+	empty.Insert(5, 1)
+	if val, _ := empty.Get(0); empty.Size() != 1 || *val != 1 {
+		t.Errorf("ArrayList[int] Insert into an empty list is %v, expected [1]", empty)
+	}
+	single := list.Arr[int](1)
+	if val, err := single.RemoveAt(0); err != nil || *val != 1 || !single.Empty() {
+		t.Errorf("ArrayList[int] RemoveAt(0) of a single element list is %v, expected empty", single)
+	}
+}
+
+func TestArrayListStrict(t *testing.T) {
+	lenient := list.Arr[int](1, 2, 3)
+	if val, _ := lenient.Get(1000); *val != 2 {
+		t.Errorf("ArrayList[int] Get(1000) is %d, expected 2 as it wraps around", *val)
+	}
+	if _, err := lenient.StrictGet(1000); !errors.Is(err, errs.ErrIndexOutOfRange) {
+		t.Errorf("ArrayList[int] StrictGet(1000) returned %v, expected an index out of range", err)
+	}
+	if val, err := lenient.StrictGet(-3); err != nil || *val != 1 {
+		t.Errorf("ArrayList[int] StrictGet(-3) is %v, expected 1", err)
+	}
+	if err := lenient.StrictSet(3, 0); !errors.Is(err, errs.ErrIndexOutOfRange) {
+		t.Errorf("ArrayList[int] StrictSet(3) returned %v, expected an index out of range", err)
+	}
+	if err := lenient.StrictInsert(3, 4); err != nil {
+		t.Errorf("ArrayList[int] StrictInsert at the end returned %v", err)
+	}
+	if err := lenient.StrictInsert(-6, 0); !errors.Is(err, errs.ErrIndexOutOfRange) {
+		t.Errorf("ArrayList[int] StrictInsert(-6) returned %v, expected an index out of range", err)
+	}
+
+	strict := list.StrictArr[int](1, 2, 3)
+	_, err := strict.Get(3)
+	var idxErr errs.IndexOutOfRangeErr
+	if !errors.As(err, &idxErr) || idxErr.Op != "Get" || idxErr.Index != 3 || idxErr.Size != 3 {
+		t.Errorf("strict ArrayList[int] Get(3) returned %v, expected index 3 out of range for size 3", err)
+	}
+	if _, err := strict.RemoveAt(-4); !errors.Is(err, errs.ErrIndexOutOfRange) || strict.Size() != 3 {
+		t.Errorf("strict ArrayList[int] RemoveAt(-4) returned %v, expected an index out of range", err)
+	}
+	if clone := strict.Clone().(*list.ArrList[int]); !clone.Strict() {
+		t.Errorf("clone of a strict ArrayList[int] is not strict")
+	}
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, errs.ErrIndexOutOfRange) {
+			t.Errorf("strict ArrayList[int] Set(5) did not panic with an index out of range")
+		}
+	}()
+	strict.Set(5, 0)
+}
+
+func TestArrayListStrictSliceAndEmpty(t *testing.T) {
+	strict := list.StrictArr[int](1, 2, 3, 4)
+	if sub := strict.Slice(-3, 4); !tau.Eq(sub, list.Arr(2, 3, 4)) {
+		t.Errorf("strict ArrayList[int] Slice(-3, 4) is %v, expected [2 3 4]", sub)
+	}
+	empty := list.StrictArr[int]()
+	_, getErr := empty.StrictGet(0)
+	_, removeErr := empty.RemoveAt(0)
+	for _, err := range []error{getErr, removeErr, empty.StrictSet(0, 1)} {
+		if !errors.Is(err, errs.ErrIndexOutOfRange) {
+			t.Errorf("strict ArrayList[int] access to an empty list returned %v, expected an index out of range", err)
+		}
+	}
+	if _, err := list.Arr[int]().Get(0); !errors.Is(err, errs.ErrEmpty) {
+		t.Errorf("ArrayList[int] Get on an empty list returned %v, expected an empty error", err)
+	}
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, errs.ErrIndexOutOfRange) {
+			t.Errorf("strict ArrayList[int] Slice(0, 1000) did not panic with an index out of range")
+		}
+	}()
+	strict.Slice(0, 1000)
+}
+
+func TestArrayListSliceToEnd(t *testing.T) {
+	l := list.Arr[int](1, 2, 3)
+	cases := []struct {
+		start, end int
+		expected   *list.ArrList[int]
+	}{
+		{1, 3, list.Arr(2, 3)},
+		{0, 3, list.Arr(1, 2, 3)},
+		{-2, 3, list.Arr(2, 3)},
+		{2, 2, list.Arr[int]()},
+	}
+	for _, c := range cases {
+		if sub := l.Slice(c.start, c.end); !tau.Eq(sub, c.expected) {
+			t.Errorf("ArrayList[int] Slice(%d, %d) is %v, expected %v", c.start, c.end, sub, c.expected)
+		}
+	}
+}
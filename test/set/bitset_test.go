@@ -0,0 +1,157 @@
+package set_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/set"
+)
+
+func TestBitSet(t *testing.T) {
+	s := set.Bits(3, 64, 1, 200, 64)
+	if s.Size() != 4 || s.String() != "BitSet{1, 3, 64, 200}" {
+		t.Errorf("BitSet is %s", s)
+	}
+	if s.Rank(64) != 2 || s.Rank(1000) != 4 || s.Rank(0) != 0 {
+		t.Errorf("BitSet Rank is wrong")
+	}
+	if v, err := s.Select(2); err != nil || v != 64 {
+		t.Errorf("BitSet Select(2) is %d", v)
+	}
+	if _, err := s.Select(4); err == nil {
+		t.Errorf("BitSet Select out of range returned no error")
+	}
+	if err := s.Remove(200); err != nil || s.Contains(200) {
+		t.Errorf("BitSet Remove failed")
+	}
+	if err := s.Remove(-1); err == nil {
+		t.Errorf("BitSet Remove of negative value returned no error")
+	}
+
+	other := set.Bits(1, 2, 3)
+	if s.And(other).String() != "BitSet{1, 3}" || s.Or(other).String() != "BitSet{1, 2, 3, 64}" {
+		t.Errorf("BitSet And/Or are %s and %s", s.And(other), s.Or(other))
+	}
+	if s.Xor(other).String() != "BitSet{2, 64}" || s.AndNot(other).String() != "BitSet{64}" {
+		t.Errorf("BitSet Xor/AndNot are %s and %s", s.Xor(other), s.AndNot(other))
+	}
+
+	data, _ := s.MarshalBinary()
+	restored := set.Bits()
+	if err := restored.UnmarshalBinary(data); err != nil || restored.Cmp(s) != 0 {
+		t.Errorf("BitSet serialization gives %s, expected %s", restored, s)
+	}
+	if err := restored.UnmarshalBinary(data[1:]); err == nil {
+		t.Errorf("BitSet UnmarshalBinary of bad data returned no error")
+	}
+}
+
+// brute-force check against a sorted slice
+func checkRoaring(t *testing.T, s *set.RoaringBitmap, expected []uint32) {
+	t.Helper()
+	if s.Size() != len(expected) {
+		t.Fatalf("RoaringBitmap has size %d, expected %d", s.Size(), len(expected))
+	}
+	i := 0
+	s.Iter().Each(func(v uint32) {
+		if v != expected[i] {
+			t.Fatalf("RoaringBitmap element %d is %d, expected %d", i, v, expected[i])
+		}
+		i++
+	})
+}
+
+func roaringOf(values map[uint32]bool) ([]uint32, *set.RoaringBitmap) {
+	sorted := make([]uint32, 0, len(values))
+	for v := range values {
+		sorted = append(sorted, v)
+	}
+	slices.Sort(sorted)
+	return sorted, set.Roaring(sorted...)
+}
+
+func TestRoaringBitmapRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(37))
+	a, b := map[uint32]bool{}, map[uint32]bool{}
+	// a dense container, a sparse one and a shared one
+	for i := 0; i < 20000; i++ {
+		a[uint32(rnd.Intn(1<<16))] = true
+		a[uint32(3<<16+rnd.Intn(1<<16))] = rnd.Intn(2) == 0
+		b[uint32(3<<16+rnd.Intn(1<<16))] = true
+		b[uint32(rnd.Intn(1<<32))] = rnd.Intn(10) == 0
+	}
+	for k, v := range a {
+		if !v {
+			delete(a, k)
+		}
+	}
+	for k, v := range b {
+		if !v {
+			delete(b, k)
+		}
+	}
+	sa, ra := roaringOf(a)
+	sb, rb := roaringOf(b)
+	checkRoaring(t, ra, sa)
+	checkRoaring(t, rb, sb)
+
+	ops := map[string]func(x, y bool) bool{
+		"And":    func(x, y bool) bool { return x && y },
+		"Or":     func(x, y bool) bool { return x || y },
+		"Xor":    func(x, y bool) bool { return x != y },
+		"AndNot": func(x, y bool) bool { return x && !y },
+	}
+	results := map[string]*set.RoaringBitmap{
+		"And": ra.And(rb), "Or": ra.Or(rb), "Xor": ra.Xor(rb), "AndNot": ra.AndNot(rb),
+	}
+	for name, op := range ops {
+		expected := map[uint32]bool{}
+		for v := range a {
+			if op(true, b[v]) {
+				expected[v] = true
+			}
+		}
+		for v := range b {
+			if op(a[v], true) {
+				expected[v] = true
+			}
+		}
+		sorted, _ := roaringOf(expected)
+		checkRoaring(t, results[name], sorted)
+	}
+
+	for k := 0; k < len(sa); k += 97 {
+		if v, err := ra.Select(k); err != nil || v != sa[k] || ra.Rank(v) != k {
+			t.Fatalf("RoaringBitmap Select(%d) is %d, expected %d", k, v, sa[k])
+		}
+	}
+
+	// removing from the dense container turns it into an array one
+	for _, v := range sa {
+		if v < 1<<16 && v%8 != 0 {
+			if err := ra.Remove(v); err != nil {
+				t.Fatalf("RoaringBitmap Remove(%d) returned error %v", v, err)
+			}
+			delete(a, v)
+		}
+	}
+	sa, _ = roaringOf(a)
+	checkRoaring(t, ra, sa)
+	if err := ra.Remove(1); err == nil {
+		t.Errorf("RoaringBitmap Remove of missing element returned no error")
+	}
+
+	data, _ := ra.MarshalBinary()
+	restored := set.Roaring()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("RoaringBitmap UnmarshalBinary returned error %v", err)
+	}
+	checkRoaring(t, restored, sa)
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("RoaringBitmap UnmarshalBinary of truncated data returned no error")
+	}
+	if restored.Cmp(ra) != 0 {
+		t.Errorf("RoaringBitmap changed after a failed UnmarshalBinary")
+	}
+}
@@ -0,0 +1,136 @@
+package set_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func TestDisjointSet(t *testing.T) {
+	ds := set.Disjoint("a", "b", "c", "d", "e")
+	if ds.Size() != 5 || ds.Count() != 5 {
+		t.Errorf("DisjointSet has %d elements in %d sets, expected 5 in 5", ds.Size(), ds.Count())
+	}
+	if !ds.Union("a", "b") || !ds.Union("c", "d") || !ds.Union("b", "d") {
+		t.Errorf("DisjointSet Union of different sets returned false")
+	}
+	if ds.Union("a", "c") {
+		t.Errorf("DisjointSet Union of the same set returned true")
+	}
+	if !ds.Connected("a", "d") || ds.Connected("a", "e") || ds.Connected("a", "z") {
+		t.Errorf("DisjointSet Connected is wrong")
+	}
+	ra, _ := ds.Find("a")
+	rd, _ := ds.Find("d")
+	if *ra != *rd {
+		t.Errorf("DisjointSet Find gives %s and %s for the same set", *ra, *rd)
+	}
+	if _, err := ds.Find("z"); err == nil {
+		t.Errorf("DisjointSet Find of missing element returned no error")
+	}
+	if ds.SetSize("c") != 4 || ds.SetSize("e") != 1 || ds.SetSize("z") != 0 {
+		t.Errorf("DisjointSet SetSize is wrong")
+	}
+
+	ds.Union("f", "e")
+	if ds.Size() != 6 || ds.Count() != 2 {
+		t.Errorf("DisjointSet has %d elements in %d sets, expected 6 in 2", ds.Size(), ds.Count())
+	}
+	sizes := map[int]int{}
+	ds.Sets().Each(func(s tau.Set[string]) {
+		sizes[s.Size()]++
+	})
+	if sizes[4] != 1 || sizes[2] != 1 {
+		t.Errorf("DisjointSet Sets sizes are %v", sizes)
+	}
+
+	clone := ds.Clone().(*set.DisjointSet[string])
+	clone.Union("a", "f")
+	if ds.Connected("a", "f") || ds.SamePartition(clone) {
+		t.Errorf("DisjointSet Clone shares state with the original")
+	}
+}
+
+func TestDisjointSetMany(t *testing.T) {
+	ds := set.Disjoint[int]()
+	for i := 0; i < 10000; i++ {
+		ds.Union(i, i%7)
+	}
+	if ds.Count() != 7 {
+		t.Errorf("DisjointSet has %d sets, expected 7", ds.Count())
+	}
+	for i := 0; i < 10000; i++ {
+		if !ds.Connected(i, i%7) || ds.Connected(i, (i+1)%7) {
+			t.Fatalf("DisjointSet puts %d in the wrong set", i)
+		}
+	}
+}
+
+func TestUndoDisjointSet(t *testing.T) {
+	ds := set.UndoDisjoint(1, 2, 3, 4)
+	ds.Union(1, 2)
+	check := ds.Checkpoint()
+	ds.Union(3, 4)
+	ds.Union(2, 3)
+	ds.Union(5, 1)
+	if ds.Count() != 1 || ds.SetSize(4) != 5 {
+		t.Errorf("UndoDisjointSet has %d sets, expected 1", ds.Count())
+	}
+
+	if !ds.Undo() {
+		t.Errorf("UndoDisjointSet Undo returned false")
+	}
+	// union of 5 and 1 undone, 5 is still there
+	if ds.Connected(5, 1) || !ds.Contains(5) || ds.SetSize(1) != 4 {
+		t.Errorf("UndoDisjointSet Undo did not revert the last union")
+	}
+
+	if err := ds.Rollback(check); err != nil {
+		t.Fatalf("UndoDisjointSet Rollback returned error %v", err)
+	}
+	if ds.Contains(5) || ds.Connected(2, 3) || ds.Connected(3, 4) || !ds.Connected(1, 2) {
+		t.Errorf("UndoDisjointSet Rollback did not restore the checkpoint: %s", ds)
+	}
+	if ds.Count() != 3 || ds.SetSize(1) != 2 {
+		t.Errorf("UndoDisjointSet has %d sets after Rollback, expected 3", ds.Count())
+	}
+	if err := ds.Rollback(check + 1); !errors.Is(err, errs.ErrIndexOutOfRange) {
+		t.Errorf("UndoDisjointSet Rollback to a future checkpoint returned no error")
+	}
+
+	ds.Rollback(0)
+	if !ds.Empty() || ds.Undo() {
+		t.Errorf("UndoDisjointSet Rollback to 0 left %s", ds)
+	}
+}
+
+func TestDisjointSetSamePartition(t *testing.T) {
+	ab := set.Disjoint("a", "b", "c", "d")
+	ab.Union("a", "b")
+	ab.Union("c", "d")
+	ac := set.Disjoint("a", "b", "c", "d")
+	ac.Union("a", "c")
+	ac.Union("b", "d")
+	if ab.SamePartition(ac) || ac.SamePartition(ab) {
+		t.Errorf("%s and %s have the same partition", ab, ac)
+	}
+	// as collections, only the elements matter
+	abcd := list.Arr("a", "b", "c", "d")
+	if ab.Cmp(ac) != 0 || ab.Cmp(abcd) != 0 || ac.Cmp(abcd) != 0 {
+		t.Errorf("%s, %s and %v are not equal as collections", ab, ac, abcd)
+	}
+	same := set.Disjoint("c", "d", "a", "b")
+	same.Union("d", "c")
+	same.Union("b", "a")
+	if !ab.SamePartition(same) || !same.SamePartition(ab) {
+		t.Errorf("%s and %s do not have the same partition", ab, same)
+	}
+	x, y := set.UndoDisjoint("a", "b"), set.UndoDisjoint("a", "c")
+	if x.SamePartition(y) || x.Cmp(y) >= 0 || y.Cmp(x) <= 0 {
+		t.Errorf("%s and %s compared with %d and %d", x, y, x.Cmp(y), y.Cmp(x))
+	}
+}
@@ -0,0 +1,68 @@
+package set_test
+
+import (
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func TestIntervalSetMerge(t *testing.T) {
+	s := set.Itv(tree.Ival(1, 3), tree.Ival(10, 12), tree.Ival(3, 5), tree.Ival(4, 7))
+	if s.String() != "IntervalSet{[1, 7), [10, 12)}" {
+		t.Errorf("IntervalSet is %s", s)
+	}
+	s.Add(tree.Ival(0, 20))
+	if s.Size() != 1 || !s.Contains(tree.Ival(0, 20)) {
+		t.Errorf("IntervalSet after covering Add is %s", s)
+	}
+
+	if err := s.Remove(tree.Ival(5, 8)); err != nil {
+		t.Errorf("IntervalSet Remove returned error %v", err)
+	}
+	if s.String() != "IntervalSet{[0, 5), [8, 20)}" {
+		t.Errorf("IntervalSet after Remove is %s", s)
+	}
+	if s.ContainsPoint(5) || !s.ContainsPoint(8) || s.ContainsPoint(20) {
+		t.Errorf("IntervalSet ContainsPoint is wrong")
+	}
+	if r, err := s.RangeOf(9); err != nil || *r != tree.Ival(8, 20) {
+		t.Errorf("IntervalSet RangeOf(9) is %v", r)
+	}
+	if err := s.Remove(tree.Ival(5, 8)); err == nil {
+		t.Errorf("IntervalSet Remove of missing range returned no error")
+	}
+	s.Remove(tree.Ival(-5, 100))
+	if !s.Empty() {
+		t.Errorf("IntervalSet after removing everything is %s", s)
+	}
+}
+
+func TestIntervalSetOperations(t *testing.T) {
+	a := set.Itv(tree.Ival(0, 10), tree.Ival(20, 30))
+	b := set.Itv(tree.Ival(5, 25), tree.Ival(28, 40))
+
+	if u := a.Union(b); u.String() != "IntervalSet{[0, 40)}" {
+		t.Errorf("IntervalSet Union is %s", u)
+	}
+	if i := a.Intersect(b); i.String() != "IntervalSet{[5, 10), [20, 25), [28, 30)}" {
+		t.Errorf("IntervalSet Intersect is %s", i)
+	}
+	if d := a.Difference(b); d.String() != "IntervalSet{[0, 5), [25, 28)}" {
+		t.Errorf("IntervalSet Difference is %s", d)
+	}
+	if c := a.Complement(-10, 50); c.String() != "IntervalSet{[-10, 0), [10, 20), [30, 50)}" {
+		t.Errorf("IntervalSet Complement is %s", c)
+	}
+	// operations make new sets
+	if a.String() != "IntervalSet{[0, 10), [20, 30)}" {
+		t.Errorf("IntervalSet operations modified the receiver: %s", a)
+	}
+
+	long := a.Subset(func(iv tree.Interval[int], _ ...any) bool {
+		return iv.Lo >= 20
+	})
+	if long.Size() != 1 || !long.Contains(tree.Ival(22, 28)) {
+		t.Errorf("IntervalSet Subset is %s", long)
+	}
+}
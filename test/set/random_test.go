@@ -0,0 +1,59 @@
+package set_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/set"
+)
+
+func TestRandomElement(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	sets := map[string]interface {
+		RandomElement(*rand.Rand) (*int, error)
+		Contains(int) bool
+	}{
+		"BitSet": set.Bits(3, 64, 65, 1000),
+		"HshSet": hashSetOf(3, 64, 65, 1000),
+		"RBSet":  rbSetOf(3, 64, 65, 1000),
+		"AVLSet": avlSetOf(3, 64, 65, 1000),
+	}
+	for name, s := range sets {
+		seen := make(map[int]bool)
+		for i := 0; i < 200; i++ {
+			v, err := s.RandomElement(r)
+			if err != nil || !s.Contains(*v) {
+				t.Fatalf("%s.RandomElement() = %v, %v", name, v, err)
+			}
+			seen[*v] = true
+		}
+		if len(seen) != 4 {
+			t.Errorf("%s.RandomElement() picked only %v", name, seen)
+		}
+	}
+	roaring := set.Roaring(7, 1<<20)
+	if v, err := roaring.RandomElement(r); err != nil || !roaring.Contains(*v) {
+		t.Errorf("RoaringBitmap.RandomElement() = %v, %v", v, err)
+	}
+	if _, err := set.Bits().RandomElement(r); err == nil {
+		t.Errorf("BitSet.RandomElement() of empty set returned no error")
+	}
+}
+
+func hashSetOf(values ...int) *set.HshSet[int] {
+	s := set.Hsh[int]()
+	s.Add(values...)
+	return s
+}
+
+func rbSetOf(values ...int) *set.RBSet[int] {
+	s := set.RB[int]()
+	s.Add(values...)
+	return s
+}
+
+func avlSetOf(values ...int) *set.AVLSet[int] {
+	s := set.AVL[int]()
+	s.Add(values...)
+	return s
+}
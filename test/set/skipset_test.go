@@ -0,0 +1,31 @@
+package set_test
+
+import (
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/set"
+)
+
+func TestSkipSet(t *testing.T) {
+	s := set.SkipSeeded[int](1)
+	s.Add(5, 3, 9, 3, 1)
+	if s.Size() != 4 {
+		t.Errorf("SkipSet size is %d, expected %d", s.Size(), 4)
+	}
+	if s.String() != "SkipSet{1, 3, 5, 9}" {
+		t.Errorf("SkipSet is %s, expected %s", s, "SkipSet{1, 3, 5, 9}")
+	}
+	if err := s.Remove(3); err != nil || s.Contains(3) {
+		t.Errorf("SkipSet Remove(%d) failed", 3)
+	}
+	if err := s.Remove(3); err == nil {
+		t.Errorf("SkipSet Remove(%d) of missing value did not fail", 3)
+	}
+
+	odd := s.Subset(func(v int, _ ...any) bool {
+		return v%2 == 1
+	})
+	if odd.Size() != 3 || odd.Contains(4) {
+		t.Errorf("SkipSet Subset is %s", odd)
+	}
+}
@@ -0,0 +1,264 @@
+package sketch_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/sketch"
+)
+
+func TestBloomFilter(t *testing.T) {
+	bf := sketch.Bloom[int](1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		bf.Add(i)
+	}
+	for i := 0; i < 1000; i++ {
+		if !bf.Contains(i) {
+			t.Fatalf("BloomFilter does not contain added element %d", i)
+		}
+	}
+	fp := 0
+	for i := 1000; i < 11000; i++ {
+		if bf.Contains(i) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / 10000; rate > 0.02 {
+		t.Errorf("BloomFilter false positive rate is %v, expected about 0.01", rate)
+	}
+
+	other := sketch.Bloom[int](1000, 0.01)
+	other.Add(-1, -2)
+	if err := bf.Merge(other); err != nil || !bf.Contains(-2) {
+		t.Errorf("BloomFilter Merge did not add the other elements, error %v", err)
+	}
+	if err := bf.Merge(sketch.Bloom[int](10, 0.01)); err == nil {
+		t.Errorf("BloomFilter Merge of different sizes returned no error")
+	}
+
+	data, _ := bf.MarshalBinary()
+	restored := sketch.Bloom[int](1, 0.5)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("BloomFilter UnmarshalBinary returned error %v", err)
+	}
+	if restored.Bits() != bf.Bits() || !restored.Contains(500) || !restored.Contains(-1) {
+		t.Errorf("BloomFilter serialization lost data: %s", restored)
+	}
+	if err := restored.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("BloomFilter UnmarshalBinary of truncated data returned no error")
+	}
+	bf.Clear()
+	if bf.Contains(1) || bf.FalsePositiveRate() != 0 {
+		t.Errorf("BloomFilter Clear left elements")
+	}
+}
+
+func TestCountingBloomFilter(t *testing.T) {
+	cbf := sketch.CountingBloom[string](100, 0.01)
+	cbf.Add("a", "b", "a")
+	if err := cbf.Remove("a"); err != nil || !cbf.Contains("a") {
+		t.Errorf("CountingBloomFilter lost an element added twice after one Remove")
+	}
+	cbf.Remove("a")
+	if cbf.Contains("a") || !cbf.Contains("b") {
+		t.Errorf("CountingBloomFilter Remove is wrong")
+	}
+	if err := cbf.Remove("z"); err == nil {
+		t.Errorf("CountingBloomFilter Remove of missing element returned no error")
+	}
+	data, _ := cbf.MarshalBinary()
+	restored := sketch.CountingBloom[string](1, 0.5)
+	if err := restored.UnmarshalBinary(data); err != nil || !restored.Contains("b") {
+		t.Errorf("CountingBloomFilter serialization lost data, error %v", err)
+	}
+	if err := sketch.Bloom[string](100, 0.01).UnmarshalBinary(data); err == nil {
+		t.Errorf("BloomFilter UnmarshalBinary of a counting filter returned no error")
+	}
+}
+
+func TestCuckooFilter(t *testing.T) {
+	cf := sketch.Cuckoo[string](1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		if err := cf.Add(fmt.Sprint("key", i)); err != nil {
+			t.Fatalf("CuckooFilter Add failed at %d: %v", i, err)
+		}
+	}
+	if cf.Size() != 1000 {
+		t.Errorf("CuckooFilter has size %d, expected 1000", cf.Size())
+	}
+	fp := 0
+	for i := 0; i < 1000; i++ {
+		if !cf.Contains(fmt.Sprint("key", i)) {
+			t.Fatalf("CuckooFilter does not contain added element %d", i)
+		}
+		if cf.Contains(fmt.Sprint("other", i)) {
+			fp++
+		}
+	}
+	if fp > 30 {
+		t.Errorf("CuckooFilter has %d false positives out of 1000", fp)
+	}
+	for i := 0; i < 500; i++ {
+		if err := cf.Remove(fmt.Sprint("key", i)); err != nil {
+			t.Fatalf("CuckooFilter Remove of %d returned error %v", i, err)
+		}
+	}
+	for i := 500; i < 1000; i++ {
+		if !cf.Contains(fmt.Sprint("key", i)) {
+			t.Fatalf("CuckooFilter lost element %d after removals", i)
+		}
+	}
+
+	data, _ := cf.MarshalBinary()
+	restored := sketch.Cuckoo[string](1, 0.5)
+	if err := restored.UnmarshalBinary(data); err != nil || restored.Size() != 500 {
+		t.Errorf("CuckooFilter serialization lost data, error %v", err)
+	}
+	other := sketch.Cuckoo[string](1000, 0.01)
+	other.Add("key0")
+	if err := restored.Merge(other); err != nil || !restored.Contains("key0") {
+		t.Errorf("CuckooFilter Merge did not add the other elements, error %v", err)
+	}
+}
+
+func TestCuckooFilterFull(t *testing.T) {
+	cf := sketch.Cuckoo[int](8, 0.01)
+	added := 0
+	for i := 0; i < 100; i++ {
+		err := cf.Add(i)
+		if err == nil {
+			added++
+		} else if !errors.Is(err, errs.ErrCapacityExceeded) {
+			t.Errorf("CuckooFilter Add to a full filter returned %v, expected capacity exceeded", err)
+		}
+	}
+	if added > cf.Capacity() || cf.Size() != added {
+		t.Errorf("CuckooFilter added %d elements with capacity %d", added, cf.Capacity())
+	}
+	// failed insertions must not push out the elements already there
+	missing := 0
+	for i := 0; i < 100; i++ {
+		if !cf.Contains(i) {
+			missing++
+		}
+	}
+	if 100-missing < added {
+		t.Errorf("CuckooFilter lost elements when full")
+	}
+}
+
+func TestCuckooFilterDecodedFills(t *testing.T) {
+	data, _ := sketch.Cuckoo[int](10000, 0.01).MarshalBinary()
+	cf := new(sketch.CuckooFilter[int])
+	if err := cf.UnmarshalBinary(data); err != nil {
+		t.Fatalf("CuckooFilter UnmarshalBinary returned %v", err)
+	}
+	for i := 0; cf.Add(i) == nil; i++ {
+	}
+	// kicks choose random slots, otherwise the filter fills much less
+	if load := float64(cf.Size()) / float64(cf.Capacity()); load < 0.9 {
+		t.Errorf("decoded CuckooFilter is full at load %.2f, expected at least 0.9", load)
+	}
+}
+
+func TestCountMinSketch(t *testing.T) {
+	cms := sketch.CountMin[int](0.001, 0.01)
+	for i := 0; i < 1000; i++ {
+		cms.Add(i, uint64(i%10+1))
+	}
+	// each estimate exceeds the bound with probability at most delta
+	over := 0
+	for i := 0; i < 1000; i++ {
+		count := cms.Count(i)
+		if count < uint64(i%10+1) {
+			t.Fatalf("CountMinSketch Count(%d) is %d, less than %d", i, count, i%10+1)
+		}
+		if count > uint64(i%10+1)+uint64(0.001*float64(cms.Total())) {
+			over++
+		}
+	}
+	if over > 30 {
+		t.Errorf("CountMinSketch has %d estimates out of 1000 over the error bound", over)
+	}
+	other := sketch.CountMin[int](0.001, 0.01)
+	other.Add(7, 100)
+	cms.Merge(other)
+	if cms.Count(7) < 108 {
+		t.Errorf("CountMinSketch Merge did not add the counts")
+	}
+	data, _ := cms.MarshalBinary()
+	restored := sketch.CountMin[int](0.5, 0.5)
+	if err := restored.UnmarshalBinary(data); err != nil || restored.Count(7) != cms.Count(7) || restored.Total() != cms.Total() {
+		t.Errorf("CountMinSketch serialization lost data, error %v", err)
+	}
+}
+
+func TestHyperLogLog(t *testing.T) {
+	hll := sketch.HLL[int](0.01)
+	if hll.Count() != 0 {
+		t.Errorf("HyperLogLog of no elements estimates %d", hll.Count())
+	}
+	for _, n := range []int{100, 10000, 200000} {
+		hll.Clear()
+		for i := 0; i < n; i++ {
+			hll.Add(i, i)
+		}
+		est := float64(hll.Count())
+		if math.Abs(est-float64(n))/float64(n) > 3*hll.StdError() {
+			t.Errorf("HyperLogLog estimates %v distinct elements, expected %d", est, n)
+		}
+	}
+
+	a, b := sketch.HLL[string](0.02), sketch.HLL[string](0.02)
+	for i := 0; i < 5000; i++ {
+		a.Add(fmt.Sprint(i))
+		b.Add(fmt.Sprint(i + 2500))
+	}
+	a.Merge(b)
+	if est := float64(a.Count()); math.Abs(est-7500)/7500 > 3*a.StdError() {
+		t.Errorf("HyperLogLog Merge estimates %v distinct elements, expected 7500", est)
+	}
+	if err := a.Merge(sketch.HLL[string](0.1)); err == nil {
+		t.Errorf("HyperLogLog Merge of different precisions returned no error")
+	}
+	data, _ := a.MarshalBinary()
+	restored := sketch.HLL[string](0.5)
+	if err := restored.UnmarshalBinary(data); err != nil || restored.Count() != a.Count() {
+		t.Errorf("HyperLogLog serialization lost data, error %v", err)
+	}
+}
+
+// serialized data with the given header fields, after the kind byte of
+// the original, and no payload
+func corruptHeader(original []byte, fields ...uint64) []byte {
+	data := []byte{original[0]}
+	for _, field := range fields {
+		data = binary.LittleEndian.AppendUint64(data, field)
+	}
+	return data
+}
+
+func TestUnmarshalCorruptHeader(t *testing.T) {
+	bf := sketch.Bloom[int](100, 0.01)
+	data, _ := bf.MarshalBinary()
+	for _, m := range []uint64{1 << 62, 64 << 30} {
+		if err := bf.UnmarshalBinary(corruptHeader(data, m, 3)); err == nil {
+			t.Errorf("BloomFilter UnmarshalBinary with m = %d and no bits returned no error", m)
+		}
+	}
+	cms := sketch.CountMin[int](0.1, 0.1)
+	data, _ = cms.MarshalBinary()
+	// 2^32 * 2^32 overflows to 0, like the length of the payload
+	if err := cms.UnmarshalBinary(corruptHeader(data, 1<<32, 1<<32, 0)); err == nil {
+		t.Errorf("CountMinSketch UnmarshalBinary with an overflowing size returned no error")
+	}
+	cf := sketch.Cuckoo[int](100, 0.01)
+	data, _ = cf.MarshalBinary()
+	if err := cf.UnmarshalBinary(corruptHeader(data, 1<<61, 8, 0)); err == nil {
+		t.Errorf("CuckooFilter UnmarshalBinary with an overflowing size returned no error")
+	}
+}
@@ -0,0 +1,125 @@
+package table_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func TestBTreeMapPutGetRemove(t *testing.T) {
+	bm := table.B[string, bool](2)
+	for i, k := range rb_keys {
+		bm.Put(k, rb_vals[i])
+	}
+	bm.Put("ciao", false)
+	if bm.Size() != len(rb_keys) {
+		t.Errorf("BTreeMap size is %d, expected %d", bm.Size(), len(rb_keys))
+	}
+	val, err := bm.Get("ciao")
+	if err != nil || *val != false {
+		t.Errorf("BTreeMap Get(%s) did not return the new value", "ciao")
+	}
+
+	prev := ""
+	bm.Keys().Each(func(k string) {
+		if tau.ASCmp(prev, k) > 0 {
+			t.Errorf("BTreeMap keys are not sorted: %s after %s", k, prev)
+		}
+		prev = k
+	})
+
+	for _, k := range rb_keys {
+		if _, err := bm.Remove(k); err != nil {
+			t.Errorf("BTreeMap Remove(%s) failed", k)
+		}
+	}
+	if !bm.Empty() {
+		t.Errorf("BTreeMap size is %d, expected %d", bm.Size(), 0)
+	}
+}
+
+func TestBTreeMapRange(t *testing.T) {
+	bm := table.B[int, int](4)
+	for i := 0; i < 1000; i++ {
+		bm.Put(i*2, i)
+	}
+	sum, count := 0, 0
+	bm.RangeValues(100, 200).Each(func(v int) {
+		sum += v
+		count++
+	})
+	if count != 50 || sum != (50+99)*50/2 {
+		t.Errorf("BTreeMap RangeValues(100, 200) returned %d values summing to %d", count, sum)
+	}
+	min, _ := bm.Min()
+	max, _ := bm.Max()
+	if *min != 0 || *max != 1998 {
+		t.Errorf("BTreeMap Min() is %d and Max() is %d", *min, *max)
+	}
+}
+
+// --- Benchmarks ---
+const benchSize = 100000
+
+func benchKeys() []int {
+	source := rand.New(rand.NewSource(1))
+	return source.Perm(benchSize)
+}
+
+func benchPut(b *testing.B, newMap func() tau.Map[int, int]) {
+	keys := benchKeys()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := newMap()
+		for _, k := range keys {
+			m.Put(k, k)
+		}
+	}
+}
+
+func benchGet(b *testing.B, m tau.Map[int, int]) {
+	keys := benchKeys()
+	for _, k := range keys {
+		m.Put(k, k)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(keys[i%benchSize])
+	}
+}
+
+func benchIter(b *testing.B, m tau.Map[int, int]) {
+	for _, k := range benchKeys() {
+		m.Put(k, k)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Values().Each(func(int) {})
+	}
+}
+
+func BenchmarkBTreeMapPut(b *testing.B) {
+	benchPut(b, func() tau.Map[int, int] { return table.B[int, int](32) })
+}
+
+func BenchmarkRBMapPut(b *testing.B) {
+	benchPut(b, func() tau.Map[int, int] { return table.RB[int, int]() })
+}
+
+func BenchmarkBTreeMapGet(b *testing.B) {
+	benchGet(b, table.B[int, int](32))
+}
+
+func BenchmarkRBMapGet(b *testing.B) {
+	benchGet(b, table.RB[int, int]())
+}
+
+func BenchmarkBTreeMapIter(b *testing.B) {
+	benchIter(b, table.B[int, int](32))
+}
+
+func BenchmarkRBMapIter(b *testing.B) {
+	benchIter(b, table.RB[int, int]())
+}
@@ -0,0 +1,161 @@
+package table_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luverolla/lexgo/pkg/table"
+)
+
+// clock moved by hand, with a ticker firing at every Advance
+type fakeClock struct {
+	now  time.Time
+	tick chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{time.Unix(0, 0), nil}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *fakeClock) Tick(time.Duration) (<-chan time.Time, func()) {
+	clock.tick = make(chan time.Time)
+	return clock.tick, func() {}
+}
+
+// moves the time forward and, if ticking, waits for the tick to be received
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.now = clock.now.Add(d)
+	if clock.tick != nil {
+		clock.tick <- clock.now
+	}
+}
+
+func TestExpiringPutWithTTL(t *testing.T) {
+	clock := newFakeClock()
+	em := table.Exp[string, int](time.Minute, clock)
+
+	em.Put("default", 1)
+	em.PutWithTTL("short", 2, time.Second)
+	em.PutWithTTL("forever", 3, 0)
+
+	if em.Size() != 3 {
+		t.Errorf("Expiring size is %d, expected %d", em.Size(), 3)
+	}
+
+	clock.Advance(2 * time.Second)
+	if em.HasKey("short") {
+		t.Errorf("Expiring has expired key %s", "short")
+	}
+	if _, err := em.Get("short"); err == nil {
+		t.Errorf("Expiring Get(%s) of expired key did not fail", "short")
+	}
+	if em.Size() != 2 {
+		t.Errorf("Expiring size is %d, expected %d", em.Size(), 2)
+	}
+
+	clock.Advance(time.Hour)
+	keys := make([]string, 0)
+	em.Keys().Each(func(k string) {
+		keys = append(keys, k)
+	})
+	if len(keys) != 1 || keys[0] != "forever" {
+		t.Errorf("Expiring keys are %v, expected %v", keys, []string{"forever"})
+	}
+}
+
+func TestExpiringOverwrite(t *testing.T) {
+	clock := newFakeClock()
+	em := table.Exp[string, int](0, clock)
+
+	em.PutWithTTL("k", 1, time.Second)
+	em.PutWithTTL("k", 2, time.Minute)
+	clock.Advance(2 * time.Second)
+
+	val, err := em.Get("k")
+	if err != nil || *val != 2 {
+		t.Errorf("Expiring Get(%s) failed after overwrite", "k")
+	}
+	ttl, _ := em.TTL("k")
+	if ttl != time.Minute-2*time.Second {
+		t.Errorf("Expiring TTL(%s) is %v, expected %v", "k", ttl, time.Minute-2*time.Second)
+	}
+
+	if _, err := em.Remove("k"); err != nil {
+		t.Errorf("Expiring Remove(%s) failed", "k")
+	}
+	clock.Advance(time.Hour)
+	if em.Sweep() != 0 {
+		t.Errorf("Expiring Sweep() reclaimed a removed key")
+	}
+}
+
+func TestExpiringSweep(t *testing.T) {
+	clock := newFakeClock()
+	em := table.Exp[int, int](0, clock)
+	for i := 0; i < 100; i++ {
+		em.PutWithTTL(i, i, time.Duration(i+1)*time.Second)
+	}
+
+	clock.Advance(50 * time.Second)
+	if n := em.Sweep(); n != 50 {
+		t.Errorf("Expiring Sweep() reclaimed %d entries, expected %d", n, 50)
+	}
+	if em.Size() != 50 {
+		t.Errorf("Expiring size is %d, expected %d", em.Size(), 50)
+	}
+}
+
+func TestExpiringSweeper(t *testing.T) {
+	clock := newFakeClock()
+	em := table.Exp[int, int](time.Second, clock)
+	em.Put(1, 1)
+	em.StartSweeper(time.Second)
+	clock.Advance(2 * time.Second)
+	// the sweep triggered by the tick is over once the sweeper is stopped
+	em.StopSweeper()
+	em.StopSweeper()
+
+	// Size reclaims by itself, so a manual sweep tells if the sweeper ran
+	if n := em.Sweep(); n != 0 {
+		t.Errorf("Expiring Sweep() reclaimed %d entries left by the sweeper", n)
+	}
+	if em.Size() != 0 {
+		t.Errorf("Expiring size is %d, expected %d", em.Size(), 0)
+	}
+}
+
+func TestExpiringConcurrent(t *testing.T) {
+	maps := []*table.Expiring[string, int]{
+		table.Exp[string, int](time.Hour, nil),
+		table.Exp[string, int](time.Hour, nil),
+	}
+	var wg sync.WaitGroup
+	for _, em := range maps {
+		for g := 0; g < 4; g++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					em.Put(fmt.Sprint(g, i), i)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				em.StartSweeper(time.Hour)
+			}()
+		}
+	}
+	wg.Wait()
+	for _, em := range maps {
+		em.StopSweeper()
+		if em.Size() != 400 {
+			t.Errorf("Expiring size is %d, expected %d", em.Size(), 400)
+		}
+	}
+}
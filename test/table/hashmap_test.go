@@ -1,6 +1,7 @@
 package table_test
 
 import (
+	"math/rand/v2"
 	"testing"
 
 	"github.com/luverolla/lexgo/pkg/table"
@@ -91,3 +92,57 @@ func TestHashMapIter(t *testing.T) {
 		}
 	}
 }
+
+func TestHashMapManyKeys(t *testing.T) {
+	hm := table.Hsh[int, int]()
+	for i := 0; i < 1000; i++ {
+		hm.Put(i*31, i)
+	}
+	for i := 0; i < 1000; i += 2 {
+		hm.Remove(i * 31)
+	}
+	for i := 0; i < 1000; i++ {
+		hm.Put(i*31, -i)
+	}
+
+	if hm.Size() != 1000 {
+		t.Errorf("HashMap size is %d, expected %d", hm.Size(), 1000)
+	}
+	for i := 0; i < 1000; i++ {
+		val, err := hm.Get(i * 31)
+		if err != nil || *val != -i {
+			t.Errorf("HashMap Get(%d) failed", i*31)
+		}
+	}
+}
+
+func TestHashMapRandomElement(t *testing.T) {
+	hm := table.Hsh[int, int]()
+	if _, err := hm.RandomElement(nil); err == nil {
+		t.Errorf("RandomElement() of empty map returned no error")
+	}
+	for i := 0; i < 1000; i++ {
+		hm.Put(i, i)
+	}
+	// only a few keys are left, so most of the slots are free
+	for i := 0; i < 995; i++ {
+		hm.Remove(i)
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	counts := make(map[int]int)
+	for i := 0; i < 5000; i++ {
+		key, err := hm.RandomElement(r)
+		if err != nil {
+			t.Fatalf("RandomElement() returned error %v", err)
+		}
+		counts[*key]++
+	}
+	for key := 995; key < 1000; key++ {
+		if counts[key] < 800 || counts[key] > 1200 {
+			t.Errorf("RandomElement() picked %d %d times out of 5000, want about 1000", key, counts[key])
+		}
+	}
+	if len(counts) != 5 {
+		t.Errorf("RandomElement() picked removed keys: %v", counts)
+	}
+}
@@ -79,3 +79,22 @@ func TestRBMapIter(t *testing.T) {
 		}
 	}
 }
+
+func TestRBMapIterAll(t *testing.T) {
+	tm := table.RB[int, int]()
+	for i := 0; i < 100; i++ {
+		tm.Put((i*37)%100, i)
+	}
+
+	count, prev := 0, -1
+	tm.Keys().Each(func(k int) {
+		if k <= prev {
+			t.Errorf("RBMap keys are not sorted: %d after %d", k, prev)
+		}
+		prev = k
+		count++
+	})
+	if count != 100 {
+		t.Errorf("RBMap iterated %d keys, expected %d", count, 100)
+	}
+}
@@ -0,0 +1,172 @@
+package table_test
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+)
+
+func TestSkipMapPutGetRemove(t *testing.T) {
+	sm := table.SkipSeeded[string, bool](1)
+	for i, k := range rb_keys {
+		sm.Put(k, rb_vals[i])
+	}
+	if sm.Size() != len(rb_keys) {
+		t.Errorf("SkipMap size is %d, expected %d", sm.Size(), len(rb_keys))
+	}
+	for i, k := range rb_keys {
+		val, err := sm.Get(k)
+		if err != nil || *val != rb_vals[i] {
+			t.Errorf("SkipMap Get(%s) failed", k)
+		}
+	}
+	for _, k := range rb_keys {
+		if _, err := sm.Remove(k); err != nil {
+			t.Errorf("SkipMap Remove(%s) failed", k)
+		}
+		if sm.HasKey(k) {
+			t.Errorf("SkipMap still contains key %s", k)
+		}
+	}
+	if !sm.Empty() {
+		t.Errorf("SkipMap size is %d, expected %d", sm.Size(), 0)
+	}
+}
+
+func TestSkipMapSorted(t *testing.T) {
+	sm := table.SkipSeeded[int, int](42)
+	present := make(map[int]int)
+	source := rand.New(rand.NewSource(7))
+	for i := 0; i < 1000; i++ {
+		k := source.Intn(500)
+		sm.Put(k, i)
+		present[k] = i
+	}
+	expected := make([]int, 0, len(present))
+	for k := range present {
+		expected = append(expected, k)
+	}
+	sort.Ints(expected)
+
+	if sm.Size() != len(expected) {
+		t.Errorf("SkipMap size is %d, expected %d", sm.Size(), len(expected))
+	}
+	index := 0
+	sm.Keys().Each(func(k int) {
+		if index >= len(expected) || k != expected[index] {
+			t.Fatalf("SkipMap key %d is %d", index, k)
+		}
+		val, _ := sm.Get(k)
+		if *val != present[k] {
+			t.Errorf("SkipMap Get(%d) is %d, expected %d", k, *val, present[k])
+		}
+		index++
+	})
+
+	count := 0
+	sm.Range(100, 200).Each(func(k int) {
+		if k < 100 || k >= 200 {
+			t.Errorf("SkipMap Range(100, 200) returned %d", k)
+		}
+		count++
+	})
+	inRange := 0
+	for _, k := range expected {
+		if k >= 100 && k < 200 {
+			inRange++
+		}
+	}
+	if count != inRange {
+		t.Errorf("SkipMap Range(100, 200) returned %d keys, expected %d", count, inRange)
+	}
+}
+
+func TestSkipMapSeeded(t *testing.T) {
+	a, b := table.SkipSeeded[int, int](3), table.SkipSeeded[int, int](3)
+	for i := 0; i < 100; i++ {
+		a.Put(i, i)
+		b.Put(i, i)
+	}
+	if a.String() != b.String() || !tau.Eq(a, b) {
+		t.Errorf("SkipMaps with the same seed differ")
+	}
+}
+
+func TestConcSkipMap(t *testing.T) {
+	cm := table.ConcSkipSeeded[int, int](1)
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < 2000; i += 8 {
+				cm.Put(i, i*2)
+			}
+			for i := w; i < 2000; i += 16 {
+				if _, err := cm.Remove(i); err != nil {
+					t.Errorf("ConcSkipMap Remove(%d) failed", i)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if cm.Size() != 1000 {
+		t.Errorf("ConcSkipMap size is %d, expected %d", cm.Size(), 1000)
+	}
+	prev := -1
+	cm.Keys().Each(func(k int) {
+		if k <= prev {
+			t.Errorf("ConcSkipMap keys are not sorted: %d after %d", k, prev)
+		}
+		prev = k
+	})
+	for i := 0; i < 2000; i++ {
+		removed := i%16 < 8
+		val, err := cm.Get(i)
+		if removed && err == nil {
+			t.Errorf("ConcSkipMap still contains key %d", i)
+		}
+		if !removed && (err != nil || *val != i*2) {
+			t.Errorf("ConcSkipMap Get(%d) failed", i)
+		}
+	}
+}
+
+func TestConcSkipMapContended(t *testing.T) {
+	cm := table.ConcSkip[int, int]()
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			source := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < 2000; i++ {
+				k := source.Intn(64)
+				switch source.Intn(3) {
+				case 0:
+					cm.Put(k, k)
+				case 1:
+					cm.Remove(k)
+				default:
+					if val, err := cm.Get(k); err == nil && *val != k {
+						t.Errorf("ConcSkipMap Get(%d) is %d", k, *val)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	count := 0
+	cm.Keys().Each(func(k int) {
+		count++
+	})
+	if count != cm.Size() {
+		t.Errorf("ConcSkipMap size is %d, but iterated %d keys", cm.Size(), count)
+	}
+}
@@ -0,0 +1,115 @@
+package tree_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func checkBTree(t *testing.T, bt *tree.BTree[int], expected map[int]bool) {
+	keys := make([]int, 0, len(expected))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	if bt.Size() != len(keys) {
+		t.Fatalf("BTree size is %d, expected %d", bt.Size(), len(keys))
+	}
+	index := 0
+	bt.InOrder().Each(func(val int) {
+		if index >= len(keys) || val != keys[index] {
+			t.Fatalf("BTree element %d is %d", index, val)
+		}
+		index++
+	})
+	if index != len(keys) {
+		t.Fatalf("BTree iterated %d elements, expected %d", index, len(keys))
+	}
+}
+
+func TestBTreeInsertRemove(t *testing.T) {
+	for _, degree := range []int{2, 3, 16} {
+		bt := tree.B[int](degree)
+		expected := make(map[int]bool)
+		source := rand.New(rand.NewSource(int64(degree)))
+
+		for i := 0; i < 3000; i++ {
+			val := source.Intn(1000)
+			added := bt.Insert(val)
+			if added == expected[val] {
+				t.Fatalf("BTree Insert(%d) returned %v", val, added)
+			}
+			expected[val] = true
+		}
+		checkBTree(t, bt, expected)
+
+		for i := 0; i < 3000; i++ {
+			val := source.Intn(1000)
+			err := bt.Remove(val)
+			if (err == nil) != expected[val] {
+				t.Fatalf("BTree Remove(%d) returned %v", val, err)
+			}
+			delete(expected, val)
+		}
+		checkBTree(t, bt, expected)
+
+		for val := range expected {
+			bt.Remove(val)
+		}
+		if !bt.Empty() || bt.Height() != 1 {
+			t.Errorf("BTree size is %d and height %d after removing all", bt.Size(), bt.Height())
+		}
+	}
+}
+
+func TestBTreeNavigation(t *testing.T) {
+	bt := tree.B[int](2)
+	for i := 0; i < 100; i += 10 {
+		bt.Insert(i)
+	}
+
+	min, _ := bt.Min()
+	max, _ := bt.Max()
+	if *min != 0 || *max != 90 {
+		t.Errorf("BTree Min() is %d and Max() is %d, expected %d and %d", *min, *max, 0, 90)
+	}
+
+	pred, err := bt.Pred(35)
+	if err != nil || *pred != 30 {
+		t.Errorf("BTree Pred(%d) failed", 35)
+	}
+	pred, err = bt.Pred(30)
+	if err != nil || *pred != 20 {
+		t.Errorf("BTree Pred(%d) failed", 30)
+	}
+	if _, err = bt.Pred(0); err == nil {
+		t.Errorf("BTree Pred(%d) did not fail", 0)
+	}
+	pred, _ = bt.Pred(1000)
+	if *pred != 90 {
+		t.Errorf("BTree Pred(%d) is %d, expected %d", 1000, *pred, 90)
+	}
+
+	succ, err := bt.Succ(30)
+	if err != nil || *succ != 40 {
+		t.Errorf("BTree Succ(%d) failed", 30)
+	}
+	succ, _ = bt.Succ(-5)
+	if *succ != 0 {
+		t.Errorf("BTree Succ(%d) is %d, expected %d", -5, *succ, 0)
+	}
+	if _, err = bt.Succ(90); err == nil {
+		t.Errorf("BTree Succ(%d) did not fail", 90)
+	}
+
+	got := make([]int, 0)
+	bt.Range(25, 60).Each(func(val int) {
+		got = append(got, val)
+	})
+	if len(got) != 3 || got[0] != 30 || got[2] != 50 {
+		t.Errorf("BTree Range(25, 60) is %v, expected %v", got, []int{30, 40, 50})
+	}
+}
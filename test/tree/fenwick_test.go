@@ -0,0 +1,85 @@
+package tree_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func sliceSum(data []int, from, to int) int {
+	sum := 0
+	for i := from; i < to; i++ {
+		sum += data[i]
+	}
+	return sum
+}
+
+func TestFenwickTree(t *testing.T) {
+	ft := tree.Fenwick[int](list.Arr(3, 1, 4, 1, 5, 9, 2, 6))
+	if ft.Size() != 8 {
+		t.Errorf("FenwickTree has size %d, expected 8", ft.Size())
+	}
+	if sum, _ := ft.PrefixSum(4); sum != 9 {
+		t.Errorf("FenwickTree PrefixSum(4) is %d, expected 9", sum)
+	}
+	if sum, _ := ft.RangeSum(2, 6); sum != 19 {
+		t.Errorf("FenwickTree RangeSum(2, 6) is %d, expected 19", sum)
+	}
+	ft.Set(5, 0)
+	if value, _ := ft.Get(5); value != 0 {
+		t.Errorf("FenwickTree Get(5) is %d after Set, expected 0", value)
+	}
+	// prefix sums are now 3, 4, 8, 9, 14, 14, 16, 22
+	if n := ft.Search(14); n != 5 {
+		t.Errorf("FenwickTree Search(14) is %d, expected 5", n)
+	}
+	if n := ft.Search(23); n != 9 {
+		t.Errorf("FenwickTree Search(23) is %d, expected 9", n)
+	}
+	if _, err := ft.Get(8); !errors.Is(err, errs.ErrIndexOutOfRange) {
+		t.Errorf("FenwickTree Get out of range returned no error")
+	}
+	if err := ft.Add(-1, 1); !errors.Is(err, errs.ErrIndexOutOfRange) {
+		t.Errorf("FenwickTree Add out of range returned no error")
+	}
+	if _, err := ft.RangeSum(5, 3); err == nil {
+		t.Errorf("FenwickTree RangeSum of reversed range returned no error")
+	}
+}
+
+func TestFenwickTreeRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(35))
+	data := make([]int, 200)
+	for i := range data {
+		data[i] = rnd.Intn(100) - 50
+	}
+	ft := tree.Fenwick[int](list.Arr(data...))
+	rft := tree.RangeFenwick[int](list.Arr(data...))
+	for step := 0; step < 2000; step++ {
+		from := rnd.Intn(len(data) + 1)
+		to := from + rnd.Intn(len(data)-from+1)
+		if sum, _ := ft.RangeSum(from, to); sum != sliceSum(data, from, to) {
+			t.Fatalf("FenwickTree RangeSum(%d, %d) is %d, expected %d", from, to, sum, sliceSum(data, from, to))
+		}
+		if sum, _ := rft.RangeSum(from, to); sum != sliceSum(data, from, to) {
+			t.Fatalf("RangeFenwickTree RangeSum(%d, %d) is %d, expected %d", from, to, sum, sliceSum(data, from, to))
+		}
+		delta := rnd.Intn(20) - 10
+		if step%2 == 0 {
+			index := rnd.Intn(len(data))
+			data[index] += delta
+			ft.Add(index, delta)
+			rft.AddRange(index, index+1, delta)
+		} else {
+			rft.AddRange(from, to, delta)
+			for i := from; i < to; i++ {
+				data[i] += delta
+				ft.Add(i, delta)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package tree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/tau"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func collectIvs(iter tau.Iterator[tree.Interval[int]]) []tree.Interval[int] {
+	res := make([]tree.Interval[int], 0)
+	iter.Each(func(iv tree.Interval[int]) {
+		res = append(res, iv)
+	})
+	return res
+}
+
+func TestIntervalTreeQueries(t *testing.T) {
+	it := tree.Itv[int]()
+	it.Insert(tree.Ival(15, 20))
+	it.Insert(tree.Ival(10, 30))
+	it.Insert(tree.Ival(17, 19))
+	it.Insert(tree.Ival(5, 20))
+	it.Insert(tree.Ival(12, 15))
+	it.Insert(tree.Ival(30, 40))
+	if it.Insert(tree.Ival(12, 15)) || it.Insert(tree.Ival(3, 3)) {
+		t.Errorf("IntervalTree Insert of duplicate or empty interval returned true")
+	}
+	if it.Size() != 6 {
+		t.Errorf("IntervalTree size is %d, expected 6", it.Size())
+	}
+
+	got := collectIvs(it.Overlapping(19, 31))
+	want := []tree.Interval[int]{tree.Ival(5, 20), tree.Ival(10, 30), tree.Ival(15, 20), tree.Ival(30, 40)}
+	if len(got) != len(want) {
+		t.Fatalf("IntervalTree Overlapping(19, 31) is %v, expected %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("IntervalTree Overlapping(19, 31) is %v, expected %v", got, want)
+		}
+	}
+	// half-open: 30 is in [30, 40) but not in [10, 30)
+	if got := collectIvs(it.Stabbing(30)); len(got) != 1 || got[0] != tree.Ival(30, 40) {
+		t.Errorf("IntervalTree Stabbing(30) is %v", got)
+	}
+	if got := collectIvs(it.Overlapping(25, 25)); len(got) != 0 {
+		t.Errorf("IntervalTree Overlapping of an empty range is %v", got)
+	}
+
+	if !it.Remove(tree.Ival(10, 30)) || it.Remove(tree.Ival(10, 30)) {
+		t.Errorf("IntervalTree Remove returned the wrong result")
+	}
+	if got := collectIvs(it.Stabbing(25)); len(got) != 0 {
+		t.Errorf("IntervalTree Stabbing(25) after Remove is %v", got)
+	}
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	it := tree.Itv[int]()
+	ref := make(map[tree.Interval[int]]bool)
+	for i := 0; i < 3000; i++ {
+		lo := rng.Intn(1000)
+		iv := tree.Ival(lo, lo+1+rng.Intn(50))
+		if rng.Intn(3) == 0 && len(ref) > 0 {
+			for k := range ref {
+				iv = k
+				break
+			}
+			it.Remove(iv)
+			delete(ref, iv)
+		} else {
+			it.Insert(iv)
+			ref[iv] = true
+		}
+		if i%100 != 0 {
+			continue
+		}
+		qlo := rng.Intn(1000)
+		qhi := qlo + rng.Intn(30)
+		count := 0
+		for k := range ref {
+			if k.Overlaps(tree.Ival(qlo, qhi)) {
+				count++
+			}
+		}
+		got := collectIvs(it.Overlapping(qlo, qhi))
+		if len(got) != count {
+			t.Fatalf("IntervalTree Overlapping(%d, %d) returned %d intervals, expected %d", qlo, qhi, len(got), count)
+		}
+		stab := 0
+		for k := range ref {
+			if k.Contains(qlo) {
+				stab++
+			}
+		}
+		if got := collectIvs(it.Stabbing(qlo)); len(got) != stab {
+			t.Fatalf("IntervalTree Stabbing(%d) returned %d intervals, expected %d", qlo, len(got), stab)
+		}
+	}
+	if it.Size() != len(ref) {
+		t.Errorf("IntervalTree size is %d, expected %d", it.Size(), len(ref))
+	}
+}
@@ -1,6 +1,7 @@
 package tree_test
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -90,3 +91,34 @@ func TestRBTreeRemove(t *testing.T) {
 		CheckBlackHeightProp(tree.Root().(RBNode[int]))
 	}
 }
+
+func TestRBTreeRemoveRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := tree.RB[int]()
+	ref := make(map[int]bool)
+	for i := 0; i < 5000; i++ {
+		value := rng.Intn(500)
+		if rng.Intn(2) == 0 {
+			tree.Insert(value)
+			ref[value] = true
+		} else {
+			tree.Remove(value)
+			delete(ref, value)
+		}
+		if tree.Empty() {
+			continue
+		}
+		root := tree.Root().(RBNode[int])
+		if !CheckBlackRootProp(tree) || !CheckRedChildrenProp(root) || !CheckBlackHeightProp(root) {
+			t.Fatalf("RBTree properties broken after %d operations", i+1)
+		}
+	}
+	if tree.Size() != len(ref) {
+		t.Errorf("RBTree size is %d, expected %d", tree.Size(), len(ref))
+	}
+	for value := range ref {
+		if !tree.Contains(value) {
+			t.Errorf("RBTree does not contain %d", value)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package tree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func sliceMin(data []int, from, to int) int {
+	min := data[from]
+	for i := from + 1; i < to; i++ {
+		if data[i] < min {
+			min = data[i]
+		}
+	}
+	return min
+}
+
+func TestSegmentTree(t *testing.T) {
+	st := tree.Segment[int](list.Arr(5, 2, 8, 1, 9, 3), tree.MinMonoid[int]())
+	if min, _ := st.Query(0, 6); min != 1 {
+		t.Errorf("SegmentTree Query(0, 6) is %d, expected 1", min)
+	}
+	if min, _ := st.Query(4, 6); min != 3 {
+		t.Errorf("SegmentTree Query(4, 6) is %d, expected 3", min)
+	}
+	st.Set(3, 7)
+	if min, _ := st.Query(2, 5); min != 7 {
+		t.Errorf("SegmentTree Query(2, 5) is %d after Set, expected 7", min)
+	}
+	if _, err := st.Query(3, 7); err == nil {
+		t.Errorf("SegmentTree Query out of range returned no error")
+	}
+
+	// combined in index order, so non-commutative monoids work too
+	concat := tree.Monoid[string]{Identity: "", Combine: func(a, b string) string { return a + b }}
+	words := tree.Segment[string](list.Arr("a", "b", "c", "d", "e"), concat)
+	if s, _ := words.Query(1, 4); s != "bcd" {
+		t.Errorf("SegmentTree Query(1, 4) of strings is %q, expected \"bcd\"", s)
+	}
+	if s, _ := words.Query(2, 2); s != "" {
+		t.Errorf("SegmentTree Query of empty range is %q, expected the identity", s)
+	}
+	if max := tree.MaxMonoid[float64]().Identity; max > -1e308 {
+		t.Errorf("MaxMonoid identity is %v, expected -Inf", max)
+	}
+}
+
+func TestLazySegmentTreeRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(35))
+	data := make([]int, 150)
+	for i := range data {
+		data[i] = rnd.Intn(100)
+	}
+	sums := tree.LazySegment[int](list.Arr(data...), tree.SumMonoid[int](), tree.AddToSum[int]())
+	mins := tree.LazySegment[int](list.Arr(data...), tree.MinMonoid[int](), tree.AddToExtreme[int]())
+	for step := 0; step < 2000; step++ {
+		from := rnd.Intn(len(data))
+		to := from + 1 + rnd.Intn(len(data)-from)
+		if sum, _ := sums.Query(from, to); sum != sliceSum(data, from, to) {
+			t.Fatalf("LazySegmentTree sum Query(%d, %d) is %d, expected %d", from, to, sum, sliceSum(data, from, to))
+		}
+		if min, _ := mins.Query(from, to); min != sliceMin(data, from, to) {
+			t.Fatalf("LazySegmentTree min Query(%d, %d) is %d, expected %d", from, to, min, sliceMin(data, from, to))
+		}
+		if step%5 == 0 {
+			index, value := rnd.Intn(len(data)), rnd.Intn(100)
+			data[index] = value
+			sums.Set(index, value)
+			mins.Set(index, value)
+			continue
+		}
+		delta := rnd.Intn(21) - 10
+		sums.Update(from, to, delta)
+		mins.Update(from, to, delta)
+		for i := from; i < to; i++ {
+			data[i] += delta
+		}
+	}
+	if value, _ := sums.Get(42); value != data[42] {
+		t.Errorf("LazySegmentTree Get(42) is %d, expected %d", value, data[42])
+	}
+	if err := sums.Update(0, len(data)+1, 1); err == nil {
+		t.Errorf("LazySegmentTree Update out of range returned no error")
+	}
+}
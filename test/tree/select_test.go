@@ -0,0 +1,57 @@
+package tree_test
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/errs"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func TestSelectAfterUpdates(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	rb, avl := tree.RB[int](), tree.AVL[int]()
+	for name, tr := range map[string]struct {
+		insert, remove func(int)
+		sel            func(int) (*int, error)
+		random         func(*rand.Rand) (*int, error)
+	}{
+		"RBTree":  {func(v int) { rb.Insert(v) }, func(v int) { rb.Remove(v) }, rb.Select, rb.RandomElement},
+		"AVLTree": {func(v int) { avl.Insert(v) }, func(v int) { avl.Remove(v) }, avl.Select, avl.RandomElement},
+	} {
+		if _, err := tr.random(r); !errors.Is(err, errs.ErrEmpty) {
+			t.Errorf("%s.RandomElement() of empty tree returned %v", name, err)
+		}
+		values := make(map[int]bool)
+		for i := 0; i < 500; i++ {
+			v := r.IntN(300)
+			if values[v] {
+				tr.remove(v)
+				delete(values, v)
+			} else {
+				tr.insert(v)
+				values[v] = true
+			}
+		}
+		sorted := make([]int, 0, len(values))
+		for v := range values {
+			sorted = append(sorted, v)
+		}
+		slices.Sort(sorted)
+		for k, want := range sorted {
+			if got, err := tr.sel(k); err != nil || *got != want {
+				t.Fatalf("%s.Select(%d) = %v, %v, want %d", name, k, got, err, want)
+			}
+		}
+		if _, err := tr.sel(len(sorted)); !errors.Is(err, errs.ErrIndexOutOfRange) {
+			t.Errorf("%s.Select(%d) returned %v", name, len(sorted), err)
+		}
+		for i := 0; i < 100; i++ {
+			if v, err := tr.random(r); err != nil || !values[*v] {
+				t.Fatalf("%s.RandomElement() = %v, %v", name, v, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package trie_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/tau"
+	"github.com/luverolla/lexgo/pkg/trie"
+)
+
+var st_keys = []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "r", "", "rom"}
+
+func collect(iter tau.Iterator[string]) []string {
+	res := make([]string, 0)
+	iter.Each(func(s string) {
+		res = append(res, s)
+	})
+	return res
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func build() *trie.StrTrie[int] {
+	st := trie.Str[int]()
+	for i, k := range st_keys {
+		st.Put(k, i)
+	}
+	return st
+}
+
+func TestStrTriePutGet(t *testing.T) {
+	st := build()
+	if st.Size() != len(st_keys) {
+		t.Errorf("StrTrie size is %d, expected %d", st.Size(), len(st_keys))
+	}
+	for i, k := range st_keys {
+		val, err := st.Get(k)
+		if err != nil || *val != i {
+			t.Errorf("StrTrie Get(%q) failed", k)
+		}
+	}
+	if st.HasKey("ro") || st.HasKey("rubiconx") {
+		t.Errorf("StrTrie has keys never put")
+	}
+
+	st.Put("ruber", 100)
+	if st.Size() != len(st_keys) {
+		t.Errorf("StrTrie size is %d after overwrite, expected %d", st.Size(), len(st_keys))
+	}
+}
+
+func TestStrTrieSorted(t *testing.T) {
+	st := build()
+	want := make([]string, len(st_keys))
+	copy(want, st_keys)
+	sort.Slice(want, func(i, j int) bool {
+		return tau.ASCmp(want[i], want[j]) < 0
+	})
+	got := collect(st.Keys())
+	if !equal(got, want) {
+		t.Errorf("StrTrie keys are %v, expected %v", got, want)
+	}
+}
+
+func TestStrTrieRemove(t *testing.T) {
+	st := build()
+	for _, k := range st_keys {
+		if _, err := st.Remove(k); err != nil {
+			t.Errorf("StrTrie Remove(%q) failed", k)
+		}
+		if st.HasKey(k) {
+			t.Errorf("StrTrie still contains key %q", k)
+		}
+	}
+	if !st.Empty() {
+		t.Errorf("StrTrie size is %d, expected %d", st.Size(), 0)
+	}
+	if _, err := st.Remove("r"); err == nil {
+		t.Errorf("StrTrie Remove of missing key did not fail")
+	}
+}
+
+func TestStrTriePrefixes(t *testing.T) {
+	st := build()
+
+	key, val, err := st.LongestPrefixOf("rubicundissimus")
+	if err != nil || key != "r" || *val != 7 {
+		t.Errorf("StrTrie LongestPrefixOf(%q) is %q", "rubicundissimus", key)
+	}
+	key, _, _ = st.LongestPrefixOf("romanesque")
+	if key != "romane" {
+		t.Errorf("StrTrie LongestPrefixOf(%q) is %q, expected %q", "romanesque", key, "romane")
+	}
+
+	got := collect(st.KeysWithPrefix("rub"))
+	want := []string{"rubens", "ruber", "rubicon", "rubicundus"}
+	if !equal(got, want) {
+		t.Errorf("StrTrie KeysWithPrefix(%q) is %v, expected %v", "rub", got, want)
+	}
+	got = collect(st.KeysWithPrefix("roma"))
+	want = []string{"romane", "romanus"}
+	if !equal(got, want) {
+		t.Errorf("StrTrie KeysWithPrefix(%q) is %v, expected %v", "roma", got, want)
+	}
+	if len(collect(st.KeysWithPrefix("x"))) != 0 {
+		t.Errorf("StrTrie KeysWithPrefix(%q) is not empty", "x")
+	}
+
+	walked := 0
+	st.WalkPrefix("ru", func(k string, v int) bool {
+		walked++
+		return walked < 2
+	})
+	if walked != 2 {
+		t.Errorf("StrTrie WalkPrefix did not stop, walked %d entries", walked)
+	}
+
+	if n := st.DeletePrefix("rubi"); n != 2 {
+		t.Errorf("StrTrie DeletePrefix(%q) removed %d keys, expected %d", "rubi", n, 2)
+	}
+	if st.Size() != len(st_keys)-2 || st.HasKey("rubicon") || !st.HasKey("ruber") {
+		t.Errorf("StrTrie is %v after DeletePrefix", st)
+	}
+}
+
+func TestSeqTrie(t *testing.T) {
+	st := trie.Seq[string, int]()
+	st.Put([]string{"GET", "users"}, 1)
+	st.Put([]string{"GET", "users", "id"}, 2)
+	st.Put([]string{"POST", "users"}, 3)
+
+	key, val, err := st.LongestPrefixOf([]string{"GET", "users", "id", "posts"})
+	if err != nil || len(key) != 3 || *val != 2 {
+		t.Errorf("SeqTrie LongestPrefixOf returned %v", key)
+	}
+	count := 0
+	st.KeysWithPrefix([]string{"GET"}).Each(func(k []string) {
+		count++
+	})
+	if count != 2 {
+		t.Errorf("SeqTrie KeysWithPrefix returned %d keys, expected %d", count, 2)
+	}
+
+	clone := st.Clone().(*trie.SeqTrie[string, int])
+	st.Remove([]string{"GET", "users"})
+	if !clone.HasKey([]string{"GET", "users"}) || clone.Cmp(st) <= 0 {
+		t.Errorf("SeqTrie clone was modified by the original")
+	}
+}
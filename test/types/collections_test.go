@@ -0,0 +1,123 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/luverolla/lexgo/pkg/deque"
+	"github.com/luverolla/lexgo/pkg/list"
+	"github.com/luverolla/lexgo/pkg/set"
+	"github.com/luverolla/lexgo/pkg/table"
+	"github.com/luverolla/lexgo/pkg/tau"
+	"github.com/luverolla/lexgo/pkg/tree"
+)
+
+func TestCollCmpAcrossKinds(t *testing.T) {
+	arr := list.Arr(1, 2, 3)
+	rb := set.RB[int]()
+	rb.Add(3, 1, 2)
+	dq := deque.Lkd(1, 2, 3)
+	avl := tree.AVL[int]()
+	for _, v := range []int{2, 3, 1} {
+		avl.Insert(v)
+	}
+	colls := []tau.Collection[int]{arr, list.Lkd(1, 2, 3), rb, dq, deque.Arr(1, 2, 3), avl, set.Bits(1, 2, 3)}
+	for _, a := range colls {
+		for _, b := range colls {
+			if a.Cmp(b) != 0 || !tau.CollEq(a, b) || !tau.Eq(a, b) {
+				t.Errorf("%v and %v are not equal", a, b)
+			}
+		}
+	}
+
+	if c := arr.Cmp(list.Lkd(1, 2, 4)); c >= 0 {
+		t.Errorf("[1 2 3] compared with [1 2 4] = %d, want negative", c)
+	}
+	if c := arr.Cmp(list.Arr(9, 9)); c <= 0 {
+		t.Errorf("[1 2 3] compared with [9 9] = %d, want positive as it's longer", c)
+	}
+	if c := dq.Cmp(list.Arr(1, 2, 3, 4)); c >= 0 {
+		t.Errorf("deque compared with a longer list = %d, want negative", c)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Cmp() with a collection of another type did not panic")
+		}
+	}()
+	arr.Cmp(list.Arr("a", "b", "c"))
+}
+
+func TestMapCmpAndEqual(t *testing.T) {
+	rb, hsh, avl := table.RB[string, int](), table.Hsh[string, int](), table.AVL[string, int]()
+	for i, key := range []string{"d", "a", "c", "b"} {
+		rb.Put(key, i)
+		hsh.Put(key, i)
+		avl.Put(key, i)
+	}
+	if rb.Cmp(avl) != 0 || !tau.MapEqual[string, int](rb, avl) {
+		t.Errorf("%v and %v are not equal", rb, avl)
+	}
+	if !tau.MapEqual[string, int](rb, hsh) || !tau.SetEqual[string](rb, hsh) {
+		t.Errorf("%v and %v are not equal ignoring the order", rb, hsh)
+	}
+	// same keys, in the same order, but different values
+	other := table.AVL[string, int]()
+	for i, key := range []string{"d", "a", "c", "b"} {
+		other.Put(key, i*10)
+	}
+	if rb.Cmp(other) != 0 {
+		t.Errorf("%v and %v don't compare equal, though Cmp ignores the values", rb, other)
+	}
+	if tau.MapEqual[string, int](rb, other) {
+		t.Errorf("%v and %v are equal despite the different values", rb, other)
+	}
+	if !tau.SetEqual[string](rb, other) {
+		t.Errorf("%v and %v don't have the same keys", rb, other)
+	}
+	// a map is equal to a collection of its keys
+	if rb.Cmp(list.Arr("a", "b", "c", "d")) != 0 {
+		t.Errorf("%v is not equal to the list of its keys", rb)
+	}
+	avl.Put("e", 0)
+	if tau.SetEqual[string](rb, avl) || tau.MapEqual[string, int](rb, avl) {
+		t.Errorf("%v and %v are equal despite the different sizes", rb, avl)
+	}
+}
+
+// a plain struct, which is not tau.Comparable
+type rec struct {
+	id string
+}
+
+func TestMapCmpWithPlainValues(t *testing.T) {
+	a, b := table.RB[string, rec](), table.Hsh[string, rec]()
+	a.Put("x", rec{"1"})
+	b.Put("x", rec{"1"})
+	other := table.RB[string, rec]()
+	other.Put("x", rec{"1"})
+	if a.Cmp(other) != 0 || !tau.Eq(a, other) || a.Cmp(b) != 0 {
+		t.Errorf("%v and %v with the same keys are not equal", a, other)
+	}
+	byID := func(x, y rec) int { return tau.ASCmp(x.id, y.id) }
+	if !tau.MapEqualBy[string, rec](a, b, byID) {
+		t.Errorf("%v and %v are not equal by id", a, b)
+	}
+	b.Put("y", rec{"2"})
+	other.Put("y", rec{"3"})
+	if tau.MapEqualBy[string, rec](b, other, byID) {
+		t.Errorf("%v and %v are equal despite the different ids", b, other)
+	}
+}
+
+func TestSetEqual(t *testing.T) {
+	hsh := set.Hsh[int]()
+	hsh.Add(5, 1, 4, 2)
+	skip := set.Skip[int]()
+	skip.Add(1, 2, 4, 5)
+	if !tau.SetEqual[int](hsh, skip) || !tau.SetEqual[int](skip, set.Bits(4, 5, 2, 1)) {
+		t.Errorf("%v and %v are not equal ignoring the order", hsh, skip)
+	}
+	skip.Add(3)
+	if tau.SetEqual[int](hsh, skip) {
+		t.Errorf("%v and %v are equal despite the different elements", hsh, skip)
+	}
+}